@@ -2,6 +2,7 @@ package main
 
 import (
 	"MicroShopik/internal/services/domain"
+	"context"
 	"log"
 	"time"
 )
@@ -9,20 +10,24 @@ import (
 func main() {
 	log.Println("Testing keep-alive service...")
 
-	// Создаем сервис с интервалом 30 секунд для тестирования
-	keepAliveService := domain.NewKeepAliveService(
-		"http://localhost:8080",
-		30*time.Second,
-	)
+	keepAliveService := domain.NewKeepAliveService("http://localhost:8080")
 
-	// Запускаем сервис
-	keepAliveService.Start()
+	// Пингуем каждые 30 секунд в течение 2 минут для демонстрации
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+	deadline := time.After(2 * time.Minute)
 
-	// Ждем 2 минуты для демонстрации
-	log.Println("Keep-alive service is running. Press Ctrl+C to stop...")
-	time.Sleep(2 * time.Minute)
+	log.Println("Keep-alive service is running...")
+	for {
+		if err := keepAliveService.Ping(context.Background()); err != nil {
+			log.Println(err)
+		}
 
-	// Останавливаем сервис
-	keepAliveService.Stop()
-	log.Println("Test completed.")
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			log.Println("Test completed.")
+			return
+		}
+	}
 }
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"MicroShopik/configs"
+	"MicroShopik/internal/container"
+	"MicroShopik/internal/database"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// worker runs queued background jobs outside the API server. Run the server with
+// JOB_WORKERS_IN_SERVER=false when jobs should only run here.
+func main() {
+	cfg, err := configs.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = database.InitDB(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize database:", err)
+	}
+
+	newContainer := container.NewContainer()
+	defer newContainer.RealtimeHub.Close()
+	defer newContainer.EventBus.Close()
+
+	newContainer.JobWorkerPool.Start()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	newContainer.JobWorkerPool.Stop()
+}
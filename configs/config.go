@@ -5,6 +5,7 @@ import (
 	"github.com/joho/godotenv"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
@@ -16,9 +17,56 @@ type Config struct {
 	DBName     string `json:"DBName"`
 	JWTSecret  string `json:"-"`
 
+	AccessTokenTTLMinutes int `json:"AccessTokenTTLMinutes"`
+	RefreshTokenTTLDays   int `json:"RefreshTokenTTLDays"`
+
+	EmailVerificationTTLHours int `json:"EmailVerificationTTLHours"`
+
 	KeepAliveEnabled  bool   `json:"KeepAliveEnabled"`
 	KeepAliveURL      string `json:"KeepAliveURL"`
 	KeepAliveInterval int    `json:"KeepAliveInterval"`
+
+	ProductModerationEnabled    bool `json:"ProductModerationEnabled"`
+	ProductImportAsyncThreshold int  `json:"ProductImportAsyncThreshold"`
+
+	RecommendationsInterval int `json:"RecommendationsInterval"`
+
+	SiteBaseURL     string `json:"SiteBaseURL"`
+	PublicAPIURL    string `json:"PublicAPIURL"`
+	SitemapPageSize int    `json:"SitemapPageSize"`
+
+	AttachmentsDir      string `json:"AttachmentsDir"`
+	AttachmentMaxSizeMB int    `json:"AttachmentMaxSizeMB"`
+
+	MessageEditWindowMinutes int `json:"MessageEditWindowMinutes"`
+
+	ChatFilterPhone       string   `json:"ChatFilterPhone"`
+	ChatFilterEmail       string   `json:"ChatFilterEmail"`
+	ChatFilterURL         string   `json:"ChatFilterURL"`
+	ChatFilterBannedWords string   `json:"ChatFilterBannedWords"`
+	ChatBannedWords       []string `json:"ChatBannedWords"`
+
+	OutboxPollIntervalSeconds int `json:"OutboxPollIntervalSeconds"`
+	OutboxMaxAttempts         int `json:"OutboxMaxAttempts"`
+
+	MailTransport           string `json:"MailTransport"`
+	MailFrom                string `json:"MailFrom"`
+	MailDir                 string `json:"MailDir"`
+	SMTPHost                string `json:"SMTPHost"`
+	SMTPPort                int    `json:"SMTPPort"`
+	SMTPUsername            string `json:"SMTPUsername"`
+	SMTPPassword            string `json:"-"`
+	UnreadEmailDelayMinutes int    `json:"UnreadEmailDelayMinutes"`
+
+	WebhookPollIntervalSeconds int  `json:"WebhookPollIntervalSeconds"`
+	WebhookTimeoutSeconds      int  `json:"WebhookTimeoutSeconds"`
+	WebhookMaxAttempts         int  `json:"WebhookMaxAttempts"`
+	WebhookAllowPrivateHosts   bool `json:"WebhookAllowPrivateHosts"`
+
+	JobWorkers             int  `json:"JobWorkers"`
+	JobWorkersInServer     bool `json:"JobWorkersInServer"`
+	JobPollIntervalSeconds int  `json:"JobPollIntervalSeconds"`
+	JobMaxAttempts         int  `json:"JobMaxAttempts"`
 }
 
 func Load() (*Config, error) {
@@ -36,13 +84,111 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("DB_PASSWORD environment variable is required")
 	}
 
+	accessTokenTTL, err := strconv.Atoi(getEnv("ACCESS_TOKEN_TTL_MINUTES", "15"))
+	if err != nil || accessTokenTTL <= 0 {
+		accessTokenTTL = 15
+	}
+
+	refreshTokenTTL, err := strconv.Atoi(getEnv("REFRESH_TOKEN_TTL_DAYS", "30"))
+	if err != nil || refreshTokenTTL <= 0 {
+		refreshTokenTTL = 30 // counted from the last refresh
+	}
+
 	keepAliveEnabled := getEnv("KEEP_ALIVE_ENABLED", "true") == "true"
 	keepAliveURL := getEnv("KEEP_ALIVE_URL", "http://localhost:8080")
 	keepAliveInterval, err := strconv.Atoi(getEnv("KEEP_ALIVE_INTERVAL", "10"))
-	if err != nil {
+	if err != nil || keepAliveInterval <= 0 {
 		keepAliveInterval = 10 // default to 10 minutes
 	}
 
+	productModerationEnabled := getEnv("PRODUCT_MODERATION_ENABLED", "false") == "true"
+	productImportAsyncThreshold, err := strconv.Atoi(getEnv("PRODUCT_IMPORT_ASYNC_THRESHOLD", "100"))
+	if err != nil {
+		productImportAsyncThreshold = 100
+	}
+
+	recommendationsInterval, err := strconv.Atoi(getEnv("RECOMMENDATIONS_INTERVAL", "60"))
+	if err != nil || recommendationsInterval <= 0 {
+		recommendationsInterval = 60 // default to hourly rebuilds
+	}
+
+	sitemapPageSize, err := strconv.Atoi(getEnv("SITEMAP_PAGE_SIZE", "50000"))
+	if err != nil {
+		sitemapPageSize = 50000 // protocol maximum per sitemap file
+	}
+
+	attachmentMaxSizeMB, err := strconv.Atoi(getEnv("ATTACHMENT_MAX_SIZE_MB", "10"))
+	if err != nil || attachmentMaxSizeMB <= 0 {
+		attachmentMaxSizeMB = 10
+	}
+
+	messageEditWindow, err := strconv.Atoi(getEnv("MESSAGE_EDIT_WINDOW_MINUTES", "15"))
+	if err != nil || messageEditWindow < 0 {
+		messageEditWindow = 15
+	}
+
+	outboxPollInterval, err := strconv.Atoi(getEnv("OUTBOX_POLL_INTERVAL_SECONDS", "2"))
+	if err != nil || outboxPollInterval <= 0 {
+		outboxPollInterval = 2
+	}
+
+	outboxMaxAttempts, err := strconv.Atoi(getEnv("OUTBOX_MAX_ATTEMPTS", "8"))
+	if err != nil || outboxMaxAttempts <= 0 {
+		outboxMaxAttempts = 8 // about two hours of retries with the relay's backoff
+	}
+
+	smtpPort, err := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	if err != nil {
+		smtpPort = 587
+	}
+
+	unreadEmailDelay, err := strconv.Atoi(getEnv("UNREAD_EMAIL_DELAY_MINUTES", "30"))
+	if err != nil || unreadEmailDelay < 0 {
+		unreadEmailDelay = 30 // 0 disables unread message emails
+	}
+
+	webhookPollInterval, err := strconv.Atoi(getEnv("WEBHOOK_POLL_INTERVAL_SECONDS", "5"))
+	if err != nil || webhookPollInterval <= 0 {
+		webhookPollInterval = 5
+	}
+
+	webhookTimeout, err := strconv.Atoi(getEnv("WEBHOOK_TIMEOUT_SECONDS", "10"))
+	if err != nil || webhookTimeout <= 0 {
+		webhookTimeout = 10
+	}
+
+	webhookMaxAttempts, err := strconv.Atoi(getEnv("WEBHOOK_MAX_ATTEMPTS", "10"))
+	if err != nil || webhookMaxAttempts <= 0 {
+		webhookMaxAttempts = 10 // about eight and a half hours of retries with the dispatcher's backoff
+	}
+
+	jobWorkers, err := strconv.Atoi(getEnv("JOB_WORKERS", "4"))
+	if err != nil || jobWorkers <= 0 {
+		jobWorkers = 4
+	}
+
+	jobPollInterval, err := strconv.Atoi(getEnv("JOB_POLL_INTERVAL_SECONDS", "1"))
+	if err != nil || jobPollInterval <= 0 {
+		jobPollInterval = 1
+	}
+
+	jobMaxAttempts, err := strconv.Atoi(getEnv("JOB_MAX_ATTEMPTS", "5"))
+	if err != nil || jobMaxAttempts <= 0 {
+		jobMaxAttempts = 5
+	}
+
+	emailVerificationTTL, err := strconv.Atoi(getEnv("EMAIL_VERIFICATION_TTL_HOURS", "48"))
+	if err != nil || emailVerificationTTL <= 0 {
+		emailVerificationTTL = 48
+	}
+
+	var chatBannedWords []string
+	for _, word := range strings.Split(getEnv("CHAT_BANNED_WORDS", ""), ",") {
+		if word = strings.TrimSpace(word); word != "" {
+			chatBannedWords = append(chatBannedWords, word)
+		}
+	}
+
 	return &Config{
 		DBHost:     getEnv("DB_HOST", "localhost"),
 		DBPort:     dbPort,
@@ -51,9 +197,56 @@ func Load() (*Config, error) {
 		DBName:     getEnv("DB_NAME", "microshopik"),
 		JWTSecret:  jwtSecret,
 
+		AccessTokenTTLMinutes: accessTokenTTL,
+		RefreshTokenTTLDays:   refreshTokenTTL,
+
+		EmailVerificationTTLHours: emailVerificationTTL,
+
 		KeepAliveEnabled:  keepAliveEnabled,
 		KeepAliveURL:      keepAliveURL,
 		KeepAliveInterval: keepAliveInterval,
+
+		ProductModerationEnabled:    productModerationEnabled,
+		ProductImportAsyncThreshold: productImportAsyncThreshold,
+
+		RecommendationsInterval: recommendationsInterval,
+
+		SiteBaseURL:     getEnv("SITE_BASE_URL", "http://localhost:8080"),
+		PublicAPIURL:    getEnv("PUBLIC_API_URL", "http://localhost:8080"),
+		SitemapPageSize: sitemapPageSize,
+
+		AttachmentsDir:      getEnv("ATTACHMENTS_DIR", "uploads/attachments"),
+		AttachmentMaxSizeMB: attachmentMaxSizeMB,
+
+		MessageEditWindowMinutes: messageEditWindow,
+
+		ChatFilterPhone:       getEnv("CHAT_FILTER_PHONE", "mask"),
+		ChatFilterEmail:       getEnv("CHAT_FILTER_EMAIL", "mask"),
+		ChatFilterURL:         getEnv("CHAT_FILTER_URL", "flag"),
+		ChatFilterBannedWords: getEnv("CHAT_FILTER_BANNED_WORDS", "block"),
+		ChatBannedWords:       chatBannedWords,
+
+		OutboxPollIntervalSeconds: outboxPollInterval,
+		OutboxMaxAttempts:         outboxMaxAttempts,
+
+		MailTransport:           getEnv("MAIL_TRANSPORT", "file"),
+		MailFrom:                getEnv("MAIL_FROM", "MicroShopik <no-reply@microshopik.local>"),
+		MailDir:                 getEnv("MAIL_DIR", "tmp/mail"),
+		SMTPHost:                getEnv("SMTP_HOST", ""),
+		SMTPPort:                smtpPort,
+		SMTPUsername:            getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:            getEnv("SMTP_PASSWORD", ""),
+		UnreadEmailDelayMinutes: unreadEmailDelay,
+
+		WebhookPollIntervalSeconds: webhookPollInterval,
+		WebhookTimeoutSeconds:      webhookTimeout,
+		WebhookMaxAttempts:         webhookMaxAttempts,
+		WebhookAllowPrivateHosts:   getEnv("WEBHOOK_ALLOW_PRIVATE_HOSTS", "false") == "true",
+
+		JobWorkers:             jobWorkers,
+		JobWorkersInServer:     getEnv("JOB_WORKERS_IN_SERVER", "true") == "true", // set to false when running cmd/worker
+		JobPollIntervalSeconds: jobPollInterval,
+		JobMaxAttempts:         jobMaxAttempts,
 	}, nil
 }
 func getEnv(key, defaultValue string) string {
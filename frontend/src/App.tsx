@@ -7,9 +7,11 @@ import ProtectedRoute from '@/components/common/ProtectedRoute'
 // Public pages
 import LoginPage from '@/pages/LoginPage'
 import RegisterPage from '@/pages/RegisterPage'
+import VerifyEmailPage from '@/pages/VerifyEmailPage'
 import HomePage from '@/pages/HomePage'
 import ProductsPage from '@/pages/ProductsPage'
 import ProductDetailPage from '@/pages/ProductDetailPage'
+import CategoryPage from '@/pages/CategoryPage'
 import NotFoundPage from '@/pages/NotFoundPage'
 
 // Protected pages
@@ -41,11 +43,13 @@ const App: React.FC = () => {
         <Route path="/" element={<HomePage />} />
         <Route path="/login" element={<LoginPage />} />
         <Route path="/register" element={<RegisterPage />} />
+        <Route path="/verify-email" element={<VerifyEmailPage />} />
 
         {/* Routes with layout (includes header) */}
         <Route path="/" element={<Layout />}>
           <Route path="/products" element={<ProductsPage />} />
           <Route path="/products/:id" element={<ProductDetailPage />} />
+          <Route path="/categories/:slug" element={<CategoryPage />} />
           
           {/* Protected routes */}
           <Route path="/profile" element={
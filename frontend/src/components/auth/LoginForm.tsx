@@ -29,7 +29,7 @@ const LoginForm = () => {
 
     try {
       const response = await apiService.login(data);
-      login(response.user, response.token);
+      login(response.user, response.token, response.refresh_token);
       navigate('/');
     } catch (err: unknown) {
       const message =
@@ -0,0 +1,68 @@
+import React, { useEffect, useState } from 'react'
+import { Monitor, LogOut } from 'lucide-react'
+import { Session } from '@/types'
+import { apiService } from '@/services/api'
+
+// Devices the user is logged in on, with a way to log any of them out.
+const ActiveSessions: React.FC = () => {
+  const [sessions, setSessions] = useState<Session[] | null>(null)
+  const [message, setMessage] = useState('')
+
+  useEffect(() => {
+    apiService
+      .getSessions()
+      .then(setSessions)
+      .catch(() => setMessage('Failed to load sessions'))
+  }, [])
+
+  if (!sessions) {
+    return message ? <p className="text-sm text-red-600">{message}</p> : null
+  }
+
+  const revoke = async (id: string) => {
+    setMessage('')
+    try {
+      await apiService.revokeSession(id)
+      setSessions(sessions.filter((session) => session.id !== id))
+    } catch (e: any) {
+      setMessage(e?.response?.data?.error || 'Failed to log out the session')
+    }
+  }
+
+  return (
+    <div className="bg-white rounded-lg shadow-sm border border-gray-200 p-6 mt-6 dark:bg-gray-900 dark:border-gray-800">
+      <h2 className="flex items-center gap-2 text-xl font-semibold text-gray-900 mb-6 dark:text-gray-100">
+        <Monitor className="h-5 w-5" /> Active Sessions
+      </h2>
+
+      <ul className="divide-y divide-gray-100 dark:divide-gray-800">
+        {sessions.map((session) => (
+          <li key={session.id} className="flex items-center justify-between py-3 text-sm">
+            <div>
+              <p className="text-gray-900 dark:text-gray-100">
+                {session.user_agent || 'Unknown device'}
+                {session.current && <span className="ml-2 text-xs text-green-600">This device</span>}
+              </p>
+              <p className="text-gray-500 dark:text-gray-400">
+                {session.ip} · signed in {new Date(session.started_at).toLocaleString()} · last active{' '}
+                {new Date(session.last_used_at).toLocaleString()}
+              </p>
+            </div>
+            {!session.current && (
+              <button
+                onClick={() => revoke(session.id)}
+                className="flex items-center gap-1 text-red-600 hover:text-red-700"
+              >
+                <LogOut className="h-4 w-4" /> Log out
+              </button>
+            )}
+          </li>
+        ))}
+      </ul>
+
+      {message && <p className="text-sm text-gray-600 mt-4 dark:text-gray-400">{message}</p>}
+    </div>
+  )
+}
+
+export default ActiveSessions
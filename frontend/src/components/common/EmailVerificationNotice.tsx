@@ -0,0 +1,46 @@
+import React, { useState } from 'react'
+import { AlertTriangle } from 'lucide-react'
+import { User } from '@/types'
+import { apiService } from '@/services/api'
+
+// Reminds users with an unconfirmed email to verify it, with a way to get a new link.
+const EmailVerificationNotice: React.FC<{ user: User }> = ({ user }) => {
+  const [isSending, setIsSending] = useState(false)
+  const [message, setMessage] = useState('')
+
+  if (user.email_verified_at) return null
+
+  const resend = async () => {
+    setIsSending(true)
+    setMessage('')
+    try {
+      await apiService.resendVerification(user.email)
+      setMessage(`A new verification link has been sent to ${user.email}.`)
+    } catch (e: any) {
+      setMessage(e?.response?.data?.error || 'Failed to send a verification link')
+    } finally {
+      setIsSending(false)
+    }
+  }
+
+  return (
+    <div className="bg-yellow-50 border border-yellow-200 text-yellow-800 px-4 py-3 rounded-md dark:bg-yellow-950 dark:border-yellow-900 dark:text-yellow-200">
+      <div className="flex items-center justify-between gap-4">
+        <p className="flex items-center gap-2 text-sm">
+          <AlertTriangle className="h-5 w-5 flex-shrink-0" />
+          Please verify your email address. You need a verified email to place orders or sell products.
+        </p>
+        <button
+          onClick={resend}
+          disabled={isSending}
+          className="text-sm font-medium whitespace-nowrap hover:underline disabled:opacity-50"
+        >
+          {isSending ? 'Sending...' : 'Resend link'}
+        </button>
+      </div>
+      {message && <p className="text-sm mt-2">{message}</p>}
+    </div>
+  )
+}
+
+export default EmailVerificationNotice
@@ -3,6 +3,7 @@ import { Link, useNavigate } from 'react-router-dom'
 import { useAuthStore } from '@/store/authStore'
 import { ShoppingBag, User, LogOut, Menu, X, MessageCircle, Home, Package } from 'lucide-react'
 import ThemeToggle from '@/components/ui/ThemeToggle'
+import NotificationBell from '@/components/common/NotificationBell'
 import { Category } from '@/types'
 import { apiService } from '@/services/api'
 
@@ -14,6 +15,7 @@ const Header: React.FC = () => {
   const [isLoadingCategories, setIsLoadingCategories] = useState(false)
 
   const handleLogout = () => {
+    apiService.logout()
     logout()
     navigate('/login')
   }
@@ -115,6 +117,7 @@ const Header: React.FC = () => {
             <div className="hidden md:block"><ThemeToggle /></div>
             {isAuthenticated ? (
               <div className="hidden md:flex items-center space-x-3">
+                <NotificationBell />
                 <Link
                   to="/profile"
                   className="flex items-center space-x-2 text-gray-700 hover:text-blue-600 transition-colors dark:text-gray-300 dark:hover:text-blue-400"
@@ -0,0 +1,143 @@
+import React, { useCallback, useEffect, useRef, useState } from 'react'
+import { useNavigate } from 'react-router-dom'
+import { Bell } from 'lucide-react'
+import { Notification } from '@/types'
+import { apiService } from '@/services/api'
+import { RealtimeEvent } from '@/services/realtime'
+import { useAutoRefresh, useRealtime } from '@/hooks'
+
+const PAGE_SIZE = 10
+
+const describe = (notification: Notification): string => {
+  const p = notification.payload as Record<string, string | number | undefined>
+  switch (notification.type) {
+    case 'order_status':
+      return p.old_status
+        ? `Order #${p.order_id} (${p.product_title ?? 'product'}) is now ${p.status}`
+        : `New order #${p.order_id} for ${p.product_title ?? 'your product'}`
+    case 'new_message':
+      return `New message from ${p.sender_name ?? 'a user'}`
+    case 'product_moderation':
+      return p.action === 'approved'
+        ? `"${p.product_title}" was approved`
+        : `"${p.product_title}" was rejected${p.reason ? `: ${p.reason}` : ''}`
+    case 'message_moderation':
+      return `A moderator acted on your message (${String(p.resolution).replace(/_/g, ' ')})`
+    default:
+      return 'Notification'
+  }
+}
+
+// Bell with an unread badge for the header. New items arrive over the realtime socket;
+// while it is down the count is polled instead.
+const NotificationBell: React.FC = () => {
+  const navigate = useNavigate()
+  const [isOpen, setIsOpen] = useState(false)
+  const [notifications, setNotifications] = useState<Notification[]>([])
+  const [unread, setUnread] = useState(0)
+  const containerRef = useRef<HTMLDivElement>(null)
+
+  const load = useCallback(async () => {
+    try {
+      const page = await apiService.getNotifications({ limit: PAGE_SIZE })
+      setNotifications(page.notifications)
+      setUnread(page.unread)
+    } catch (e) {
+      // ignore, the badge is not essential
+    }
+  }, [])
+
+  useEffect(() => {
+    load()
+  }, [load])
+
+  const isConnected = useRealtime((event: RealtimeEvent) => {
+    if (event.type === 'notification.created') {
+      load()
+    }
+  })
+
+  useAutoRefresh({ interval: 60000, enabled: !isConnected, onRefresh: load })
+
+  useEffect(() => {
+    if (!isOpen) return
+    const handleClick = (e: MouseEvent) => {
+      if (containerRef.current && !containerRef.current.contains(e.target as Node)) {
+        setIsOpen(false)
+      }
+    }
+    document.addEventListener('mousedown', handleClick)
+    return () => document.removeEventListener('mousedown', handleClick)
+  }, [isOpen])
+
+  const openNotification = async (notification: Notification) => {
+    setIsOpen(false)
+    if (!notification.is_read) {
+      setNotifications((items) => items.map((n) => (n.id === notification.id ? { ...n, is_read: true } : n)))
+      setUnread((count) => Math.max(0, count - 1))
+      apiService.markNotificationRead(notification.id).catch(() => load())
+    }
+    if (notification.link) navigate(notification.link)
+  }
+
+  const markAllRead = async () => {
+    try {
+      await apiService.markAllNotificationsRead()
+      setNotifications((items) => items.map((n) => ({ ...n, is_read: true })))
+      setUnread(0)
+    } catch (e) {
+      load()
+    }
+  }
+
+  return (
+    <div className="relative" ref={containerRef}>
+      <button
+        onClick={() => setIsOpen(!isOpen)}
+        className="relative p-1 text-gray-700 hover:text-blue-600 transition-colors dark:text-gray-300 dark:hover:text-blue-400"
+        aria-label="Notifications"
+      >
+        <Bell className="w-5 h-5" />
+        {unread > 0 && (
+          <span className="absolute -top-1 -right-1 min-w-[1.1rem] h-[1.1rem] px-1 rounded-full bg-red-600 text-white text-[10px] font-semibold flex items-center justify-center">
+            {unread > 99 ? '99+' : unread}
+          </span>
+        )}
+      </button>
+
+      {isOpen && (
+        <div className="absolute right-0 mt-2 w-80 bg-white border border-gray-200 rounded-md shadow-lg z-50 dark:bg-gray-900 dark:border-gray-800">
+          <div className="flex items-center justify-between px-4 py-2 border-b border-gray-200 dark:border-gray-800">
+            <span className="text-sm font-semibold text-gray-900 dark:text-gray-100">Notifications</span>
+            {unread > 0 && (
+              <button onClick={markAllRead} className="text-xs text-blue-600 hover:underline dark:text-blue-400">
+                Mark all as read
+              </button>
+            )}
+          </div>
+          {notifications.length === 0 ? (
+            <div className="px-4 py-6 text-sm text-center text-gray-500 dark:text-gray-400">No notifications yet</div>
+          ) : (
+            <ul className="max-h-96 overflow-y-auto">
+              {notifications.map((notification) => (
+                <li key={notification.id}>
+                  <button
+                    onClick={() => openNotification(notification)}
+                    className={`w-full text-left px-4 py-3 text-sm hover:bg-gray-50 dark:hover:bg-gray-800 ${
+                      notification.is_read ? 'text-gray-500 dark:text-gray-400' : 'text-gray-900 font-medium dark:text-gray-100'
+                    }`}
+                  >
+                    <div>{describe(notification)}</div>
+                    <div className="text-xs text-gray-400 mt-1">{new Date(notification.created_at).toLocaleString()}</div>
+                  </button>
+                </li>
+              ))}
+            </ul>
+          )}
+        </div>
+      )}
+    </div>
+  )
+}
+
+export default NotificationBell
@@ -0,0 +1,178 @@
+import React, { useEffect, useState } from 'react'
+import { Bell, Save } from 'lucide-react'
+import { NotificationChannel, NotificationPreferences } from '@/types'
+import { apiService } from '@/services/api'
+
+const EVENT_LABELS: Record<string, string> = {
+  order_status: 'Orders',
+  new_message: 'Chat messages',
+  product_moderation: 'Product moderation',
+  message_moderation: 'Message moderation',
+}
+
+const CHANNELS: { key: NotificationChannel; label: string }[] = [
+  { key: 'in_app', label: 'In-app' },
+  { key: 'email', label: 'Email' },
+  { key: 'webhook', label: 'Webhook' },
+]
+
+// Per-event and per-channel switches, quiet hours and email digest frequency.
+const NotificationSettings: React.FC = () => {
+  const [preferences, setPreferences] = useState<NotificationPreferences | null>(null)
+  const [isSaving, setIsSaving] = useState(false)
+  const [message, setMessage] = useState('')
+
+  useEffect(() => {
+    apiService
+      .getNotificationPreferences()
+      .then((data) => {
+        // Suggest the browser's timezone until the user saved one
+        if (data.updated_at.startsWith('0001-') && data.timezone === 'UTC') {
+          data.timezone = Intl.DateTimeFormat().resolvedOptions().timeZone || 'UTC'
+        }
+        setPreferences(data)
+      })
+      .catch(() => setMessage('Failed to load notification settings'))
+  }, [])
+
+  if (!preferences) {
+    return message ? <p className="text-sm text-red-600">{message}</p> : null
+  }
+
+  const toggle = (eventType: string, channel: NotificationChannel, enabled: boolean) => {
+    setPreferences({
+      ...preferences,
+      channels: { ...preferences.channels, [eventType]: { ...preferences.channels[eventType], [channel]: enabled } },
+    })
+  }
+
+  const save = async () => {
+    setIsSaving(true)
+    setMessage('')
+    try {
+      setPreferences(await apiService.updateNotificationPreferences(preferences))
+      setMessage('Notification settings saved')
+    } catch (e: any) {
+      setMessage(e?.response?.data?.error || 'Failed to save notification settings')
+    } finally {
+      setIsSaving(false)
+    }
+  }
+
+  const quietHoursEnabled = preferences.quiet_hours_start !== ''
+
+  return (
+    <div id="notification-settings" className="bg-white rounded-lg shadow-sm border border-gray-200 p-6 mt-6 dark:bg-gray-900 dark:border-gray-800">
+      <h2 className="flex items-center gap-2 text-xl font-semibold text-gray-900 mb-6 dark:text-gray-100">
+        <Bell className="h-5 w-5" /> Notification Settings
+      </h2>
+
+      <table className="w-full text-sm mb-6">
+        <thead>
+          <tr className="text-left text-gray-500 dark:text-gray-400">
+            <th className="py-2 font-medium">Event</th>
+            {CHANNELS.map((channel) => (
+              <th key={channel.key} className="py-2 font-medium text-center">{channel.label}</th>
+            ))}
+          </tr>
+        </thead>
+        <tbody>
+          {Object.keys(EVENT_LABELS).filter((eventType) => preferences.channels[eventType]).map((eventType) => (
+            <tr key={eventType} className="border-t border-gray-100 dark:border-gray-800">
+              <td className="py-2 text-gray-900 dark:text-gray-100">{EVENT_LABELS[eventType]}</td>
+              {CHANNELS.map((channel) => {
+                const value = preferences.channels[eventType]?.[channel.key]
+                return (
+                  <td key={channel.key} className="py-2 text-center">
+                    {value === undefined ? (
+                      <span className="text-gray-300 dark:text-gray-700">—</span>
+                    ) : (
+                      <input
+                        type="checkbox"
+                        className="h-4 w-4"
+                        checked={value}
+                        onChange={(e) => toggle(eventType, channel.key, e.target.checked)}
+                      />
+                    )}
+                  </td>
+                )
+              })}
+            </tr>
+          ))}
+        </tbody>
+      </table>
+
+      <div className="grid grid-cols-1 md:grid-cols-3 gap-4 mb-6">
+        <label className="block text-sm">
+          <span className="block text-gray-500 mb-1 dark:text-gray-400">Timezone</span>
+          <input
+            type="text"
+            className="input w-full"
+            value={preferences.timezone}
+            onChange={(e) => setPreferences({ ...preferences, timezone: e.target.value })}
+          />
+        </label>
+        <div className="block text-sm">
+          <label className="flex items-center gap-2 text-gray-500 mb-1 dark:text-gray-400">
+            <input
+              type="checkbox"
+              checked={quietHoursEnabled}
+              onChange={(e) =>
+                setPreferences({
+                  ...preferences,
+                  quiet_hours_start: e.target.checked ? '22:00' : '',
+                  quiet_hours_end: e.target.checked ? '08:00' : '',
+                })
+              }
+            />
+            Quiet hours
+          </label>
+          {quietHoursEnabled && (
+            <div className="flex items-center gap-2">
+              <input
+                type="time"
+                className="input"
+                value={preferences.quiet_hours_start}
+                onChange={(e) => setPreferences({ ...preferences, quiet_hours_start: e.target.value })}
+              />
+              <span className="text-gray-500">to</span>
+              <input
+                type="time"
+                className="input"
+                value={preferences.quiet_hours_end}
+                onChange={(e) => setPreferences({ ...preferences, quiet_hours_end: e.target.value })}
+              />
+            </div>
+          )}
+        </div>
+        <label className="block text-sm">
+          <span className="block text-gray-500 mb-1 dark:text-gray-400">Email digest</span>
+          <select
+            className="input w-full"
+            value={preferences.digest_frequency}
+            onChange={(e) =>
+              setPreferences({ ...preferences, digest_frequency: e.target.value as NotificationPreferences['digest_frequency'] })
+            }
+          >
+            <option value="off">Off, email me right away</option>
+            <option value="daily">Daily summary</option>
+            <option value="weekly">Weekly summary</option>
+          </select>
+        </label>
+      </div>
+
+      <div className="flex items-center gap-4">
+        <button
+          onClick={save}
+          disabled={isSaving}
+          className="flex items-center gap-2 bg-blue-600 text-white px-4 py-2 rounded-md text-sm font-medium hover:bg-blue-700 disabled:opacity-50"
+        >
+          <Save className="h-4 w-4" /> {isSaving ? 'Saving...' : 'Save'}
+        </button>
+        {message && <span className="text-sm text-gray-600 dark:text-gray-400">{message}</span>}
+      </div>
+    </div>
+  )
+}
+
+export default NotificationSettings
@@ -26,14 +26,13 @@ const ConversationList: React.FC<ConversationListProps> = ({
   // Use external conversations if provided
   const displayConversations = externalConversations || [];
 
-  // Fetch order statuses per conversation using first message's order_id
+  // Fetch order statuses for conversations linked to an order
   useEffect(() => {
     const pending: Array<{ convId: number; orderId: number }> = [];
     for (const conv of displayConversations) {
-      if (!conv || !conv.messages || conv.messages.length === 0) continue;
-      const withOrder = conv.messages.find(m => typeof m.order_id === 'number' && m.order_id > 0);
-      if (withOrder && orderStatusByConv[conv.id] === undefined) {
-        pending.push({ convId: conv.id, orderId: withOrder.order_id as number });
+      if (!conv || !conv.order_id) continue;
+      if (orderStatusByConv[conv.id] === undefined) {
+        pending.push({ convId: conv.id, orderId: conv.order_id });
       }
     }
     if (pending.length === 0) return;
@@ -115,44 +114,15 @@ const ConversationList: React.FC<ConversationListProps> = ({
     );
   };
 
-  const getProductFromFirstMessage = (conversation: Conversation) => {
-    const first = conversation.messages && conversation.messages[0];
-    if (!first || !first.text) return null;
-    // Expecting pattern: "Purchase: product name"
-    const match = first.text.match(/^Purchase:\s*(.+)$/i);
-    return match ? match[1] : null;
-  };
-
-  const getOrderStatusFromMessages = (conversation: Conversation): string | null => {
-    // Prefer last message with apparent status keyword
-    const reversed = [...conversation.messages].reverse();
-    for (const m of reversed) {
-      const t = (m.text || '').toLowerCase();
-      if (t.includes('confirmed')) return 'confirmed';
-      if (t.includes('completed')) return 'completed';
-      if (t.includes('pending')) return 'pending';
-      if (t.includes('canceled') || t.includes('cancelled')) return 'canceled';
-    }
-    return null;
-  };
-
+  // The list only carries the newest message's text; the history is loaded when a chat is opened
   const getLastMessagePreview = (conversation: Conversation) => {
-    if (!conversation.messages || conversation.messages.length === 0) {
-      return 'No messages yet';
-    }
-    
-    const lastMessage = conversation.messages[conversation.messages.length - 1];
-    if (!lastMessage) return 'No messages yet';
-    
-    const senderName = lastMessage.sender?.username || 'System';
-    const messageText = lastMessage.text || '';
-    
+    const messageText = conversation.last_message || '';
+    if (!messageText) return 'No messages yet';
+
     // Truncate long messages
-    const truncatedText = messageText.length > 50 
-      ? messageText.substring(0, 50) + '...' 
+    return messageText.length > 50
+      ? messageText.substring(0, 50) + '...'
       : messageText;
-    
-    return `${senderName}: ${truncatedText}`;
   };
 
   const handleConversationClick = (conversation: Conversation) => {
@@ -186,17 +156,14 @@ const ConversationList: React.FC<ConversationListProps> = ({
 
       {displayConversations.map((conversation) => {
         const otherParticipants = getOtherParticipants(conversation);
-        const lastMessage = conversation.messages[conversation.messages.length - 1];
         const isSelected = selectedConversationId === conversation.id;
-        const hasUnreadMessages = lastMessage && 
-          lastMessage.sender_id !== currentUser.id && 
-          !lastMessage.is_system;
-        const chatProduct = conversation.product?.title || getProductFromFirstMessage(conversation);
+        const unreadCount = isSelected ? 0 : conversation.unread_count ?? 0;
+        const hasUnreadMessages = unreadCount > 0;
+        const chatProduct = conversation.product?.title;
         const chatTitle = chatProduct
           ? `${chatProduct} · ${otherParticipants.map(p => p.user.username).join(', ')}`
           : otherParticipants.map(p => p.user.username).join(', ');
-        const derivedStatus = getOrderStatusFromMessages(conversation);
-        const orderStatus = orderStatusByConv[conversation.id] || derivedStatus;
+        const orderStatus = orderStatusByConv[conversation.id];
         
         return (
           <div
@@ -219,7 +186,9 @@ const ConversationList: React.FC<ConversationListProps> = ({
                   {chatTitle}
                 </span>
                 {hasUnreadMessages && (
-                  <div className="w-2 h-2 bg-indigo-500 rounded-full flex-shrink-0"></div>
+                  <span className="inline-flex min-w-[1.25rem] justify-center px-1.5 py-0.5 rounded-full bg-indigo-500 text-white text-[10px] font-semibold flex-shrink-0">
+                    {unreadCount > 99 ? '99+' : unreadCount}
+                  </span>
                 )}
               </div>
               <span className={`text-xs flex-shrink-0 ${
@@ -241,7 +210,9 @@ const ConversationList: React.FC<ConversationListProps> = ({
               <span className={`text-xs ${
                 isSelected ? 'text-indigo-500 dark:text-indigo-400' : 'text-slate-500 dark:text-slate-400'
               }`}>
-                {conversation.messages.length} messages
+                {conversation.last_message_time
+                  ? new Date(conversation.last_message_time).toLocaleString()
+                  : 'No messages yet'}
               </span>
               <div className="flex items-center space-x-2">
                 {/* Показываем статус продукта если он неактивен */}
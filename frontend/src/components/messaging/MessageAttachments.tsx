@@ -0,0 +1,75 @@
+import React, { useEffect, useState } from 'react';
+import { Paperclip } from 'lucide-react';
+import { apiService } from '@/services/api';
+import { Attachment } from '@/types';
+
+const formatSize = (bytes: number) => {
+  if (bytes < 1024) return `${bytes} B`;
+  if (bytes < 1024 * 1024) return `${(bytes / 1024).toFixed(1)} KB`;
+  return `${(bytes / (1024 * 1024)).toFixed(1)} MB`;
+};
+
+const ImagePreview: React.FC<{ attachment: Attachment }> = ({ attachment }) => {
+  const [url, setUrl] = useState<string | null>(null);
+
+  useEffect(() => {
+    let objectUrl: string | null = null;
+    apiService
+      .getAttachmentBlob(attachment.conversation_id, attachment.id)
+      .then((blob) => {
+        objectUrl = URL.createObjectURL(blob);
+        setUrl(objectUrl);
+      })
+      .catch((err) => console.warn('Failed to load attachment preview:', err));
+    return () => {
+      if (objectUrl) URL.revokeObjectURL(objectUrl);
+    };
+  }, [attachment.conversation_id, attachment.id]);
+
+  if (!url) return <div className="w-40 h-28 rounded bg-black/10 animate-pulse" />;
+  return <img src={url} alt={attachment.file_name} className="max-w-[240px] max-h-48 rounded" />;
+};
+
+const MessageAttachments: React.FC<{ attachments?: Attachment[] }> = ({ attachments }) => {
+  if (!attachments || attachments.length === 0) return null;
+
+  const download = async (attachment: Attachment) => {
+    try {
+      const blob = await apiService.getAttachmentBlob(attachment.conversation_id, attachment.id);
+      const url = URL.createObjectURL(blob);
+      const link = document.createElement('a');
+      link.href = url;
+      link.download = attachment.file_name;
+      link.click();
+      URL.revokeObjectURL(url);
+    } catch (err) {
+      console.error('Failed to download attachment:', err);
+    }
+  };
+
+  return (
+    <div className="mt-2 space-y-2">
+      {attachments.map((attachment) => (
+        <button
+          key={attachment.id}
+          type="button"
+          onClick={() => download(attachment)}
+          className="block text-left"
+          title={`Download ${attachment.file_name}`}
+        >
+          {attachment.content_type.startsWith('image/') ? (
+            <ImagePreview attachment={attachment} />
+          ) : (
+            <span className="inline-flex items-center space-x-1 text-sm underline">
+              <Paperclip className="h-3 w-3" />
+              <span className="truncate max-w-[200px]">{attachment.file_name}</span>
+              <span className="opacity-70">({formatSize(attachment.size)})</span>
+            </span>
+          )}
+        </button>
+      ))}
+    </div>
+  );
+};
+
+export default MessageAttachments;
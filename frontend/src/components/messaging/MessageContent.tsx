@@ -0,0 +1,53 @@
+import React from 'react';
+import { Flag, Pencil, Trash2 } from 'lucide-react';
+import { Message } from '@/types';
+
+interface MessageContentProps {
+  message: Message;
+  isOwn: boolean;
+  onEdit?: (message: Message) => void;
+  onDelete?: (message: Message) => void;
+  onReport?: (message: Message) => void;
+}
+
+// Message text with the deleted placeholder, the "(edited)" marker and edit/delete actions for the sender.
+// The server enforces the edit window; the actions just surface its error when it has passed.
+// Messages from other participants get a report action instead.
+const MessageContent: React.FC<MessageContentProps> = ({ message, isOwn, onEdit, onDelete, onReport }) => {
+  if (message.is_deleted) {
+    return <div className="mt-1 italic opacity-70">{message.text}</div>;
+  }
+
+  const canModify = isOwn && !message.is_system;
+  const canReport = !isOwn && !message.is_system && !!onReport;
+
+  return (
+    <div className="mt-1 group">
+      <span>{message.text}</span>
+      {message.edited_at && <span className="ml-1 text-xs opacity-70">(edited)</span>}
+      {canModify && (onEdit || onDelete) && (
+        <span className="ml-2 inline-flex gap-1 opacity-0 group-hover:opacity-100 transition-opacity">
+          {onEdit && (
+            <button type="button" onClick={() => onEdit(message)} title="Edit message" className="opacity-80 hover:opacity-100">
+              <Pencil className="w-3 h-3" />
+            </button>
+          )}
+          {onDelete && (
+            <button type="button" onClick={() => onDelete(message)} title="Delete message" className="opacity-80 hover:opacity-100">
+              <Trash2 className="w-3 h-3" />
+            </button>
+          )}
+        </span>
+      )}
+      {canReport && (
+        <span className="ml-2 inline-flex opacity-0 group-hover:opacity-100 transition-opacity">
+          <button type="button" onClick={() => onReport?.(message)} title="Report message" className="opacity-80 hover:opacity-100">
+            <Flag className="w-3 h-3" />
+          </button>
+        </span>
+      )}
+    </div>
+  );
+};
+
+export default MessageContent;
@@ -0,0 +1,90 @@
+import React, { useEffect, useState } from 'react';
+import { Search } from 'lucide-react';
+import { apiService } from '@/services/api';
+import { MessageSearchResult } from '@/types';
+
+interface MessageSearchResultsProps {
+  query: string;
+  onSelect: (result: MessageSearchResult) => void;
+}
+
+// Snippets are raw message text with <mark> around matches, so split on the markers
+// and render plain text instead of injecting HTML.
+const Snippet: React.FC<{ text: string }> = ({ text }) => (
+  <>
+    {text.split(/(<mark>.*?<\/mark>)/g).map((part, i) =>
+      part.startsWith('<mark>') && part.endsWith('</mark>') ? (
+        <mark key={i} className="bg-yellow-200 dark:bg-yellow-700/60 rounded px-0.5">
+          {part.slice(6, -7)}
+        </mark>
+      ) : (
+        <React.Fragment key={i}>{part}</React.Fragment>
+      )
+    )}
+  </>
+);
+
+const MessageSearchResults: React.FC<MessageSearchResultsProps> = ({ query, onSelect }) => {
+  const [results, setResults] = useState<MessageSearchResult[]>([]);
+  const [isSearching, setIsSearching] = useState(false);
+
+  useEffect(() => {
+    const q = query.trim();
+    if (q.length < 2) {
+      setResults([]);
+      return;
+    }
+
+    let cancelled = false;
+    const timer = setTimeout(async () => {
+      setIsSearching(true);
+      try {
+        const found = await apiService.searchConversations({ q });
+        if (!cancelled) setResults(found);
+      } catch (err) {
+        console.warn('Message search failed:', err);
+        if (!cancelled) setResults([]);
+      } finally {
+        if (!cancelled) setIsSearching(false);
+      }
+    }, 300);
+
+    return () => {
+      cancelled = true;
+      clearTimeout(timer);
+    };
+  }, [query]);
+
+  if (query.trim().length < 2) return null;
+
+  return (
+    <div className="mb-3">
+      <div className="flex items-center text-xs font-medium text-gray-500 dark:text-slate-400 mb-1">
+        <Search className="w-3 h-3 mr-1" />
+        {isSearching ? 'Searching messages…' : `${results.length} matching messages`}
+      </div>
+      <div className="space-y-1">
+        {results.map((result) => (
+          <button
+            key={result.message_id}
+            type="button"
+            onClick={() => onSelect(result)}
+            className="w-full text-left p-2 rounded-md border border-gray-200 hover:bg-gray-50 dark:border-gray-800 dark:hover:bg-slate-800"
+          >
+            <div className="flex justify-between text-xs text-gray-500 dark:text-slate-400">
+              <span className="truncate">
+                {result.conversation?.product?.title || `Conversation #${result.conversation_id}`}
+              </span>
+              <span className="ml-2 flex-shrink-0">{new Date(result.sent_at).toLocaleDateString()}</span>
+            </div>
+            <div className="text-sm text-gray-800 dark:text-slate-200 line-clamp-2">
+              <Snippet text={result.snippet} />
+            </div>
+          </button>
+        ))}
+      </div>
+    </div>
+  );
+};
+
+export default MessageSearchResults;
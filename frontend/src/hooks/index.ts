@@ -1,2 +1,3 @@
 export { useAutoRefresh } from './useAutoRefresh';
 export { useTheme } from './useTheme';
+export { useRealtime } from './useRealtime';
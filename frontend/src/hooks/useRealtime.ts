@@ -0,0 +1,26 @@
+import { useEffect, useRef, useState } from 'react';
+import { useAuthStore } from '@/store/authStore';
+import { realtimeClient, RealtimeEvent } from '@/services/realtime';
+
+// Keeps the shared WebSocket open while logged in and forwards its events to onEvent.
+// Returns whether the socket is currently connected, so callers can fall back to polling.
+export const useRealtime = (onEvent: (event: RealtimeEvent) => void) => {
+  const token = useAuthStore((state) => state.token);
+  const [connected, setConnected] = useState(realtimeClient.isConnected());
+  const onEventRef = useRef(onEvent);
+  onEventRef.current = onEvent;
+
+  useEffect(() => {
+    if (!token) {
+      realtimeClient.disconnect();
+      return;
+    }
+    realtimeClient.connect(token);
+  }, [token]);
+
+  useEffect(() => realtimeClient.onStatusChange(setConnected), []);
+
+  useEffect(() => realtimeClient.subscribe((event) => onEventRef.current(event)), []);
+
+  return connected;
+};
@@ -0,0 +1,119 @@
+import React, { useState, useEffect } from 'react'
+import { Link, useParams, useNavigate } from 'react-router-dom'
+import { ArrowLeft, Monitor, Search, Loader2 } from 'lucide-react'
+import { apiService } from '@/services/api'
+import { Product, Category } from '@/types'
+
+const CategoryPage: React.FC = () => {
+  const { slug } = useParams<{ slug: string }>()
+  const navigate = useNavigate()
+  const [category, setCategory] = useState<Category | null>(null)
+  const [products, setProducts] = useState<Product[]>([])
+  const [isLoading, setIsLoading] = useState(true)
+  const [error, setError] = useState('')
+
+  useEffect(() => {
+    const fetchCategory = async () => {
+      if (!slug) return
+
+      try {
+        setIsLoading(true)
+        setError('')
+        const categoryData = await apiService.getCategoryBySlug(slug)
+        setCategory(categoryData)
+
+        // Keep the address bar on the canonical slug URL
+        if (categoryData.slug && categoryData.slug !== slug) {
+          navigate(`/categories/${categoryData.slug}`, { replace: true })
+        }
+
+        const productsData = await apiService.getProducts({ category_id: categoryData.id })
+        setProducts(productsData)
+      } catch (err: unknown) {
+        const message =
+          typeof err === 'object' && err !== null && 'response' in err
+            ? (err as { response?: { data?: { error?: string } } }).response?.data?.error || 'Failed to load category'
+            : 'Failed to load category'
+        setError(message)
+      } finally {
+        setIsLoading(false)
+      }
+    }
+
+    fetchCategory()
+  }, [slug])
+
+  if (isLoading) {
+    return (
+      <div className="flex items-center justify-center py-12">
+        <Loader2 className="w-8 h-8 animate-spin text-blue-600" />
+        <span className="ml-2 text-gray-600">Loading category...</span>
+      </div>
+    )
+  }
+
+  if (error || !category) {
+    return (
+      <div className="max-w-7xl mx-auto px-4 sm:px-6 lg:px-8 py-12 text-center">
+        <h3 className="text-lg font-medium text-gray-900 mb-2 dark:text-gray-100">Category not found</h3>
+        <p className="text-gray-600 mb-6 dark:text-gray-400">{error}</p>
+        <Link to="/products" className="text-blue-600 hover:text-blue-700 inline-flex items-center">
+          <ArrowLeft className="w-4 h-4 mr-1" />
+          Back to all digital goods
+        </Link>
+      </div>
+    )
+  }
+
+  return (
+    <div className="max-w-7xl mx-auto px-4 sm:px-6 lg:px-8 py-8">
+      <Link to="/products" className="text-blue-600 hover:text-blue-700 inline-flex items-center mb-6">
+        <ArrowLeft className="w-4 h-4 mr-1" />
+        All digital goods
+      </Link>
+
+      <div className="mb-8">
+        <h1 className="text-3xl font-bold text-gray-900 mb-2 dark:text-gray-100">{category.name}</h1>
+        {category.description && (
+          <p className="text-gray-600 dark:text-gray-300">{category.description}</p>
+        )}
+      </div>
+
+      {products.length === 0 ? (
+        <div className="text-center py-12">
+          <div className="w-16 h-16 bg-gray-100 rounded-full flex items-center justify-center mx-auto mb-4 dark:bg-gray-800">
+            <Search className="w-8 h-8 text-gray-400 dark:text-gray-500" />
+          </div>
+          <h3 className="text-lg font-medium text-gray-900 mb-2 dark:text-gray-100">No digital goods in this category yet</h3>
+        </div>
+      ) : (
+        <div className="grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 xl:grid-cols-4 gap-6">
+          {products.map((product) => (
+            <div key={product.id} className="bg-white rounded-lg shadow-sm border border-gray-200 hover:shadow-md transition-shadow dark:bg-gray-900 dark:border-gray-800">
+              <div className="p-6">
+                <Monitor className="w-8 h-8 text-blue-600 mb-4" />
+                <h3 className="font-semibold text-gray-900 mb-2 line-clamp-2 dark:text-gray-100">
+                  {product.title}
+                </h3>
+                <p className="text-gray-600 text-sm mb-4 line-clamp-3 dark:text-gray-300">
+                  {product.description}
+                </p>
+                <div className="flex items-center justify-between">
+                  <span className="text-2xl font-bold text-blue-600">${(product.price / 100).toFixed(2)}</span>
+                  <Link
+                    to={`/products/${product.slug ?? product.id}`}
+                    className="bg-blue-600 text-white px-4 py-2 rounded-lg text-sm font-medium hover:bg-blue-700 transition-colors dark:bg-blue-500 dark:hover:bg-blue-600"
+                  >
+                    View Details
+                  </Link>
+                </div>
+              </div>
+            </div>
+          ))}
+        </div>
+      )}
+    </div>
+  )
+}
+
+export default CategoryPage
@@ -1,19 +1,31 @@
 import React, { useState, useEffect, useRef, useCallback, useMemo } from 'react';
 import { useAuthStore } from '@/store/authStore';
 import { apiService } from '@/services/api';
-import { Conversation, Message, Order, Product } from '@/types';
+import { Attachment, Conversation, Message, MessageSearchResult, Order, Presence, Product } from '@/types';
 import ConversationList from '@/components/messaging/ConversationList';
-import { Send, ArrowLeft, CheckCircle2, Loader2, Package, User as UserIcon, MessageCircle } from 'lucide-react';
+import MessageAttachments from '@/components/messaging/MessageAttachments';
+import MessageContent from '@/components/messaging/MessageContent';
+import MessageSearchResults from '@/components/messaging/MessageSearchResults';
+import { Send, ArrowLeft, CheckCircle2, Loader2, Package, User as UserIcon, MessageCircle, Paperclip, X } from 'lucide-react';
 import toast from 'react-hot-toast';
-import { useSearchParams } from 'react-router-dom';
+import { useNavigate, useSearchParams } from 'react-router-dom';
 import { useAutoRefresh } from '@/hooks/useAutoRefresh';
+import { useRealtime } from '@/hooks/useRealtime';
+import { realtimeClient, RealtimeEvent } from '@/services/realtime';
 
 const ConversationsPage: React.FC = () => {
   const { user } = useAuthStore();
   const [searchParams, setSearchParams] = useSearchParams();
+  const navigate = useNavigate();
   const [conversations, setConversations] = useState<Conversation[]>([]);
   const [selectedConversation, setSelectedConversation] = useState<Conversation | null>(null);
   const [messages, setMessages] = useState<Message[]>([]);
+  const [hasOlderMessages, setHasOlderMessages] = useState(false);
+  const [hasNewerMessages, setHasNewerMessages] = useState(false);
+  const [highlightedMessageId, setHighlightedMessageId] = useState<number | null>(null);
+  const [isLoadingOlder, setIsLoadingOlder] = useState(false);
+  const [typingUserIds, setTypingUserIds] = useState<number[]>([]);
+  const [otherPresence, setOtherPresence] = useState<Presence | null>(null);
   const [newMessage, setNewMessage] = useState('');
   const [isSending, setIsSending] = useState(false);
   const [isLoading, setIsLoading] = useState(false);
@@ -28,6 +40,9 @@ const ConversationsPage: React.FC = () => {
   const [mobileView, setMobileView] = useState<'list' | 'chat'>('list');
   const [isSwitchingConversation, setIsSwitchingConversation] = useState(false);
   const [listSearch, setListSearch] = useState('');
+  const [pendingAttachments, setPendingAttachments] = useState<Attachment[]>([]);
+  const [isUploading, setIsUploading] = useState(false);
+  const fileInputRef = useRef<HTMLInputElement>(null);
   
   const messagesContainerRef = useRef<HTMLDivElement>(null);
   const messageInputRef = useRef<HTMLInputElement>(null);
@@ -37,6 +52,12 @@ const ConversationsPage: React.FC = () => {
   const activeConversationIdRef = useRef<number | null>(null);
   const isFetchingConversationsRef = useRef(false);
   const isFetchingMessagesRef = useRef(false);
+  const isLoadingOlderRef = useRef(false);
+  // Set while the loaded history is a window that doesn't reach the newest message (after a search jump)
+  const hasNewerRef = useRef(false);
+  const jumpToMessageRef = useRef<number | null>(null);
+  const typingTimersRef = useRef(new Map<number, ReturnType<typeof setTimeout>>());
+  const lastTypingSentRef = useRef(0);
 
   // Load conversations
   const loadConversations = useCallback(async (options?: { silent?: boolean; syncSelection?: boolean }) => {
@@ -88,10 +109,8 @@ const ConversationsPage: React.FC = () => {
       } else {
         // Initial/full load: sort by last activity
         const sorted: Conversation[] = [...(data as Conversation[])].sort((a: Conversation, b: Conversation) => {
-          const aLastMessage = a.messages[a.messages.length - 1];
-          const bLastMessage = b.messages[b.messages.length - 1];
-          if (aLastMessage && bLastMessage) {
-            return new Date(bLastMessage.created_at).getTime() - new Date(aLastMessage.created_at).getTime();
+          if (a.last_message_time && b.last_message_time) {
+            return new Date(b.last_message_time).getTime() - new Date(a.last_message_time).getTime();
           }
           return new Date(b.updated_at).getTime() - new Date(a.updated_at).getTime();
         });
@@ -119,7 +138,7 @@ const ConversationsPage: React.FC = () => {
   }, [user]);
 
   // Load messages for a conversation
-  const loadMessages = useCallback(async (conversationId: number, options?: { silent?: boolean }): Promise<boolean> => {
+  const loadMessages = useCallback(async (conversationId: number, options?: { silent?: boolean; around?: number }): Promise<boolean> => {
     try {
       // Allow concurrent loads; ignore stale responses via activeConversationIdRef
       isFetchingMessagesRef.current = true;
@@ -128,15 +147,21 @@ const ConversationsPage: React.FC = () => {
       }
       setError(null);
       // do not override user's manual scroll state here; it is tracked in onScroll
-      const data = await apiService.getMessages(conversationId);
-      
-      // Sort messages chronologically
-      const sorted = [...data].sort((a, b) => 
-        new Date(a.created_at).getTime() - new Date(b.created_at).getTime()
-      );
-      
+      // The newest page (or the page around a search hit), already oldest-first; older pages load on scroll
+      const page = await apiService.getMessages(conversationId, options?.around ? { around: options.around } : undefined);
+      const sorted = page.messages;
+
       const isForActiveConversation = activeConversationIdRef.current === conversationId;
       if (isForActiveConversation) {
+        if (options?.silent && hasNewerRef.current) {
+          // Viewing older history; appending the newest page here would leave a gap
+          return true;
+        }
+        if (!options?.silent) {
+          setHasOlderMessages(page.has_older);
+          setHasNewerMessages(page.has_newer);
+          hasNewerRef.current = page.has_newer;
+        }
         // Merge by id, keep stable order (avoid jank by not re-sorting existing messages)
         setMessages((prev) => {
           if (!options?.silent || prev.length === 0) return sorted;
@@ -147,8 +172,15 @@ const ConversationsPage: React.FC = () => {
           }
           return merged;
         });
-        // scroll only if user was near bottom before update (stable check)
-        if (!options?.silent && wasNearBottomRef.current) {
+        if (options?.around) {
+          const targetId = options.around;
+          setHighlightedMessageId(targetId);
+          setTimeout(() => {
+            document.getElementById(`message-${targetId}`)?.scrollIntoView({ block: 'center' });
+          }, 0);
+          setTimeout(() => setHighlightedMessageId((id) => (id === targetId ? null : id)), 3000);
+        } else if (!options?.silent && wasNearBottomRef.current) {
+          // scroll only if user was near bottom before update (stable check)
           setTimeout(() => {
             if (messagesContainerRef.current) {
               messagesContainerRef.current.scrollTop = messagesContainerRef.current.scrollHeight;
@@ -188,7 +220,7 @@ const ConversationsPage: React.FC = () => {
 
   // Send message
   const sendMessage = useCallback(async () => {
-    if (!newMessage.trim() || !selectedConversation || !user || isSending) return;
+    if ((!newMessage.trim() && pendingAttachments.length === 0) || !selectedConversation || !user || isSending) return;
 
     try {
       setIsSending(true);
@@ -197,13 +229,17 @@ const ConversationsPage: React.FC = () => {
       const messageData = {
         text: newMessage.trim(),
         sender_id: user.id,
-        order_id: order?.id || undefined
+        order_id: order?.id || undefined,
+        attachment_ids: pendingAttachments.map((a) => a.id),
       };
 
       await apiService.sendMessage(selectedConversation.id, messageData);
-      
+      realtimeClient.sendTyping(selectedConversation.id, false);
+      lastTypingSentRef.current = 0;
+
       // Clear input and retain focus for continued typing
       setNewMessage('');
+      setPendingAttachments([]);
       
       // Reload messages and conversations
       await Promise.all([
@@ -223,7 +259,200 @@ const ConversationsPage: React.FC = () => {
     } finally {
       setIsSending(false);
     }
-  }, [newMessage, selectedConversation, user, order?.id, loadMessages, loadConversations]);
+  }, [newMessage, pendingAttachments, selectedConversation, user, order?.id, loadMessages, loadConversations]);
+
+  // Announce typing at most every couple of seconds; the server throttles too
+  const handleMessageChange = useCallback((value: string) => {
+    setNewMessage(value);
+    if (!selectedConversation) return;
+
+    if (!value.trim()) {
+      if (lastTypingSentRef.current) {
+        realtimeClient.sendTyping(selectedConversation.id, false);
+        lastTypingSentRef.current = 0;
+      }
+      return;
+    }
+    const now = Date.now();
+    if (now - lastTypingSentRef.current > 2000) {
+      realtimeClient.sendTyping(selectedConversation.id, true);
+      lastTypingSentRef.current = now;
+    }
+  }, [selectedConversation]);
+
+  const setTyping = useCallback((userId: number, typing: boolean) => {
+    const timers = typingTimersRef.current;
+    const existing = timers.get(userId);
+    if (existing) clearTimeout(existing);
+    timers.delete(userId);
+
+    if (typing) {
+      // A typing notice goes stale if it isn't refreshed
+      timers.set(userId, setTimeout(() => setTyping(userId, false), 6000));
+      setTypingUserIds((prev) => (prev.includes(userId) ? prev : [...prev, userId]));
+    } else {
+      setTypingUserIds((prev) => prev.filter((id) => id !== userId));
+    }
+  }, []);
+
+  // Reset typing indicators and load the other participant's presence when switching chats
+  useEffect(() => {
+    typingTimersRef.current.forEach((timer) => clearTimeout(timer));
+    typingTimersRef.current.clear();
+    setTypingUserIds([]);
+    setOtherPresence(null);
+    lastTypingSentRef.current = 0;
+
+    const other = selectedConversation?.participants.find((p) => p.user_id !== user?.id);
+    if (!other) return;
+
+    let cancelled = false;
+    const load = () => {
+      apiService
+        .getPresence(other.user_id)
+        .then((presence) => {
+          if (!cancelled) setOtherPresence(presence);
+        })
+        .catch(() => {});
+    };
+    load();
+    const timer = setInterval(load, 30000);
+    return () => {
+      cancelled = true;
+      clearInterval(timer);
+    };
+  }, [selectedConversation?.id, selectedConversation?.participants, user?.id]);
+
+  const presenceLabel = otherPresence?.online
+    ? 'Online'
+    : otherPresence?.last_seen_at
+      ? `Last seen ${new Date(otherPresence.last_seen_at).toLocaleString()}`
+      : null;
+
+  const typingLabel = typingUserIds.length > 0
+    ? `${typingUserIds
+        .map((id) => selectedConversation?.participants.find((p) => p.user_id === id)?.user.username || 'Someone')
+        .join(', ')} ${typingUserIds.length === 1 ? 'is' : 'are'} typing…`
+    : null;
+
+  // Upload picked files right away; they are linked to the next message that is sent
+  const handleFilesSelected = useCallback(async (e: React.ChangeEvent<HTMLInputElement>) => {
+    const files = Array.from(e.target.files || []);
+    e.target.value = '';
+    if (!selectedConversation || files.length === 0) return;
+
+    setIsUploading(true);
+    try {
+      for (const file of files) {
+        try {
+          const attachment = await apiService.uploadAttachment(selectedConversation.id, file);
+          setPendingAttachments((prev) => [...prev, attachment]);
+        } catch (err: unknown) {
+          const message =
+            typeof err === 'object' && err !== null && 'response' in err
+              ? (err as { response?: { data?: { error?: string } } }).response?.data?.error
+              : undefined;
+          toast.error(`${file.name}: ${message || 'upload failed'}`);
+        }
+      }
+    } finally {
+      setIsUploading(false);
+    }
+  }, [selectedConversation]);
+
+  const replaceMessage = useCallback((updated: Message) => {
+    setMessages((prev) => prev.map((m) => (m.id === updated.id ? { ...m, ...updated } : m)));
+  }, []);
+
+  const editMessage = useCallback(async (message: Message) => {
+    const text = window.prompt('Edit message', message.text);
+    if (text == null || !text.trim() || text === message.text) return;
+
+    try {
+      replaceMessage(await apiService.editMessage(message.conversation_id, message.id, text.trim()));
+    } catch (err: unknown) {
+      const error =
+        typeof err === 'object' && err !== null && 'response' in err
+          ? (err as { response?: { data?: { error?: string } } }).response?.data?.error
+          : undefined;
+      toast.error(error || 'Failed to edit message');
+    }
+  }, [replaceMessage]);
+
+  const deleteMessage = useCallback(async (message: Message) => {
+    if (!window.confirm('Delete this message?')) return;
+
+    try {
+      replaceMessage(await apiService.deleteMessage(message.conversation_id, message.id));
+    } catch (err: unknown) {
+      const error =
+        typeof err === 'object' && err !== null && 'response' in err
+          ? (err as { response?: { data?: { error?: string } } }).response?.data?.error
+          : undefined;
+      toast.error(error || 'Failed to delete message');
+    }
+  }, [replaceMessage]);
+
+  const reportMessage = useCallback(async (message: Message) => {
+    try {
+      const reasons = await apiService.getMessageReportReasons();
+      const codes = Object.keys(reasons);
+      const choice = window.prompt(
+        'Why are you reporting this message? Enter a number:\n' +
+          codes.map((code, i) => `${i + 1}. ${reasons[code]}`).join('\n'),
+      );
+      if (choice == null) return;
+
+      const reason = codes[Number(choice.trim()) - 1];
+      if (!reason) {
+        toast.error('Please pick one of the listed reasons');
+        return;
+      }
+      const comment = window.prompt('Add a comment for the moderators (optional)') ?? '';
+
+      await apiService.reportMessage(message.conversation_id, message.id, reason, comment.trim());
+      toast.success('Message reported. Thank you!');
+    } catch (err: unknown) {
+      const error =
+        typeof err === 'object' && err !== null && 'response' in err
+          ? (err as { response?: { data?: { error?: string } } }).response?.data?.error
+          : undefined;
+      toast.error(error || 'Failed to report message');
+    }
+  }, []);
+
+  useEffect(() => {
+    setPendingAttachments([]);
+  }, [selectedConversation?.id]);
+
+  const attachmentControls = (
+    <>
+      <input ref={fileInputRef} type="file" multiple className="hidden" onChange={handleFilesSelected} />
+      <button
+        type="button"
+        onClick={() => fileInputRef.current?.click()}
+        disabled={isUploading || !isOnline}
+        title="Attach files"
+        className="w-10 h-10 rounded-full text-gray-500 hover:bg-gray-100 disabled:opacity-50 flex items-center justify-center flex-shrink-0 dark:text-gray-400 dark:hover:bg-gray-800"
+      >
+        {isUploading ? <Loader2 className="h-4 w-4 animate-spin" /> : <Paperclip className="h-4 w-4" />}
+      </button>
+    </>
+  );
+
+  const pendingAttachmentList = pendingAttachments.length > 0 && (
+    <div className="flex flex-wrap gap-2 mb-2">
+      {pendingAttachments.map((a) => (
+        <span key={a.id} className="inline-flex items-center space-x-1 px-2 py-1 rounded bg-gray-100 text-xs dark:bg-gray-800 dark:text-gray-200">
+          <Paperclip className="h-3 w-3" />
+          <span className="truncate max-w-[160px]">{a.file_name}</span>
+          <button type="button" onClick={() => setPendingAttachments((prev) => prev.filter((p) => p.id !== a.id))}>
+            <X className="h-3 w-3" />
+          </button>
+        </span>
+      ))}
+    </div>
+  );
 
   // Handle conversation selection
   const handleConversationSelect = useCallback((conversation: Conversation) => {
@@ -233,6 +462,9 @@ const ConversationsPage: React.FC = () => {
       setIsSwitchingConversation(true);
       setSelectedConversation(conversation);
       setMessages([]);
+      setHasOlderMessages(false);
+      setHasNewerMessages(false);
+      hasNewerRef.current = false;
       // Ensure first load scrolls to bottom once
       wasNearBottomRef.current = true;
       activeConversationIdRef.current = conversation.id;
@@ -241,7 +473,7 @@ const ConversationsPage: React.FC = () => {
     setError(null);
 
     // Derive order from selected conversation and sync URL + state
-    const derivedOrderId = conversation.messages.find(msg => msg.order_id)?.order_id || null;
+    const derivedOrderId = conversation.order_id || null;
     if (derivedOrderId) {
       if (derivedOrderId !== orderId) {
         setOrderId(derivedOrderId);
@@ -260,6 +492,27 @@ const ConversationsPage: React.FC = () => {
     }
   }, [orderId, loadOrder, setSearchParams, selectedConversation?.id, isMobile]);
 
+  // Open a search hit: select its conversation and load the history around the message
+  const openSearchResult = useCallback((result: MessageSearchResult) => {
+    const conversation = conversations.find((c) => c.id === result.conversation_id) || result.conversation;
+    if (!conversation) return;
+
+    if (selectedConversation?.id === conversation.id) {
+      wasNearBottomRef.current = false;
+      loadMessages(conversation.id, { around: result.message_id });
+      if (isMobile) setMobileView('chat');
+      return;
+    }
+    jumpToMessageRef.current = result.message_id;
+    handleConversationSelect(conversation);
+  }, [conversations, selectedConversation?.id, loadMessages, handleConversationSelect, isMobile]);
+
+  const jumpToLatest = useCallback(() => {
+    if (!selectedConversation) return;
+    wasNearBottomRef.current = true;
+    loadMessages(selectedConversation.id);
+  }, [selectedConversation, loadMessages]);
+
   // Handle order confirmation
   const handleConfirmOrder = useCallback(async () => {
     if (!order?.id) return;
@@ -284,6 +537,38 @@ const ConversationsPage: React.FC = () => {
 
   // Removed unused scrollToBottom helper; we scroll on load explicitly
 
+  // Prepend the page before the oldest loaded message, keeping the viewport anchored
+  const loadOlderMessages = useCallback(async () => {
+    const conversationId = activeConversationIdRef.current;
+    if (!conversationId || !hasOlderMessages || isLoadingOlderRef.current || messages.length === 0) return;
+
+    isLoadingOlderRef.current = true;
+    setIsLoadingOlder(true);
+    try {
+      const page = await apiService.getMessages(conversationId, { before: messages[0].id });
+      if (activeConversationIdRef.current !== conversationId) return;
+
+      const el = messagesContainerRef.current;
+      const previousHeight = el?.scrollHeight ?? 0;
+      setMessages((prev) => {
+        const existing = new Set(prev.map((m) => m.id));
+        return [...page.messages.filter((m) => !existing.has(m.id)), ...prev];
+      });
+      setHasOlderMessages(page.has_older);
+      setTimeout(() => {
+        if (el) {
+          el.scrollTop += el.scrollHeight - previousHeight;
+        }
+      }, 0);
+    } catch (error) {
+      console.error('Error loading older messages:', error);
+      toast.error('Failed to load older messages');
+    } finally {
+      isLoadingOlderRef.current = false;
+      setIsLoadingOlder(false);
+    }
+  }, [hasOlderMessages, messages]);
+
   // Track user scroll position to prevent auto-scroll when user reads history
   const handleMessagesScroll = useCallback(() => {
     const el = messagesContainerRef.current;
@@ -301,8 +586,12 @@ const ConversationsPage: React.FC = () => {
       wasNearBottomRef.current = distanceFromBottom <= nearBottomThreshold;
     }
 
+    if (currentTop < previousTop && currentTop < 80) {
+      loadOlderMessages();
+    }
+
     lastScrollTopRef.current = currentTop;
-  }, []);
+  }, [loadOlderMessages]);
 
   // Handle key down in message input (onKeyPress is deprecated in React 18)
   const handleKeyDown = useCallback((e: React.KeyboardEvent) => {
@@ -330,10 +619,60 @@ const ConversationsPage: React.FC = () => {
     return order;
   }, [order]);
 
+  // Live updates over WebSocket; polling below only runs while the socket is down
+  const handleRealtimeEvent = useCallback((event: RealtimeEvent) => {
+    switch (event.type) {
+      case 'message.created':
+        if (activeConversationIdRef.current === event.conversation_id && !hasNewerRef.current) {
+          if (event.message.sender_id) {
+            setTyping(event.message.sender_id, false);
+          }
+          setMessages((prev) => (prev.some((m) => m.id === event.message.id) ? prev : [...prev, event.message]));
+          if (event.message.sender_id !== user?.id) {
+            apiService.markConversationRead(event.conversation_id, event.message.id).catch(() => {});
+          }
+          if (wasNearBottomRef.current) {
+            setTimeout(() => {
+              if (messagesContainerRef.current) {
+                messagesContainerRef.current.scrollTop = messagesContainerRef.current.scrollHeight;
+              }
+            }, 0);
+          }
+        }
+        loadConversations({ silent: true, syncSelection: false });
+        break;
+      case 'message.updated':
+        if (activeConversationIdRef.current === event.conversation_id) {
+          replaceMessage(event.message);
+        }
+        loadConversations({ silent: true, syncSelection: false });
+        break;
+      case 'typing.started':
+      case 'typing.stopped':
+        if (activeConversationIdRef.current === event.conversation_id && event.user_id !== user?.id) {
+          setTyping(event.user_id, event.type === 'typing.started');
+        }
+        break;
+      case 'participant.joined':
+      case 'participant.left':
+      case 'conversation.read':
+        loadConversations({ silent: true });
+        break;
+      case 'resync.required':
+        loadConversations({ silent: true });
+        if (activeConversationIdRef.current) {
+          loadMessages(activeConversationIdRef.current, { silent: true });
+        }
+        break;
+    }
+  }, [loadConversations, loadMessages, replaceMessage, setTyping, user?.id]);
+
+  const isRealtimeConnected = useRealtime(handleRealtimeEvent);
+
   // Auto-refresh conversations
   const { pause: pauseAutoRefresh, resume: resumeAutoRefresh } = useAutoRefresh({
     interval: 15000,
-    enabled: isOnline && !!user,
+    enabled: isOnline && !!user && !isRealtimeConnected,
     onRefresh: () => loadConversations({ silent: true, syncSelection: false }),
     dependencies: [user?.id]
   });
@@ -341,7 +680,7 @@ const ConversationsPage: React.FC = () => {
   // Auto-refresh messages for selected conversation
   const { pause: pauseMessageRefresh, resume: resumeMessageRefresh } = useAutoRefresh({
     interval: 5000,
-    enabled: isOnline && !!selectedConversation,
+    enabled: isOnline && !!selectedConversation && !isRealtimeConnected,
     onRefresh: async () => {
       if (selectedConversation) {
         await loadMessages(selectedConversation.id, { silent: true });
@@ -409,8 +748,17 @@ const ConversationsPage: React.FC = () => {
       activeConversationIdRef.current = selectedConversation.id;
       // First load should magnet to bottom once, then lock near-bottom for initial render
       wasNearBottomRef.current = true;
+      const jumpTo = jumpToMessageRef.current;
+      jumpToMessageRef.current = null;
       (async () => {
+        if (jumpTo) {
+          wasNearBottomRef.current = false;
+          await loadMessages(selectedConversation.id, { around: jumpTo });
+          setIsSwitchingConversation(false);
+          return;
+        }
         await loadMessages(selectedConversation.id);
+        apiService.markConversationRead(selectedConversation.id).catch(() => {});
         // Force scroll to bottom after first paint to avoid starting mid-list
         requestAnimationFrame(() => {
           if (messagesContainerRef.current) {
@@ -422,6 +770,9 @@ const ConversationsPage: React.FC = () => {
       resumeMessageRefresh();
     } else {
       setMessages([]);
+      setHasOlderMessages(false);
+      setHasNewerMessages(false);
+      hasNewerRef.current = false;
       pauseMessageRefresh();
     }
   }, [selectedConversation?.id, loadMessages, resumeMessageRefresh, pauseMessageRefresh]);
@@ -449,15 +800,32 @@ const ConversationsPage: React.FC = () => {
   useEffect(() => {
     // Auto-select conversation if orderId is provided and conversations are loaded
     if (orderId && conversations.length > 0 && !selectedConversation) {
-      const relatedConversation = conversations.find(conv => 
-        conv.messages.some(msg => msg.order_id === orderId)
+      const relatedConversation = conversations.find(conv =>
+        order?.id === orderId && order.conversation_id
+          ? conv.id === order.conversation_id
+          : conv.order_id === orderId
       );
       
       if (relatedConversation) {
         setSelectedConversation(relatedConversation);
       }
     }
-  }, [orderId, conversations, selectedConversation]);
+  }, [orderId, order, conversations, selectedConversation]);
+
+  useEffect(() => {
+    // Open the conversation from ?conversationId=, e.g. after starting a product inquiry
+    const conversationIdParam = searchParams.get('conversationId');
+    if (!conversationIdParam || conversations.length === 0) return;
+
+    const conversation = conversations.find((c) => c.id === parseInt(conversationIdParam));
+    if (conversation) {
+      handleConversationSelect(conversation);
+      setSearchParams((prev) => {
+        prev.delete('conversationId');
+        return prev;
+      }, { replace: true });
+    }
+  }, [searchParams, conversations, handleConversationSelect, setSearchParams]);
 
   // Refresh conversations
   const handleRefresh = useCallback(() => {
@@ -477,7 +845,7 @@ const ConversationsPage: React.FC = () => {
         .filter(p => p.user.id !== user?.id)
         .map(p => p.user.username.toLowerCase())
         .join(' ');
-      const lastText = (c.messages[c.messages.length - 1]?.text || '').toLowerCase();
+      const lastText = (c.last_message || '').toLowerCase();
       return title.includes(q) || participants.includes(q) || lastText.includes(q);
     });
   }, [conversations, listSearch, user?.id]);
@@ -626,6 +994,13 @@ const ConversationsPage: React.FC = () => {
                     <UserIcon className="w-5 h-5 mr-2" />
                     Conversations
                   </h3>
+                  <input
+                    type="search"
+                    value={listSearch}
+                    onChange={(e) => setListSearch(e.target.value)}
+                    placeholder="Search messages..."
+                    className="mt-2 w-full px-3 py-2 rounded-md border border-gray-300 dark:border-gray-700 dark:bg-slate-800 dark:text-slate-100 text-sm"
+                  />
                 </div>
                 
                 <div className="h-full overflow-y-scroll conversation-list pb-4" ref={conversationsScrollRef}>
@@ -634,12 +1009,15 @@ const ConversationsPage: React.FC = () => {
                       <Loader2 className="animate-spin rounded-full h-8 w-8 border-b-2 border-blue-600" />
                     </div>
                   ) : (
-                    <ConversationList
-                      onConversationSelect={handleConversationSelect}
-                      currentUser={user}
-                      selectedConversationId={selectedConversation?.id}
-                      conversations={conversations}
-                    />
+                    <>
+                      <MessageSearchResults query={listSearch} onSelect={openSearchResult} />
+                      <ConversationList
+                        onConversationSelect={handleConversationSelect}
+                        currentUser={user}
+                        selectedConversationId={selectedConversation?.id}
+                        conversations={filteredConversations}
+                      />
+                    </>
                   )}
                 </div>
               </div>
@@ -661,7 +1039,7 @@ const ConversationsPage: React.FC = () => {
                               .join(', ')}
                           </h3>
                           <p className="text-sm text-gray-500 dark:text-slate-400">
-                            {messages.length} messages
+                            {presenceLabel ?? 'Offline'}
                             {getProductInfo() && (
                               <span className="ml-2">• {getProductInfo()?.title}</span>
                             )}
@@ -678,6 +1056,11 @@ const ConversationsPage: React.FC = () => {
                     className={`chat-messages transition-opacity duration-150 ${isSwitchingConversation ? 'opacity-0' : 'opacity-100'}`}
                     onScroll={handleMessagesScroll}
                   >
+                    {isLoadingOlder && (
+                      <div className="flex justify-center py-2">
+                        <Loader2 className="w-4 h-4 animate-spin text-gray-400" />
+                      </div>
+                    )}
                     {isLoadingMessages && messages.length === 0 ? (
                       <div className="flex items-center justify-center h-32">
                         <Loader2 className="animate-spin rounded-full h-8 w-8 border-b-2 border-blue-600" />
@@ -700,6 +1083,7 @@ const ConversationsPage: React.FC = () => {
                       messages.map((message) => (
                         <div
                           key={message.id}
+                          id={`message-${message.id}`}
                           className={`flex ${
                             message.sender_id === user.id ? 'justify-end' : 'justify-start'
                           }`}
@@ -707,12 +1091,19 @@ const ConversationsPage: React.FC = () => {
                           <div
                             className={`message-bubble ${
                               message.sender_id === user.id ? 'sent' : 'received'
-                            }`}
+                            } ${highlightedMessageId === message.id ? 'ring-2 ring-yellow-400' : ''}`}
                           >
                             <div className="text-sm font-medium">
                               {message.sender?.username || 'System'}
                             </div>
-                            <div className="mt-1">{message.text}</div>
+                            <MessageContent
+                              message={message}
+                              isOwn={message.sender_id === user.id}
+                              onEdit={editMessage}
+                              onDelete={deleteMessage}
+                              onReport={reportMessage}
+                            />
+                            <MessageAttachments attachments={message.attachments} />
                             <div className={`text-xs mt-1 ${
                               message.sender_id === user.id ? 'text-blue-100' : 'text-gray-500'
                             }`}>
@@ -726,12 +1117,22 @@ const ConversationsPage: React.FC = () => {
 
                   {/* Message Input */}
                   <div className="chat-input dark:bg-gray-900 dark:border-gray-800">
+                    {hasNewerMessages && (
+                      <button type="button" onClick={jumpToLatest} className="text-xs text-blue-600 dark:text-blue-400 mb-1">
+                        Jump to latest messages
+                      </button>
+                    )}
+                    {typingLabel && (
+                      <div className="text-xs italic text-gray-500 dark:text-slate-400 mb-1">{typingLabel}</div>
+                    )}
+                    {pendingAttachmentList}
                     <div className="flex space-x-2">
+                      {attachmentControls}
                       <input
                         ref={messageInputRef}
                         type="text"
                         value={newMessage}
-                        onChange={(e) => setNewMessage(e.target.value)}
+                        onChange={(e) => handleMessageChange(e.target.value)}
                         onKeyDown={handleKeyDown}
                         placeholder="Type your message..."
                         className="flex-1 px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-blue-500 transition-all dark:bg-gray-800 dark:border-gray-700 dark:text-gray-100"
@@ -739,7 +1140,7 @@ const ConversationsPage: React.FC = () => {
                       />
                       <button
                         onClick={sendMessage}
-                        disabled={!newMessage.trim() || isSending || !isOnline}
+                        disabled={(!newMessage.trim() && pendingAttachments.length === 0) || isSending || isUploading || !isOnline}
                         className="px-4 py-2 bg-blue-600 text-white rounded-md hover:bg-blue-700 disabled:opacity-50 disabled:cursor-not-allowed flex items-center justify-center space-x-2 transition-all dark:bg-blue-500 dark:hover:bg-blue-600"
                       >
                         {isSending ? (
@@ -814,12 +1215,15 @@ const ConversationsPage: React.FC = () => {
                         <Loader2 className="animate-spin rounded-full h-8 w-8 border-b-2 border-blue-600" />
                       </div>
                     ) : (
-                      <ConversationList
-                        onConversationSelect={handleConversationSelect}
-                        currentUser={user}
-                        selectedConversationId={selectedConversation?.id}
-                        conversations={filteredConversations}
-                      />
+                      <>
+                        <MessageSearchResults query={listSearch} onSelect={openSearchResult} />
+                        <ConversationList
+                          onConversationSelect={handleConversationSelect}
+                          currentUser={user}
+                          selectedConversationId={selectedConversation?.id}
+                          conversations={filteredConversations}
+                        />
+                      </>
                     )}
                   </div>
                 </div>
@@ -860,7 +1264,7 @@ const ConversationsPage: React.FC = () => {
                           <button
                             onClick={() => {
                               const id = getProductInfo()?.id
-                              if (id) window.location.hash = `#/products/${id}`
+                              if (id) navigate(`/products/${id}`)
                             }}
                             className="text-xs px-2 py-1 rounded-md border border-gray-300 text-gray-700 hover:bg-gray-50 dark:border-gray-700 dark:text-gray-300 dark:hover:bg-gray-800"
                           >
@@ -877,6 +1281,11 @@ const ConversationsPage: React.FC = () => {
                     className={`chat-messages transition-opacity duration-150 ${isSwitchingConversation ? 'opacity-0' : 'opacity-100'}`}
                     onScroll={handleMessagesScroll}
                   >
+                    {isLoadingOlder && (
+                      <div className="flex justify-center py-2">
+                        <Loader2 className="w-4 h-4 animate-spin text-gray-400" />
+                      </div>
+                    )}
                     {isLoadingMessages && messages.length === 0 ? (
                       <div className="flex items-center justify-center h-32">
                         <Loader2 className="animate-spin rounded-full h-8 w-8 border-b-2 border-blue-600" />
@@ -899,13 +1308,21 @@ const ConversationsPage: React.FC = () => {
                       messages.map((message) => (
                         <div
                           key={message.id}
+                          id={`message-${message.id}`}
                           className={`flex ${message.sender_id === user.id ? 'justify-end' : 'justify-start'}`}
                         >
-                          <div className={`message-bubble ${message.sender_id === user.id ? 'sent' : 'received'}`}>
+                          <div className={`message-bubble ${message.sender_id === user.id ? 'sent' : 'received'} ${highlightedMessageId === message.id ? 'ring-2 ring-yellow-400' : ''}`}>
                             <div className="text-sm font-medium">
                               {message.sender?.username || 'System'}
                             </div>
-                            <div className="mt-1">{message.text}</div>
+                            <MessageContent
+                              message={message}
+                              isOwn={message.sender_id === user.id}
+                              onEdit={editMessage}
+                              onDelete={deleteMessage}
+                              onReport={reportMessage}
+                            />
+                            <MessageAttachments attachments={message.attachments} />
                             <div className={`text-xs mt-1 ${message.sender_id === user.id ? 'text-blue-100' : 'text-gray-500'}`}>
                               {new Date(message.created_at).toLocaleTimeString()}
                             </div>
@@ -918,12 +1335,22 @@ const ConversationsPage: React.FC = () => {
                   {/* Input bar */}
                   <div className="sticky bottom-0 bg-white/95 dark:bg-slate-900/95 backdrop-blur border-t border-gray-200 dark:border-gray-800">
                     <div className="chat-input dark:bg-transparent dark:border-transparent">
+                      {hasNewerMessages && (
+                        <button type="button" onClick={jumpToLatest} className="text-xs text-blue-600 dark:text-blue-400 mb-1">
+                          Jump to latest messages
+                        </button>
+                      )}
+                      {typingLabel && (
+                        <div className="text-xs italic text-gray-500 dark:text-slate-400 mb-1">{typingLabel}</div>
+                      )}
+                      {pendingAttachmentList}
                       <div className="flex items-end space-x-2">
+                        {attachmentControls}
                         <input
                           ref={messageInputRef}
                           type="text"
                           value={newMessage}
-                          onChange={(e) => setNewMessage(e.target.value)}
+                          onChange={(e) => handleMessageChange(e.target.value)}
                           onKeyDown={handleKeyDown}
                           placeholder="Type a message..."
                           className="flex-1 px-3 py-3 border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-blue-500 transition-all dark:bg-gray-800 dark:border-gray-700 dark:text-gray-100"
@@ -931,7 +1358,7 @@ const ConversationsPage: React.FC = () => {
                         />
                         <button
                           onClick={sendMessage}
-                          disabled={!newMessage.trim() || isSending || !isOnline}
+                          disabled={(!newMessage.trim() && pendingAttachments.length === 0) || isSending || isUploading || !isOnline}
                           className="w-10 h-10 rounded-full bg-blue-600 text-white hover:bg-blue-700 disabled:opacity-50 disabled:cursor-not-allowed flex items-center justify-center transition-all dark:bg-blue-500 dark:hover:bg-blue-600 flex-shrink-0"
                         >
                           {isSending ? <Loader2 className="h-4 w-4 animate-spin" /> : <Send className="h-4 w-4" />}
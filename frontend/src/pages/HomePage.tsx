@@ -131,7 +131,7 @@ const HomePage: React.FC = () => {
                     <span className="text-2xl font-bold text-blue-600">${(product.price / 100).toFixed(2)}</span>
                     <span className="text-sm text-gray-500 mr-2 dark:text-gray-400">{sellerName ? `by ${sellerName}` : ''}</span>
                     <Link
-                      to={`/products/${product.id}`}
+                      to={`/products/${product.slug ?? product.id}`}
                       className="bg-blue-600 text-white px-4 py-2 rounded-lg text-sm font-medium hover:bg-blue-700 transition-colors dark:bg-blue-500 dark:hover:bg-blue-600"
                     >
                       View Details
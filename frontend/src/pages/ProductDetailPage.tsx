@@ -7,7 +7,8 @@ import {
   CheckCircle,
   Monitor, 
   ArrowLeft,
-  Loader2
+  Loader2,
+  MessageCircle
 } from 'lucide-react'
 import { apiService } from '@/services/api'
 import { Product, Category } from '@/types'
@@ -20,9 +21,11 @@ const ProductDetailPage: React.FC = () => {
   const { isAuthenticated, user } = useAuthStore()
   const [product, setProduct] = useState<Product | null>(null)
   const [category, setCategory] = useState<Category | null>(null)
+  const [relatedProducts, setRelatedProducts] = useState<Product[]>([])
   const [isLoading, setIsLoading] = useState(true)
   const [error, setError] = useState('')
   const [isPurchasing, setIsPurchasing] = useState(false)
+  const [isStartingInquiry, setIsStartingInquiry] = useState(false)
   const isOwnProduct = !!(user && product && product.seller_id === user.id)
 
   // Fetch product data on component mount
@@ -32,8 +35,15 @@ const ProductDetailPage: React.FC = () => {
       
       try {
         setIsLoading(true)
-        const productData = await apiService.getProduct(Number(id))
+        const productData = /^\d+$/.test(id)
+          ? await apiService.getProduct(Number(id))
+          : await apiService.getProductBySlug(id)
         setProduct(productData)
+
+        // Keep the address bar on the canonical slug URL
+        if (productData.slug && productData.slug !== id) {
+          navigate(`/products/${productData.slug}`, { replace: true })
+        }
         
         // Fetch category data
         if (productData.category_id) {
@@ -59,6 +69,15 @@ const ProductDetailPage: React.FC = () => {
     fetchProduct()
   }, [id])
 
+  const productId = product?.id
+  useEffect(() => {
+    if (!productId) return
+    apiService
+      .getRelatedProducts(productId)
+      .then(setRelatedProducts)
+      .catch((err) => console.warn('Failed to fetch related products:', err))
+  }, [productId])
+
   const handlePurchase = async () => {
     if (!isAuthenticated) {
       toast.error('Please log in to purchase this product')
@@ -75,34 +94,13 @@ const ProductDetailPage: React.FC = () => {
     try {
       setIsPurchasing(true)
       
-      // Create order
-      const order = await apiService.createOrder({
+      // The order card is posted to the chat with the seller once the order is processed
+      await apiService.createOrder({
         customer_id: user?.id as number,
         product_id: product.id,
         status: 'pending'
       })
-      
-      // Create conversation between buyer and seller
-      try {
-        const conversation = await apiService.createConversation(
-          { product_id: product.id },
-          [user?.id as number, product.seller_id]
-        )
-        // Send auto message from seller about purchase
-        try {
-          await apiService.sendMessage(conversation.id, {
-            text: `Purchase: "${product.title}"`,
-            sender_id: product.seller_id,
-            order_id: order.id,
-          })
-        } catch (e) {
-          console.warn('Failed to send auto purchase message:', e)
-        }
-        toast.success('Order created successfully! A chat has been created with the seller.')
-      } catch (chatError) {
-        console.warn('Failed to create chat:', chatError)
-        toast.success('Order created successfully!')
-      }
+      toast.success('Order created successfully!')
       
       // Navigate to orders page
       navigate('/orders')
@@ -117,6 +115,29 @@ const ProductDetailPage: React.FC = () => {
     }
   }
 
+  const handleAskSeller = async () => {
+    if (!isAuthenticated) {
+      toast.error('Please log in to contact the seller')
+      navigate('/login')
+      return
+    }
+    if (!product || isOwnProduct) return
+
+    try {
+      setIsStartingInquiry(true)
+      const conversation = await apiService.startProductInquiry(product.id)
+      navigate(`/conversations?conversationId=${conversation.id}`)
+    } catch (err: unknown) {
+      const message =
+        typeof err === 'object' && err !== null && 'response' in err
+          ? (err as { response?: { data?: { error?: string } } }).response?.data?.error || 'Failed to contact the seller'
+          : 'Failed to contact the seller'
+      toast.error(message)
+    } finally {
+      setIsStartingInquiry(false)
+    }
+  }
+
   if (isLoading) {
     return (
       <div className="flex items-center justify-center min-h-[60vh]">
@@ -183,9 +204,18 @@ const ProductDetailPage: React.FC = () => {
             <div className="flex items-center justify-between mb-6">
               <div className="flex items-center space-x-3">
                 <Monitor className="w-12 h-12 text-blue-600" />
-                <span className="text-sm text-gray-500 bg-gray-100 px-3 py-1 rounded-full capitalize dark:bg-gray-800 dark:text-gray-300">
-                  {category?.name || 'Digital Good'}
-                </span>
+                {category?.slug ? (
+                  <Link
+                    to={`/categories/${category.slug}`}
+                    className="text-sm text-gray-500 bg-gray-100 px-3 py-1 rounded-full capitalize hover:bg-gray-200 dark:bg-gray-800 dark:text-gray-300 dark:hover:bg-gray-700"
+                  >
+                    {category.name}
+                  </Link>
+                ) : (
+                  <span className="text-sm text-gray-500 bg-gray-100 px-3 py-1 rounded-full capitalize dark:bg-gray-800 dark:text-gray-300">
+                    {category?.name || 'Digital Good'}
+                  </span>
+                )}
               </div>
               <div className="text-sm text-gray-500 dark:text-gray-400">{product.sold_count} sales</div>
             </div>
@@ -241,6 +271,21 @@ const ProductDetailPage: React.FC = () => {
                       'Purchase Now'
                     )}
                   </button>
+
+                  {!isOwnProduct && (
+                    <button
+                      onClick={handleAskSeller}
+                      disabled={isStartingInquiry}
+                      className="w-full bg-white text-blue-600 border border-blue-600 py-3 px-6 rounded-lg font-semibold hover:bg-blue-50 transition-colors disabled:opacity-50 disabled:cursor-not-allowed flex items-center justify-center dark:bg-gray-900 dark:text-blue-400 dark:border-blue-400 dark:hover:bg-gray-800"
+                    >
+                      {isStartingInquiry ? (
+                        <Loader2 className="w-4 h-4 animate-spin mr-2" />
+                      ) : (
+                        <MessageCircle className="w-4 h-4 mr-2" />
+                      )}
+                      Ask the seller
+                    </button>
+                  )}
                   
                   <div className="text-center">
                     <p className="text-sm text-gray-600 dark:text-gray-300">
@@ -279,6 +324,26 @@ const ProductDetailPage: React.FC = () => {
           </div>
         </div>
       </div>
+
+      {/* Customers also bought */}
+      {relatedProducts.length > 0 && (
+        <div className="mt-8">
+          <h2 className="text-2xl font-bold text-gray-900 mb-4 dark:text-gray-100">Customers also bought</h2>
+          <div className="grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-4 gap-4">
+            {relatedProducts.map((related) => (
+              <Link
+                key={related.id}
+                to={`/products/${related.slug ?? related.id}`}
+                className="bg-white rounded-lg shadow-sm border border-gray-200 p-4 hover:shadow-md transition-shadow dark:bg-gray-900 dark:border-gray-800"
+              >
+                <h3 className="font-semibold text-gray-900 mb-2 line-clamp-2 dark:text-gray-100">{related.title}</h3>
+                <p className="text-sm text-gray-500 mb-2 dark:text-gray-400">{related.sold_count} sales</p>
+                <span className="text-lg font-bold text-blue-600">${(related.price / 100).toFixed(2)}</span>
+              </Link>
+            ))}
+          </div>
+        </div>
+      )}
     </div>
   )
 }
@@ -290,7 +290,7 @@ const ProductsPage: React.FC = () => {
                     <span className="text-sm text-gray-500 mr-2 dark:text-gray-400">{sellerName ? `by ${sellerName}` : ''}</span>
                     {product.is_active ? (
                       <Link
-                        to={`/products/${product.id}`}
+                        to={`/products/${product.slug ?? product.id}`}
                         className="bg-blue-600 text-white px-4 py-2 rounded-lg text-sm font-medium hover:bg-blue-700 transition-colors dark:bg-blue-500 dark:hover:bg-blue-600"
                       >
                         View Details
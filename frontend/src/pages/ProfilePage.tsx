@@ -1,7 +1,11 @@
 import { useState, useEffect } from 'react';
 import { useForm } from 'react-hook-form';
-import { User, Mail, Calendar, Shield, Save, Edit } from 'lucide-react';
+import { User, Mail, Calendar, Shield, Save, Edit, EyeOff } from 'lucide-react';
 import { useAuthStore } from '@/store/authStore';
+import { apiService } from '@/services/api';
+import NotificationSettings from '@/components/common/NotificationSettings';
+import ActiveSessions from '@/components/common/ActiveSessions';
+import EmailVerificationNotice from '@/components/common/EmailVerificationNotice';
 
 interface ProfileFormData {
 	username: string;
@@ -13,6 +17,7 @@ const ProfilePage = () => {
 	const [isEditing, setIsEditing] = useState(false);
 	const [isSaving, setIsSaving] = useState(false);
 	const [message, setMessage] = useState('');
+	const [isSavingPresence, setIsSavingPresence] = useState(false);
 
 	const {
 		register,
@@ -30,6 +35,19 @@ const ProfilePage = () => {
 		}
 	}, [user, reset]);
 
+	const toggleOnlineStatus = async (hide: boolean) => {
+		if (!user) return;
+		setIsSavingPresence(true);
+		try {
+			await apiService.updatePresenceSettings(hide);
+			updateUser({ ...user, hide_online_status: hide });
+		} catch {
+			setMessage('Failed to update privacy settings');
+		} finally {
+			setIsSavingPresence(false);
+		}
+	};
+
 	const onSubmit = async (data: ProfileFormData) => {
 		setIsSaving(true);
 		setMessage('');
@@ -97,6 +115,8 @@ const ProfilePage = () => {
 				</button>
 			</div>
 
+			<EmailVerificationNotice user={user} />
+
 			{/* Success Message */}
 			{message && (
 				<div className="bg-green-50 border border-green-200 text-green-700 px-4 py-3 rounded-md">
@@ -232,6 +252,25 @@ const ProfilePage = () => {
 									</div>
 								</div>
 							</div>
+
+							<div className="flex items-center space-x-3">
+								<div className="bg-blue-100 rounded-full p-2 dark:bg-blue-900/40">
+									<EyeOff className="h-5 w-5 text-blue-600" />
+								</div>
+								<label className="flex-1 flex items-center justify-between cursor-pointer">
+									<span>
+										<span className="block text-sm text-gray-500 dark:text-gray-400">Online Status</span>
+										<span className="block font-medium text-gray-900 dark:text-gray-100">Hide from other users</span>
+									</span>
+									<input
+										type="checkbox"
+										className="h-4 w-4"
+										checked={!!user.hide_online_status}
+										disabled={isSavingPresence}
+										onChange={(e) => toggleOnlineStatus(e.target.checked)}
+									/>
+								</label>
+							</div>
 						</div>
 
 						{/* Quick Actions */}
@@ -241,7 +280,10 @@ const ProfilePage = () => {
 								<button className="w-full text-left px-3 py-2 text-sm text-gray-700 hover:bg-gray-100 rounded-md transition-colors dark:text-gray-300 dark:hover:bg-gray-800">
 									Change Password
 								</button>
-								<button className="w-full text-left px-3 py-2 text-sm text-gray-700 hover:bg-gray-100 rounded-md transition-colors dark:text-gray-300 dark:hover:bg-gray-800">
+								<button
+									onClick={() => document.getElementById('notification-settings')?.scrollIntoView({ behavior: 'smooth' })}
+									className="w-full text-left px-3 py-2 text-sm text-gray-700 hover:bg-gray-100 rounded-md transition-colors dark:text-gray-300 dark:hover:bg-gray-800"
+								>
 									Notification Settings
 								</button>
 								<button className="w-full text-left px-3 py-2 text-sm text-gray-700 hover:bg-gray-100 rounded-md transition-colors dark:text-gray-300 dark:hover:bg-gray-800">
@@ -252,6 +294,9 @@ const ProfilePage = () => {
 					</div>
 				</div>
 			</div>
+
+			<NotificationSettings />
+			<ActiveSessions />
 			</div>
 		);
 };
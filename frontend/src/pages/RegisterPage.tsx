@@ -42,7 +42,7 @@ const RegisterPage = () => {
         email: data.email,
         password: data.password,
       });
-      navigate('/login', { state: { message: 'Registration successful! Please log in.' } });
+      navigate('/login', { state: { message: 'Registration successful! Check your email for a link to verify your address, then log in.' } });
     } catch (err: unknown) {
       const message =
         typeof err === 'object' && err !== null && 'response' in err
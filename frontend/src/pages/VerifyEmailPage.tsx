@@ -0,0 +1,80 @@
+import { useEffect, useRef, useState } from 'react';
+import { Link, useSearchParams } from 'react-router-dom';
+import { CheckCircle, XCircle, Loader2 } from 'lucide-react';
+import { apiService } from '@/services/api';
+import { useAuthStore } from '@/store/authStore';
+
+type Status = 'verifying' | 'verified' | 'failed';
+
+const VerifyEmailPage = () => {
+  const [searchParams] = useSearchParams();
+  const token = searchParams.get('token') ?? '';
+  const { user, updateUser } = useAuthStore();
+  const [status, setStatus] = useState<Status>(token ? 'verifying' : 'failed');
+  const [message, setMessage] = useState(token ? '' : 'This verification link is incomplete.');
+  // Tokens work once, so StrictMode's second effect run must not submit it again
+  const submitted = useRef(false);
+
+  useEffect(() => {
+    if (!token || submitted.current) return;
+    submitted.current = true;
+
+    apiService
+      .verifyEmail(token)
+      .then((data) => {
+        setStatus('verified');
+        if (user && !user.email_verified_at) {
+          updateUser({ ...user, email_verified_at: data.email_verified_at });
+        }
+      })
+      .catch((e: any) => {
+        setStatus('failed');
+        setMessage(e?.response?.data?.error || 'Failed to verify your email address.');
+      });
+  }, [token, user, updateUser]);
+
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-gray-50 dark:bg-gray-950 py-12 px-4 sm:px-6 lg:px-8">
+      <div className="max-w-md w-full text-center space-y-6">
+        {status === 'verifying' && (
+          <>
+            <Loader2 className="mx-auto h-12 w-12 animate-spin text-blue-600" />
+            <h1 className="text-2xl font-bold text-gray-900 dark:text-gray-100">Verifying your email...</h1>
+          </>
+        )}
+
+        {status === 'verified' && (
+          <>
+            <CheckCircle className="mx-auto h-12 w-12 text-green-600" />
+            <h1 className="text-2xl font-bold text-gray-900 dark:text-gray-100">Email verified</h1>
+            <p className="text-gray-600 dark:text-gray-400">
+              Thanks for confirming your address. You can now place orders and sell products.
+            </p>
+          </>
+        )}
+
+        {status === 'failed' && (
+          <>
+            <XCircle className="mx-auto h-12 w-12 text-red-600" />
+            <h1 className="text-2xl font-bold text-gray-900 dark:text-gray-100">Verification failed</h1>
+            <p className="text-gray-600 dark:text-gray-400">{message}</p>
+            <p className="text-sm text-gray-500 dark:text-gray-400">
+              You can request a new link from your profile page.
+            </p>
+          </>
+        )}
+
+        {status !== 'verifying' && (
+          <Link
+            to={user ? '/profile' : '/login'}
+            className="inline-flex justify-center w-full py-2 px-4 rounded-md text-sm font-medium text-white bg-blue-600 hover:bg-blue-700"
+          >
+            {user ? 'Go to profile' : 'Sign in'}
+          </Link>
+        )}
+      </div>
+    </div>
+  );
+};
+
+export default VerifyEmailPage;
@@ -1,10 +1,17 @@
-import axios, { AxiosInstance, AxiosResponse } from 'axios';
+import axios, { AxiosInstance, AxiosResponse, InternalAxiosRequestConfig } from 'axios';
 import { useAuthStore } from '@/store/authStore';
 import { config } from '@/config';
-import { Product, Order, Conversation, User, Role, Category } from '@/types';
+import { Product, Order, Conversation, User, Role, Category, Attachment, Message, MessagePage, MessageSearchResult, Presence, NotificationPage, NotificationPreferences, AuthResponse, Session } from '@/types';
+
+// withCrossTabLock runs fn while holding a lock shared by every tab of the app. Browsers without
+// the Web Locks API run it straight away.
+const withCrossTabLock = <T>(name: string, fn: () => Promise<T>): Promise<T> =>
+  'locks' in navigator ? navigator.locks.request(name, fn) : fn();
 
 class ApiService {
   private api: AxiosInstance;
+  // Shared by all requests of this tab that hit a 401 at once, since a refresh token works only once
+  private refreshing: Promise<string | null> | null = null;
 
   constructor() {
     this.api = axios.create({
@@ -36,20 +43,56 @@ class ApiService {
       }
     );
 
-    // Response interceptor to handle auth errors
+    // Response interceptor to handle auth errors: refresh an expired access token once, then log out
     this.api.interceptors.response.use(
       (response: AxiosResponse) => response,
-      (error) => {
+      async (error) => {
+        const request = error.config as (InternalAxiosRequestConfig & { _retried?: boolean }) | undefined;
+        if (error.response?.status === 401 && request && !request._retried && !request.url?.startsWith('/auth/')) {
+          request._retried = true;
+          const staleToken = String(request.headers.Authorization ?? '').replace(/^Bearer /, '');
+          const token = await this.refreshAccessToken(staleToken);
+          if (token) {
+            request.headers.Authorization = `Bearer ${token}`;
+            return this.api(request);
+          }
+        }
         if (error.response?.status === 401) {
           useAuthStore.getState().logout();
-          // Use hash-based route to avoid server 404s on SPA
-          window.location.href = '/#/login';
+          window.location.href = '/login';
         }
         return Promise.reject(error);
       }
     );
   }
 
+  // Tabs share the persisted tokens, and presenting a refresh token another tab already traded
+  // in ends the session. So tabs refresh one at a time under a cross-tab lock, and each first
+  // reloads the stored tokens: if another tab refreshed since staleToken was sent, its new
+  // access token is used as is.
+  private refreshAccessToken(staleToken: string): Promise<string | null> {
+    if (!this.refreshing) {
+      this.refreshing = withCrossTabLock('auth-refresh', async () => {
+        await useAuthStore.persist.rehydrate();
+        const { token: current, refreshToken } = useAuthStore.getState();
+        if (current && current !== staleToken) return current;
+        if (!refreshToken) return null;
+        try {
+          const response = await this.api.post<AuthResponse>('/auth/refresh', { refresh_token: refreshToken });
+          const { token, refresh_token, user } = response.data;
+          useAuthStore.getState().setTokens(token, refresh_token);
+          useAuthStore.getState().updateUser(user);
+          return token;
+        } catch {
+          return null;
+        }
+      }).finally(() => {
+        this.refreshing = null;
+      });
+    }
+    return this.refreshing;
+  }
+
   // Normalize server message shape (content -> text)
   private mapMessageFromServer = (m: any) => {
     if (!m) return m;
@@ -73,8 +116,37 @@ class ApiService {
     return response.data;
   }
 
+  async logout() {
+    const refreshToken = useAuthStore.getState().refreshToken;
+    if (!refreshToken) return;
+    try {
+      await this.api.post('/auth/logout', { refresh_token: refreshToken });
+    } catch {
+      // The session expires on its own
+    }
+  }
+
+  async getSessions(): Promise<Session[]> {
+    const response = await this.api.get('/me/sessions');
+    return response.data.sessions ?? [];
+  }
+
+  async revokeSession(id: string): Promise<void> {
+    await this.api.delete(`/me/sessions/${id}`);
+  }
+
   async register(userData: { username: string; email: string; password: string }) {
-    const response = await this.api.post('/auth/register', userData);
+    const response = await this.api.post('/auth/register', { ...userData, locale: navigator.language });
+    return response.data;
+  }
+
+  async verifyEmail(token: string) {
+    const response = await this.api.post('/auth/verify-email', { token });
+    return response.data;
+  }
+
+  async resendVerification(email: string) {
+    const response = await this.api.post('/auth/resend-verification', { email });
     return response.data;
   }
 
@@ -119,6 +191,17 @@ class ApiService {
     return response.data;
   }
 
+  // Old slugs are redirected by the server, so the returned product carries the current slug.
+  async getProductBySlug(slug: string) {
+    const response = await this.api.get(`/products/by-slug/${encodeURIComponent(slug)}`);
+    return response.data;
+  }
+
+  async getRelatedProducts(id: number, limit = 4): Promise<Product[]> {
+    const response = await this.api.get(`/products/${id}/related`, { params: { limit } });
+    return response.data;
+  }
+
 
 
   async createProduct(productData: Partial<Product>) {
@@ -164,6 +247,12 @@ class ApiService {
     return response.data;
   }
 
+  // Old slugs are redirected by the server, so the returned category carries the current slug.
+  async getCategoryBySlug(slug: string): Promise<Category> {
+    const response = await this.api.get(`/categories/by-slug/${encodeURIComponent(slug)}`);
+    return response.data;
+  }
+
   // Order endpoints
   async createOrder(orderData: Partial<Order>) {
     const response = await this.api.post('/orders', orderData);
@@ -274,19 +363,130 @@ class ApiService {
     return response.data;
   }
 
+  // Opens (or reopens) the caller's pre-purchase conversation with the product's seller.
+  async startProductInquiry(productId: number): Promise<Conversation> {
+    const response = await this.api.post(`/products/${productId}/inquiry`);
+    return this.mapConversationFromServer(response.data);
+  }
+
+  async getProductConversations(productId: number): Promise<Conversation[]> {
+    const response = await this.api.get(`/products/${productId}/conversations`);
+    return (response.data ?? []).map(this.mapConversationFromServer);
+  }
+
   // Message endpoints
-  async getMessages(conversationId: number) {
-    const response = await this.api.get(`/conversations/${conversationId}/messages`);
+  // History is cursor-paginated by message ID; pass at most one of before/after/around.
+  async getMessages(
+    conversationId: number,
+    params?: { before?: number; after?: number; around?: number; limit?: number }
+  ): Promise<MessagePage> {
+    const response = await this.api.get(`/conversations/${conversationId}/messages`, { params });
     const data = response.data;
-    return Array.isArray(data) ? data.map(this.mapMessageFromServer) : data;
+    return { ...data, messages: (data.messages ?? []).map(this.mapMessageFromServer) };
   }
 
-  async sendMessage(conversationId: number, messageData: { text: string; sender_id: number; order_id?: number }) {
+  async markConversationRead(conversationId: number, messageId?: number) {
+    const response = await this.api.post(`/conversations/${conversationId}/read`, messageId ? { message_id: messageId } : {});
+    return response.data;
+  }
+
+  async getPresence(userId: number): Promise<Presence> {
+    const response = await this.api.get(`/users/${userId}/presence`);
+    return response.data;
+  }
+
+  async updatePresenceSettings(hideOnlineStatus: boolean): Promise<{ hide_online_status: boolean }> {
+    const response = await this.api.put('/me/presence', { hide_online_status: hideOnlineStatus });
+    return response.data;
+  }
+
+  async searchConversations(params: {
+    q: string;
+    product_id?: number;
+    order_id?: number;
+    from?: string;
+    to?: string;
+    limit?: number;
+    offset?: number;
+  }): Promise<MessageSearchResult[]> {
+    const response = await this.api.get('/me/conversations/search', { params });
+    return (response.data ?? []).map((r: MessageSearchResult) => ({
+      ...r,
+      conversation: r.conversation ? this.mapConversationFromServer(r.conversation) : undefined,
+    }));
+  }
+
+  async getUnread(): Promise<{ unread_count: number; unread_conversations: number }> {
+    const response = await this.api.get('/me/unread');
+    return response.data;
+  }
+
+  async getNotifications(params?: { limit?: number; offset?: number }): Promise<NotificationPage> {
+    const response = await this.api.get('/me/notifications', { params });
+    return { ...response.data, notifications: response.data.notifications ?? [] };
+  }
+
+  async markNotificationRead(id: number): Promise<void> {
+    await this.api.post(`/me/notifications/${id}/read`);
+  }
+
+  async markAllNotificationsRead(): Promise<void> {
+    await this.api.post('/me/notifications/read-all');
+  }
+
+  async getNotificationPreferences(): Promise<NotificationPreferences> {
+    const response = await this.api.get('/me/notification-preferences');
+    return response.data;
+  }
+
+  async updateNotificationPreferences(preferences: NotificationPreferences): Promise<NotificationPreferences> {
+    const response = await this.api.put('/me/notification-preferences', preferences);
+    return response.data;
+  }
+
+  async sendMessage(conversationId: number, messageData: { text: string; sender_id: number; order_id?: number; attachment_ids?: number[] }) {
     const response = await this.api.post(`/conversations/${conversationId}/messages`, {
       conversation_id: conversationId,
       content: messageData.text, // backend expects `content`
       sender_id: messageData.sender_id,
       order_id: messageData.order_id,
+      attachment_ids: messageData.attachment_ids,
+    });
+    return response.data;
+  }
+
+  async editMessage(conversationId: number, messageId: number, text: string): Promise<Message> {
+    const response = await this.api.put(`/conversations/${conversationId}/messages/${messageId}`, { content: text });
+    return this.mapMessageFromServer(response.data);
+  }
+
+  async deleteMessage(conversationId: number, messageId: number): Promise<Message> {
+    const response = await this.api.delete(`/conversations/${conversationId}/messages/${messageId}`);
+    return this.mapMessageFromServer(response.data);
+  }
+
+  async getMessageReportReasons(): Promise<Record<string, string>> {
+    const response = await this.api.get('/moderation/message-report-reasons');
+    return response.data;
+  }
+
+  async reportMessage(conversationId: number, messageId: number, reason: string, comment?: string): Promise<void> {
+    await this.api.post(`/conversations/${conversationId}/messages/${messageId}/report`, { reason, comment });
+  }
+
+  async uploadAttachment(conversationId: number, file: File): Promise<Attachment> {
+    const formData = new FormData();
+    formData.append('file', file);
+    const response = await this.api.post(`/conversations/${conversationId}/attachments`, formData, {
+      headers: { 'Content-Type': 'multipart/form-data' },
+    });
+    return response.data;
+  }
+
+  // Attachments need the auth header, so they are fetched as blobs instead of linked directly.
+  async getAttachmentBlob(conversationId: number, attachmentId: number): Promise<Blob> {
+    const response = await this.api.get(`/conversations/${conversationId}/attachments/${attachmentId}`, {
+      responseType: 'blob',
     });
     return response.data;
   }
@@ -0,0 +1,142 @@
+import { config } from '@/config';
+import { Message, Notification } from '@/types';
+
+export type RealtimeEvent =
+  | { type: 'message.created' | 'message.updated'; conversation_id: number; message: Message }
+  | { type: 'participant.joined' | 'participant.left'; conversation_id: number; user_id: number }
+  | { type: 'conversation.read'; conversation_id: number; user_id: number; last_message_id: number }
+  | { type: 'typing.started' | 'typing.stopped'; conversation_id: number; user_id: number }
+  | { type: 'resume.complete'; last_message_id?: number }
+  | { type: 'resync.required' }
+  | { type: 'notification.created'; notification: Notification }
+  | { type: 'pong' };
+
+type EventListener = (event: RealtimeEvent) => void;
+type StatusListener = (connected: boolean) => void;
+
+const MIN_RECONNECT_DELAY = 1000;
+const MAX_RECONNECT_DELAY = 30000;
+// Sent by the server when the session of the token is revoked; reconnecting with it is pointless.
+const CLOSE_SESSION_REVOKED = 1008;
+
+// Single WebSocket per tab, shared by every page that needs live conversation updates.
+// Reconnects with backoff and resumes from the last message it saw.
+class RealtimeClient {
+  private socket: WebSocket | null = null;
+  private token: string | null = null;
+  private lastMessageId = 0;
+  private reconnectDelay = MIN_RECONNECT_DELAY;
+  private reconnectTimer: ReturnType<typeof setTimeout> | null = null;
+  private listeners = new Set<EventListener>();
+  private statusListeners = new Set<StatusListener>();
+
+  connect(token: string) {
+    if (this.token === token && this.socket) return;
+    this.disconnect();
+    this.token = token;
+    this.open();
+  }
+
+  disconnect() {
+    this.token = null;
+    this.lastMessageId = 0;
+    if (this.reconnectTimer) {
+      clearTimeout(this.reconnectTimer);
+      this.reconnectTimer = null;
+    }
+    if (this.socket) {
+      this.socket.onclose = null;
+      this.socket.close();
+      this.socket = null;
+      this.emitStatus(false);
+    }
+  }
+
+  subscribe(listener: EventListener) {
+    this.listeners.add(listener);
+    return () => {
+      this.listeners.delete(listener);
+    };
+  }
+
+  onStatusChange(listener: StatusListener) {
+    this.statusListeners.add(listener);
+    listener(this.isConnected());
+    return () => {
+      this.statusListeners.delete(listener);
+    };
+  }
+
+  isConnected() {
+    return this.socket?.readyState === WebSocket.OPEN;
+  }
+
+  // Typing notices are fire-and-forget; they are dropped while disconnected.
+  sendTyping(conversationId: number, typing: boolean) {
+    if (!this.socket || !this.isConnected()) return;
+    this.socket.send(JSON.stringify({ type: typing ? 'typing' : 'typing.stop', conversation_id: conversationId }));
+  }
+
+  private open() {
+    if (!this.token) return;
+
+    const url = new URL('/ws', config.apiUrl);
+    url.protocol = url.protocol === 'https:' ? 'wss:' : 'ws:';
+    url.searchParams.set('token', this.token);
+    if (this.lastMessageId > 0) {
+      url.searchParams.set('since', String(this.lastMessageId));
+    }
+
+    const socket = new WebSocket(url.toString());
+    this.socket = socket;
+
+    socket.onopen = () => {
+      this.reconnectDelay = MIN_RECONNECT_DELAY;
+      this.emitStatus(true);
+    };
+
+    socket.onmessage = (e) => {
+      let event: RealtimeEvent;
+      try {
+        event = JSON.parse(e.data);
+      } catch {
+        return;
+      }
+
+      if (event.type === 'message.created' || event.type === 'message.updated') {
+        const raw = event.message as Message & { content?: string };
+        event = { ...event, message: { ...raw, text: raw.text ?? raw.content ?? '' } };
+      }
+      if (event.type === 'message.created') {
+        this.lastMessageId = Math.max(this.lastMessageId, event.message.id);
+      }
+      this.listeners.forEach((listener) => listener(event));
+    };
+
+    socket.onclose = (e) => {
+      this.socket = null;
+      this.emitStatus(false);
+      if (e.code === CLOSE_SESSION_REVOKED) {
+        this.token = null;
+        return;
+      }
+      this.scheduleReconnect();
+    };
+  }
+
+  private scheduleReconnect() {
+    if (!this.token || this.reconnectTimer) return;
+
+    this.reconnectTimer = setTimeout(() => {
+      this.reconnectTimer = null;
+      this.open();
+    }, this.reconnectDelay);
+    this.reconnectDelay = Math.min(this.reconnectDelay * 2, MAX_RECONNECT_DELAY);
+  }
+
+  private emitStatus(connected: boolean) {
+    this.statusListeners.forEach((listener) => listener(connected));
+  }
+}
+
+export const realtimeClient = new RealtimeClient();
@@ -5,8 +5,10 @@ import { User } from '@/types';
 interface AuthState {
   user: User | null;
   token: string | null;
+  refreshToken: string | null;
   isAuthenticated: boolean;
-  login: (user: User, token: string) => void;
+  login: (user: User, token: string, refreshToken: string) => void;
+  setTokens: (token: string, refreshToken: string) => void;
   logout: () => void;
   updateUser: (user: User) => void;
   hasRole: (roleName: string) => boolean;
@@ -18,17 +20,25 @@ export const useAuthStore = create<AuthState>()(
     (set, get) => ({
       user: null,
       token: null,
+      refreshToken: null,
       isAuthenticated: false,
-      login: (user: User, token: string) =>
+      login: (user: User, token: string, refreshToken: string) =>
         set({
           user,
           token,
+          refreshToken,
           isAuthenticated: true,
         }),
+      setTokens: (token: string, refreshToken: string) =>
+        set({
+          token,
+          refreshToken,
+        }),
       logout: () =>
         set({
           user: null,
           token: null,
+          refreshToken: null,
           isAuthenticated: false,
         }),
       updateUser: (user: User) =>
@@ -50,12 +60,20 @@ export const useAuthStore = create<AuthState>()(
       partialize: (state) => ({
         user: state.user,
         token: state.token,
+        refreshToken: state.refreshToken,
         isAuthenticated: state.isAuthenticated,
       }),
     }
   )
 );
 
+// Keep tabs in step: tokens rotated or cleared in one tab are picked up by the others.
+window.addEventListener('storage', (event) => {
+  if (event.key === 'auth-storage') {
+    useAuthStore.persist.rehydrate();
+  }
+});
+
 export const useAuth = () => {
   const { user, token, isAuthenticated, login, logout, updateUser, hasRole, hasAnyRole } = useAuthStore();
   
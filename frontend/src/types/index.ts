@@ -3,9 +3,19 @@ export interface User {
   username: string
   email: string
   roles: Role[]
+  hide_online_status?: boolean
+  locale?: string
+  email_verified_at?: string | null
   created_at: string
 }
 
+export interface Presence {
+  user_id: number
+  online: boolean
+  last_seen_at?: string
+  hidden: boolean
+}
+
 export interface Role {
   id: number
   name: string
@@ -15,6 +25,8 @@ export interface Role {
 export interface Product {
   id: number
   seller_id: number
+  sku?: string
+  slug?: string
   title: string
   description: string
   price: number // Price in cents, will be converted to dollars in UI
@@ -24,6 +36,9 @@ export interface Product {
   disposable: boolean
   max_sales: number
   sold_count: number
+  moderation_status: 'pending_review' | 'approved' | 'rejected'
+  rejection_code?: string
+  rejection_reason?: string
   created_at: string
   updated_at: string
 }
@@ -31,6 +46,7 @@ export interface Product {
 export interface Category {
   id: number
   name: string
+  slug?: string
   description: string
 }
 
@@ -40,10 +56,22 @@ export interface Order {
   product_id?: number
   product?: Product
   status: string
+  conversation_id?: number
   created_at: string
   updated_at: string
 }
 
+export interface Attachment {
+  id: number
+  conversation_id: number
+  message_id?: number | null
+  uploader_id: number
+  file_name: string
+  content_type: string
+  size: number
+  created_at: string
+}
+
 export interface Message {
   id: number
   conversation_id: number
@@ -52,15 +80,39 @@ export interface Message {
   order_id?: number
   text: string
   is_system?: boolean
+  is_deleted?: boolean
+  edited_at?: string
+  attachments?: Attachment[]
   created_at: string
 }
 
+export interface MessagePage {
+  messages: Message[]
+  has_older: boolean
+  has_newer: boolean
+}
+
+export interface MessageSearchResult {
+  message_id: number
+  conversation_id: number
+  sender_id?: number
+  sent_at: string
+  // Raw message text with matches wrapped in <mark></mark>; never render it as HTML
+  snippet: string
+  conversation?: Conversation
+}
+
 export interface Conversation {
   id: number
   product_id?: number
   product?: Product
+  buyer_id?: number
   participants: Participant[]
-  messages: Message[]
+  messages?: Message[]
+  last_message?: string
+  last_message_time?: string
+  order_id?: number
+  unread_count?: number
   created_at: string
   updated_at: string
 }
@@ -71,9 +123,45 @@ export interface Participant {
   user_id: number
   user: User
   role: string
+  last_read_message_id?: number | null
+  last_read_at?: string | null
+  created_at: string
+}
+
+export type NotificationType = 'order_status' | 'new_message' | 'product_moderation' | 'message_moderation'
+
+export interface Notification {
+  id: number
+  user_id: number
+  type: NotificationType
+  payload: Record<string, unknown>
+  link: string
+  is_read: boolean
+  read_at?: string
   created_at: string
 }
 
+export interface NotificationPage {
+  notifications: Notification[]
+  total: number
+  unread: number
+}
+
+export type NotificationChannel = 'in_app' | 'email' | 'webhook'
+
+export interface NotificationPreferences {
+  // Every event type maps to the channels it supports; false turns that channel off
+  channels: Record<string, Partial<Record<NotificationChannel, boolean>>>
+  timezone: string
+  // "HH:MM" in the user's timezone; empty strings disable quiet hours
+  quiet_hours_start: string
+  quiet_hours_end: string
+  digest_frequency: 'off' | 'daily' | 'weekly'
+  last_digest_at?: string
+  // Zero time until the user saves preferences for the first time
+  updated_at: string
+}
+
 export interface AuthRequest {
   username: string
   password: string
@@ -81,9 +169,22 @@ export interface AuthRequest {
 
 export interface AuthResponse {
   token: string
+  refresh_token: string
+  // Access token lifetime in seconds
+  expires_in: number
   user: User
 }
 
+export interface Session {
+  id: string
+  user_agent: string
+  ip: string
+  started_at: string
+  last_used_at: string
+  expires_at: string
+  current: boolean
+}
+
 export interface ApiResponse<T> {
   success: boolean
   data?: T
@@ -2,13 +2,23 @@ package container
 
 import (
 	"MicroShopik/configs"
+	"MicroShopik/internal/contentfilter"
 	"MicroShopik/internal/controllers"
 	"MicroShopik/internal/database"
 	"MicroShopik/internal/domain"
+	"MicroShopik/internal/events"
+	"MicroShopik/internal/jobqueue"
+	"MicroShopik/internal/mailer"
+	"MicroShopik/internal/realtime"
 	"MicroShopik/internal/repositories"
+	"MicroShopik/internal/scheduler"
 	"MicroShopik/internal/services/application"
 	sdomain "MicroShopik/internal/services/domain"
+	"MicroShopik/internal/storage"
+	"context"
+	"fmt"
 	"log"
+	"time"
 )
 
 type Container struct {
@@ -21,7 +31,34 @@ type Container struct {
 	OrderRepository        domain.OrderRepository
 	MessageRepository      domain.MessageRepository
 
+	ProductModerationLogRepository   domain.ProductModerationLogRepository
+	ProductImportJobRepository       domain.ProductImportJobRepository
+	ProductRevisionRepository        domain.ProductRevisionRepository
+	ProductRecommendationRepository  domain.ProductRecommendationRepository
+	SlugRepository                   domain.SlugRepository
+	AttachmentRepository             domain.AttachmentRepository
+	MessageReportRepository          domain.MessageReportRepository
+	UserSanctionRepository           domain.UserSanctionRepository
+	OutboxRepository                 domain.OutboxRepository
+	NotificationRepository           domain.NotificationRepository
+	NotificationPreferenceRepository domain.NotificationPreferenceRepository
+	WebhookEndpointRepository        domain.WebhookEndpointRepository
+	WebhookDeliveryRepository        domain.WebhookDeliveryRepository
+	JobRunRepository                 domain.JobRunRepository
+	QueuedJobRepository              domain.QueuedJobRepository
+	SessionRepository                domain.SessionRepository
+
+	RealtimeHub *realtime.Hub
+	EventBus    *events.Bus
+	OutboxRelay *sdomain.OutboxRelay
+
+	Scheduler         *scheduler.Scheduler
+	WebhookDispatcher *sdomain.WebhookDispatcher
+	JobQueue          *jobqueue.Queue
+	JobWorkerPool     *jobqueue.Pool
+
 	UserService         sdomain.UserService
+	SessionService      sdomain.SessionService
 	RoleService         sdomain.RoleService
 	ProductService      sdomain.ProductService
 	CategoryService     sdomain.CategoryService
@@ -30,12 +67,27 @@ type Container struct {
 	OrderService        sdomain.OrderService
 	MessageService      sdomain.MessageService
 
-	OrderApplicationService        *application.OrderApplicationService
-	UserApplicationService         *application.UserApplicationService
-	ProductApplicationService      *application.ProductApplicationService
-	ConversationApplicationService *application.ConversationApplicationService
+	ProductModerationService      sdomain.ProductModerationService
+	RecommendationService         sdomain.RecommendationService
+	SlugService                   sdomain.SlugService
+	AttachmentService             sdomain.AttachmentService
+	MessageModerationService      sdomain.MessageModerationService
+	OutboxService                 sdomain.OutboxService
+	EmailService                  sdomain.EmailService
+	EmailVerificationService      sdomain.EmailVerificationService
+	NotificationService           sdomain.NotificationService
+	NotificationPreferenceService sdomain.NotificationPreferenceService
+	WebhookService                sdomain.WebhookService
+
+	OrderApplicationService         *application.OrderApplicationService
+	UserApplicationService          *application.UserApplicationService
+	ProductApplicationService       *application.ProductApplicationService
+	ConversationApplicationService  *application.ConversationApplicationService
+	ProductImportApplicationService *application.ProductImportApplicationService
+	SitemapApplicationService       *application.SitemapApplicationService
 
 	UserController         *controllers.UserController
+	SessionController      *controllers.SessionController
 	RoleController         *controllers.RoleController
 	ProductController      *controllers.ProductController
 	CategoryController     *controllers.CategoryController
@@ -43,6 +95,20 @@ type Container struct {
 	ParticipantController  *controllers.ParticipantController
 	OrderController        *controllers.OrderController
 	MessageController      *controllers.MessageController
+
+	EmailVerificationController *controllers.EmailVerificationController
+	ProductModerationController *controllers.ProductModerationController
+	ProductImportController     *controllers.ProductImportController
+	SitemapController           *controllers.SitemapController
+	RealtimeController          *controllers.RealtimeController
+	AttachmentController        *controllers.AttachmentController
+	PresenceController          *controllers.PresenceController
+	MessageModerationController *controllers.MessageModerationController
+	OutboxController            *controllers.OutboxController
+	NotificationController      *controllers.NotificationController
+	WebhookController           *controllers.WebhookController
+	JobController               *controllers.JobController
+	QueueController             *controllers.QueueController
 }
 
 func NewContainer() *Container {
@@ -60,27 +126,86 @@ func NewContainer() *Container {
 	participantRepo := repositories.NewParticipantRepository(db)
 	orderRepo := repositories.NewOrderRepository(db)
 	messageRepo := repositories.NewMessageRepository(db)
+	productModerationLogRepo := repositories.NewProductModerationLogRepository(db)
+	productImportJobRepo := repositories.NewProductImportJobRepository(db)
+	productRevisionRepo := repositories.NewProductRevisionRepository(db)
+	productRecommendationRepo := repositories.NewProductRecommendationRepository(db)
+	slugRepo := repositories.NewSlugRepository(db)
+	attachmentRepo := repositories.NewAttachmentRepository(db)
+	messageReportRepo := repositories.NewMessageReportRepository(db)
+	userSanctionRepo := repositories.NewUserSanctionRepository(db)
+	outboxRepo := repositories.NewOutboxRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	notificationPreferenceRepo := repositories.NewNotificationPreferenceRepository(db)
+	webhookEndpointRepo := repositories.NewWebhookEndpointRepository(db)
+	webhookDeliveryRepo := repositories.NewWebhookDeliveryRepository(db)
+	jobRunRepo := repositories.NewJobRunRepository(db)
+	queuedJobRepo := repositories.NewQueuedJobRepository(db)
+	sessionRepo := repositories.NewSessionRepository(db)
+
+	attachmentStorage, err := storage.NewLocalStorage(cfg.AttachmentsDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	messageFilter, err := newMessageFilter(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	emailRenderer, err := mailer.NewRenderer()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	emailTransport, err := newMailer(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	userService := sdomain.NewUserService(userRepo, cfg.JWTSecret)
-	roleService := sdomain.NewRoleService(roleRepo, userRepo)
-	productService := sdomain.NewProductService(productRepo)
-	categoryService := sdomain.NewCategoryService(categoryRepo)
-	participantService := sdomain.NewParticipantService(participantRepo, conversationRepo, userRepo)
-	conversationService := sdomain.NewConversationService(conversationRepo, participantRepo, userRepo)
-	messageService := sdomain.NewMessageService(messageRepo, conversationRepo, participantRepo, orderRepo)
-	orderService := sdomain.NewOrderService(orderRepo)
+	realtimeHub := realtime.NewHub(participantRepo, messageRepo, userRepo)
+	eventBus := events.NewBus()
+	outboxRelay := sdomain.NewOutboxRelay(outboxRepo, time.Duration(cfg.OutboxPollIntervalSeconds)*time.Second, cfg.OutboxMaxAttempts)
+
+	sessionService := sdomain.NewSessionService(
+		sessionRepo,
+		userRepo,
+		cfg.JWTSecret,
+		time.Duration(cfg.AccessTokenTTLMinutes)*time.Minute,
+		time.Duration(cfg.RefreshTokenTTLDays)*24*time.Hour,
+		realtimeHub,
+	)
+	userService := sdomain.NewUserService(userRepo, sessionService)
+	roleService := sdomain.NewRoleService(roleRepo, userRepo, sessionService)
+	slugService := sdomain.NewSlugService(slugRepo)
+	attachmentService := sdomain.NewAttachmentService(attachmentRepo, participantRepo, attachmentStorage, int64(cfg.AttachmentMaxSizeMB)<<20)
+	productService := sdomain.NewProductService(productRepo, productRevisionRepo, slugService)
+	categoryService := sdomain.NewCategoryService(categoryRepo, slugService)
+	participantService := sdomain.NewParticipantService(participantRepo, conversationRepo, userRepo, realtimeHub)
+	conversationService := sdomain.NewConversationService(conversationRepo, participantRepo, userRepo, realtimeHub)
+	messageService := sdomain.NewMessageService(messageRepo, conversationRepo, participantRepo, orderRepo, attachmentRepo, attachmentService, messageReportRepo, userSanctionRepo, messageFilter, realtimeHub, eventBus, time.Duration(cfg.MessageEditWindowMinutes)*time.Minute)
+	outboxService := sdomain.NewOutboxService(outboxRepo)
+	orderService := sdomain.NewOrderService(orderRepo, outboxService)
+	notificationPreferenceService := sdomain.NewNotificationPreferenceService(notificationPreferenceRepo)
+	notificationService := sdomain.NewNotificationService(notificationRepo, notificationPreferenceService, realtimeHub)
+	webhookService := sdomain.NewWebhookService(webhookEndpointRepo, webhookDeliveryRepo, notificationPreferenceService, time.Duration(cfg.WebhookTimeoutSeconds)*time.Second, cfg.WebhookMaxAttempts, cfg.WebhookAllowPrivateHosts)
+	emailService := sdomain.NewEmailService(emailRenderer, emailTransport, outboxService, notificationPreferenceService, cfg.SiteBaseURL)
+	emailVerificationService := sdomain.NewEmailVerificationService(userRepo, emailService, cfg.JWTSecret, time.Duration(cfg.EmailVerificationTTLHours)*time.Hour)
+	productModerationService := sdomain.NewProductModerationService(productRepo, productModerationLogRepo, cfg.ProductModerationEnabled)
+	recommendationService := sdomain.NewRecommendationService(productRecommendationRepo, productRepo)
+	presenceService := sdomain.NewPresenceService(userRepo, realtimeHub)
+	messageModerationService := sdomain.NewMessageModerationService(messageRepo, participantRepo, messageReportRepo, userSanctionRepo, attachmentService, realtimeHub, eventBus)
 
 	orderAppService := application.NewOrderApplicationService(
 		orderService,
 		productService,
 		userService,
-		conversationService,
-		messageService,
 	)
 
 	userAppService := application.NewUserApplicationService(
 		userService,
 		roleService,
+		eventBus,
 	)
 
 	productAppService := application.NewProductApplicationService(
@@ -88,6 +213,10 @@ func NewContainer() *Container {
 		categoryService,
 		userService,
 		orderService,
+		productModerationService,
+		recommendationService,
+		slugService,
+		eventBus,
 	)
 
 	conversationAppService := application.NewConversationApplicationService(
@@ -98,14 +227,117 @@ func NewContainer() *Container {
 		participantService,
 	)
 
+	jobQueue := jobqueue.NewQueue(queuedJobRepo, cfg.JobMaxAttempts)
+
+	productImportAppService := application.NewProductImportApplicationService(
+		productAppService,
+		productService,
+		categoryService,
+		productImportJobRepo,
+		jobQueue,
+		cfg.ProductImportAsyncThreshold,
+	)
+	jobQueue.Handle(application.ProductImportJobType, jobqueue.Typed(productImportAppService.RunImportJob))
+	jobQueue.HandleDead(application.ProductImportJobType, jobqueue.TypedDead(productImportAppService.ImportJobDied))
+
+	jobWorkerPool := jobqueue.NewPool(jobQueue, cfg.JobWorkers, time.Duration(cfg.JobPollIntervalSeconds)*time.Second)
+
+	sitemapAppService := application.NewSitemapApplicationService(slugService, cfg.SiteBaseURL, cfg.PublicAPIURL, cfg.SitemapPageSize)
+
+	// Reactions to domain events. Bus subscribers run in-process when the event is published;
+	// outbox handlers run from the relay, at least once, after the change is committed.
+	orderChatSubscriber := application.NewOrderChatSubscriber(orderService, productService, conversationService, messageService)
+	outboxRelay.Handle(events.OrderStatusChangedEvent, "order chat", orderChatSubscriber.OnOrderStatusChanged)
+
+	emailSubscriber := application.NewEmailSubscriber(emailService, emailVerificationService, orderService)
+	eventBus.Subscribe(events.UserRegisteredEvent, "verification email", events.Sync, emailSubscriber.OnUserRegistered)
+	eventBus.Subscribe(events.UserEmailChangedEvent, "verification email", events.Sync, emailSubscriber.OnUserEmailChanged)
+	outboxRelay.Handle(events.OrderCreatedEvent, "order email", emailSubscriber.OnOrderChanged)
+	outboxRelay.Handle(events.OrderStatusChangedEvent, "order email", emailSubscriber.OnOrderChanged)
+	outboxRelay.Handle(events.EmailQueuedEvent, "mailer", emailService.Deliver)
+
+	notificationSubscriber := application.NewNotificationSubscriber(notificationService, orderService, participantService)
+	eventBus.Subscribe(events.MessageSentEvent, "message notification", events.Async, notificationSubscriber.OnMessageSent)
+	eventBus.Subscribe(events.ProductModeratedEvent, "moderation notification", events.Async, notificationSubscriber.OnProductModerated)
+	eventBus.Subscribe(events.MessageModeratedEvent, "moderation notification", events.Async, notificationSubscriber.OnMessageModerated)
+	outboxRelay.Handle(events.OrderCreatedEvent, "order notification", notificationSubscriber.OnOrderChanged)
+	outboxRelay.Handle(events.OrderStatusChangedEvent, "order notification", notificationSubscriber.OnOrderChanged)
+
+	webhookSubscriber := application.NewWebhookSubscriber(webhookService, orderService, participantService)
+	eventBus.Subscribe(events.MessageSentEvent, "seller webhooks", events.Async, webhookSubscriber.OnMessageSent)
+	outboxRelay.Handle(events.OrderCreatedEvent, "seller webhooks", webhookSubscriber.OnOrderChanged)
+	outboxRelay.Handle(events.OrderStatusChangedEvent, "seller webhooks", webhookSubscriber.OnOrderChanged)
+	webhookDispatcher := sdomain.NewWebhookDispatcher(webhookService, time.Duration(cfg.WebhookPollIntervalSeconds)*time.Second)
+
+	// Periodic jobs. The outbox relay and the webhook dispatcher poll every few seconds and
+	// already share work through SKIP LOCKED, so they keep their own loops.
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal(err)
+	}
+	jobScheduler := scheduler.NewScheduler(scheduler.NewPostgresLocker(sqlDB), jobRunRepo)
+
+	if cfg.KeepAliveEnabled {
+		keepAliveService := sdomain.NewKeepAliveService(cfg.KeepAliveURL)
+		jobScheduler.Register("keepalive", "Ping the server's health endpoint so the host keeps it awake",
+			scheduler.Every(time.Duration(cfg.KeepAliveInterval)*time.Minute), keepAliveService.Ping)
+	}
+
+	jobScheduler.Register("co_purchase_matrix", "Rebuild the co-purchase matrix behind product recommendations",
+		scheduler.Every(time.Duration(cfg.RecommendationsInterval)*time.Minute), func(ctx context.Context) error {
+			return recommendationService.RebuildCoPurchaseMatrix()
+		})
+	jobScheduler.RunAtStart("co_purchase_matrix")
+
+	jobScheduler.Register("session_cleanup", "Delete expired refresh tokens",
+		scheduler.MustParse("@daily"), func(ctx context.Context) error {
+			deleted, err := sessionService.DeleteExpired()
+			if err == nil && deleted > 0 {
+				log.Printf("Deleted %d expired sessions", deleted)
+			}
+			return err
+		})
+
+	notificationDigestJob := sdomain.NewNotificationDigestJob(notificationPreferenceService, notificationService, userRepo, emailService, cfg.SiteBaseURL)
+	jobScheduler.Register("notification_digest", "Email daily and weekly notification digests",
+		scheduler.MustParse("@hourly"), notificationDigestJob.Run)
+
+	if cfg.UnreadEmailDelayMinutes > 0 {
+		unreadMessageEmailJob := sdomain.NewUnreadMessageEmailJob(
+			participantRepo,
+			messageRepo,
+			conversationRepo,
+			userRepo,
+			realtimeHub,
+			emailService,
+			time.Duration(cfg.UnreadEmailDelayMinutes)*time.Minute,
+		)
+		jobScheduler.Register("unread_message_email", "Email participants about chat messages left unread",
+			scheduler.Every(5*time.Minute), unreadMessageEmailJob.Run)
+	}
+
 	userController := controllers.NewUserController(userAppService)
+	sessionController := controllers.NewSessionController(sessionService)
+	emailVerificationController := controllers.NewEmailVerificationController(emailVerificationService)
 	roleController := controllers.NewRoleController(roleService)
 	productController := controllers.NewProductController(productAppService)
-	categoryController := controllers.NewCategoryController(categoryService)
+	categoryController := controllers.NewCategoryController(categoryService, slugService)
 	conversationController := controllers.NewConversationController(conversationAppService)
 	participantController := controllers.NewParticipantController(participantService)
 	orderController := controllers.NewOrderController(orderAppService)
 	messageController := controllers.NewMessageController(messageService)
+	productModerationController := controllers.NewProductModerationController(productAppService)
+	productImportController := controllers.NewProductImportController(productImportAppService)
+	sitemapController := controllers.NewSitemapController(sitemapAppService)
+	realtimeController := controllers.NewRealtimeController(realtimeHub)
+	attachmentController := controllers.NewAttachmentController(attachmentService)
+	presenceController := controllers.NewPresenceController(presenceService)
+	messageModerationController := controllers.NewMessageModerationController(messageModerationService)
+	outboxController := controllers.NewOutboxController(outboxService)
+	notificationController := controllers.NewNotificationController(notificationService, notificationPreferenceService)
+	webhookController := controllers.NewWebhookController(webhookService)
+	jobController := controllers.NewJobController(jobScheduler)
+	queueController := controllers.NewQueueController(jobQueue)
 
 	return &Container{
 		UserRepository:         userRepo,
@@ -117,7 +349,34 @@ func NewContainer() *Container {
 		OrderRepository:        orderRepo,
 		MessageRepository:      messageRepo,
 
+		ProductModerationLogRepository:   productModerationLogRepo,
+		ProductImportJobRepository:       productImportJobRepo,
+		ProductRevisionRepository:        productRevisionRepo,
+		ProductRecommendationRepository:  productRecommendationRepo,
+		SlugRepository:                   slugRepo,
+		AttachmentRepository:             attachmentRepo,
+		MessageReportRepository:          messageReportRepo,
+		UserSanctionRepository:           userSanctionRepo,
+		OutboxRepository:                 outboxRepo,
+		NotificationRepository:           notificationRepo,
+		NotificationPreferenceRepository: notificationPreferenceRepo,
+		WebhookEndpointRepository:        webhookEndpointRepo,
+		WebhookDeliveryRepository:        webhookDeliveryRepo,
+		JobRunRepository:                 jobRunRepo,
+		QueuedJobRepository:              queuedJobRepo,
+		SessionRepository:                sessionRepo,
+
+		RealtimeHub: realtimeHub,
+		EventBus:    eventBus,
+		OutboxRelay: outboxRelay,
+
+		Scheduler:         jobScheduler,
+		WebhookDispatcher: webhookDispatcher,
+		JobQueue:          jobQueue,
+		JobWorkerPool:     jobWorkerPool,
+
 		UserService:         userService,
+		SessionService:      sessionService,
 		RoleService:         roleService,
 		ProductService:      productService,
 		CategoryService:     categoryService,
@@ -126,12 +385,27 @@ func NewContainer() *Container {
 		OrderService:        orderService,
 		MessageService:      messageService,
 
-		OrderApplicationService:        orderAppService,
-		UserApplicationService:         userAppService,
-		ProductApplicationService:      productAppService,
-		ConversationApplicationService: conversationAppService,
+		ProductModerationService:      productModerationService,
+		RecommendationService:         recommendationService,
+		SlugService:                   slugService,
+		AttachmentService:             attachmentService,
+		MessageModerationService:      messageModerationService,
+		OutboxService:                 outboxService,
+		EmailService:                  emailService,
+		EmailVerificationService:      emailVerificationService,
+		NotificationService:           notificationService,
+		NotificationPreferenceService: notificationPreferenceService,
+		WebhookService:                webhookService,
+
+		OrderApplicationService:         orderAppService,
+		UserApplicationService:          userAppService,
+		ProductApplicationService:       productAppService,
+		ConversationApplicationService:  conversationAppService,
+		ProductImportApplicationService: productImportAppService,
+		SitemapApplicationService:       sitemapAppService,
 
 		UserController:         userController,
+		SessionController:      sessionController,
 		RoleController:         roleController,
 		ProductController:      productController,
 		CategoryController:     categoryController,
@@ -139,5 +413,56 @@ func NewContainer() *Container {
 		ParticipantController:  participantController,
 		OrderController:        orderController,
 		MessageController:      messageController,
+
+		EmailVerificationController: emailVerificationController,
+		ProductModerationController: productModerationController,
+		ProductImportController:     productImportController,
+		SitemapController:           sitemapController,
+		RealtimeController:          realtimeController,
+		PresenceController:          presenceController,
+		AttachmentController:        attachmentController,
+		MessageModerationController: messageModerationController,
+		OutboxController:            outboxController,
+		NotificationController:      notificationController,
+		WebhookController:           webhookController,
+		JobController:               jobController,
+		QueueController:             queueController,
+	}
+}
+
+// newMessageFilter builds the chat content filter from the CHAT_FILTER_* settings.
+func newMessageFilter(cfg *configs.Config) (contentfilter.Filter, error) {
+	actions := make(map[string]contentfilter.Action, 4)
+	for key, value := range map[string]string{
+		"CHAT_FILTER_PHONE":        cfg.ChatFilterPhone,
+		"CHAT_FILTER_EMAIL":        cfg.ChatFilterEmail,
+		"CHAT_FILTER_URL":          cfg.ChatFilterURL,
+		"CHAT_FILTER_BANNED_WORDS": cfg.ChatFilterBannedWords,
+	} {
+		action, err := contentfilter.ParseAction(value)
+		if err != nil {
+			return nil, fmt.Errorf("incorrect %s: %v", key, err)
+		}
+		actions[key] = action
+	}
+
+	return contentfilter.NewRuleFilter(
+		contentfilter.PhoneRule(actions["CHAT_FILTER_PHONE"]),
+		contentfilter.EmailRule(actions["CHAT_FILTER_EMAIL"]),
+		contentfilter.URLRule(actions["CHAT_FILTER_URL"]),
+		contentfilter.WordsRule("banned word", actions["CHAT_FILTER_BANNED_WORDS"], cfg.ChatBannedWords),
+	), nil
+}
+
+// newMailer picks the email transport from MAIL_TRANSPORT: "smtp" for real delivery, "file"
+// to write .eml files into MAIL_DIR during development.
+func newMailer(cfg *configs.Config) (mailer.Mailer, error) {
+	switch cfg.MailTransport {
+	case "smtp":
+		return mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	case "file":
+		return mailer.NewFileMailer(cfg.MailDir, cfg.MailFrom)
+	default:
+		return nil, fmt.Errorf("incorrect MAIL_TRANSPORT: %q", cfg.MailTransport)
 	}
 }
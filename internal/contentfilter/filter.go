@@ -0,0 +1,119 @@
+package contentfilter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Action is what happens to a message that matches a rule. Actions are ordered by severity,
+// so the strongest action among all matching rules wins.
+type Action int
+
+const (
+	Allow Action = iota
+	Mask
+	Flag
+	Block
+)
+
+// MaskText replaces every masked fragment of a message.
+const MaskText = "***"
+
+var actionNames = map[Action]string{
+	Allow: "allow",
+	Mask:  "mask",
+	Flag:  "flag",
+	Block: "block",
+}
+
+func (a Action) String() string {
+	return actionNames[a]
+}
+
+func ParseAction(name string) (Action, error) {
+	for action, n := range actionNames {
+		if strings.EqualFold(strings.TrimSpace(name), n) {
+			return action, nil
+		}
+	}
+	return Allow, fmt.Errorf("unknown content filter action %q", name)
+}
+
+// Result describes what the filter decided about a message. Content has the masked fragments
+// replaced; Matched lists the names of every rule that matched, for messages and audit notes.
+type Result struct {
+	Action  Action
+	Content string
+	Matched []string
+}
+
+// Filter inspects message text before it is saved. Implementations must be safe for concurrent use.
+type Filter interface {
+	Check(content string) Result
+}
+
+type RuleFilter struct {
+	rules []Rule
+}
+
+func NewRuleFilter(rules ...Rule) *RuleFilter {
+	return &RuleFilter{rules: rules}
+}
+
+// Check applies masking rules first and runs the remaining rules on the masked text,
+// so an email address that was already hidden doesn't also count as a link.
+func (f *RuleFilter) Check(content string) Result {
+	result := Result{Action: Allow, Content: content}
+
+	var masked [][2]int
+	for _, rule := range f.rules {
+		if rule.Action != Mask {
+			continue
+		}
+		if ranges := rule.find(content); len(ranges) > 0 {
+			result.Matched = append(result.Matched, rule.Name)
+			result.Action = Mask
+			masked = append(masked, ranges...)
+		}
+	}
+	if len(masked) > 0 {
+		result.Content = mask(content, masked)
+	}
+
+	for _, rule := range f.rules {
+		if rule.Action <= Mask {
+			continue
+		}
+		if ranges := rule.find(result.Content); len(ranges) > 0 {
+			result.Matched = append(result.Matched, rule.Name)
+			result.Action = max(result.Action, rule.Action)
+		}
+	}
+	return result
+}
+
+// mask replaces the byte ranges, merging any that overlap or touch.
+func mask(content string, ranges [][2]int) string {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+
+	merged := [][2]int{ranges[0]}
+	for _, r := range ranges[1:] {
+		current := &merged[len(merged)-1]
+		if r[0] <= current[1] {
+			current[1] = max(current[1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, r := range merged {
+		b.WriteString(content[last:r[0]])
+		b.WriteString(MaskText)
+		last = r[1]
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
@@ -0,0 +1,96 @@
+package contentfilter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		name   string
+		ranges [][2]int
+		want   string
+	}{
+		{"single", [][2]int{{1, 3}}, "a***def"},
+		{"disjoint and unsorted", [][2]int{{4, 5}, {0, 1}}, "***bcd***f"},
+		{"overlapping", [][2]int{{1, 4}, {2, 5}}, "a***f"},
+		{"touching", [][2]int{{1, 3}, {3, 5}}, "a***f"},
+		{"contained", [][2]int{{0, 6}, {2, 3}}, "***"},
+		{"chain merged out of order", [][2]int{{3, 4}, {0, 2}, {1, 3}}, "***ef"},
+		{"whole content", [][2]int{{0, 6}}, "***"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mask("abcdef", tt.ranges); got != tt.want {
+				t.Errorf("mask(%q, %v) = %q, want %q", "abcdef", tt.ranges, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleFilterCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []Rule
+		content string
+		want    Result
+	}{
+		{
+			name:    "nothing matches",
+			rules:   []Rule{PhoneRule(Mask), URLRule(Flag)},
+			content: "the key costs 1500 rub",
+			want:    Result{Action: Allow, Content: "the key costs 1500 rub"},
+		},
+		{
+			name:    "phone masked",
+			rules:   []Rule{PhoneRule(Mask)},
+			content: "call me at +7 (912) 345-67-89 please",
+			want:    Result{Action: Mask, Content: "call me at *** please", Matched: []string{"phone number"}},
+		},
+		{
+			name:    "masked email no longer counts as a link",
+			rules:   []Rule{EmailRule(Mask), URLRule(Flag)},
+			content: "write to bob@mail.ru",
+			want:    Result{Action: Mask, Content: "write to ***", Matched: []string{"email address"}},
+		},
+		{
+			name:    "overlapping masks merge into one",
+			rules:   []Rule{EmailRule(Mask), URLRule(Mask)},
+			content: "bob@mail.ru or later",
+			want:    Result{Action: Mask, Content: "*** or later", Matched: []string{"email address", "link"}},
+		},
+		{
+			name:    "strongest action wins",
+			rules:   []Rule{URLRule(Flag), WordsRule("banned words", Block, []string{"scam"})},
+			content: "Scam at www.example.com",
+			want:    Result{Action: Block, Content: "Scam at www.example.com", Matched: []string{"link", "banned words"}},
+		},
+		{
+			name:    "flag runs on the masked text",
+			rules:   []Rule{PhoneRule(Mask), WordsRule("banned words", Flag, []string{"telegram"})},
+			content: "telegram 89123456789",
+			want:    Result{Action: Flag, Content: "telegram ***", Matched: []string{"phone number", "banned words"}},
+		},
+		{
+			name:    "cyrillic words",
+			rules:   []Rule{WordsRule("banned words", Mask, []string{" Обман "})},
+			content: "Это обман!",
+			want:    Result{Action: Mask, Content: "Это ***!", Matched: []string{"banned words"}},
+		},
+		{
+			name:    "words match whole words only",
+			rules:   []Rule{WordsRule("banned words", Block, []string{"scam"})},
+			content: "scammers beware",
+			want:    Result{Action: Allow, Content: "scammers beware"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewRuleFilter(tt.rules...).Check(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check(%q) = %+v, want %+v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
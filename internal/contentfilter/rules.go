@@ -0,0 +1,84 @@
+package contentfilter
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Rule finds fragments of a message that call for an action.
+type Rule struct {
+	Name   string
+	Action Action
+	find   func(content string) [][2]int
+}
+
+var (
+	// Ten to fifteen digits with the usual separators, so prices and order numbers don't match.
+	phonePattern = regexp.MustCompile(`\+?\d(?:[ \-().]{0,2}\d){9,14}`)
+	emailPattern = regexp.MustCompile(`(?i)[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}`)
+	urlPattern   = regexp.MustCompile(`(?i)\b(?:https?://|www\.|t\.me/)\S+|\b[a-z0-9\-]+\.(?:com|net|org|ru|io|me|su|info|biz)\b(?:/\S*)?`)
+)
+
+func PatternRule(name string, action Action, pattern *regexp.Regexp) Rule {
+	return Rule{
+		Name:   name,
+		Action: action,
+		find: func(content string) [][2]int {
+			var ranges [][2]int
+			for _, loc := range pattern.FindAllStringIndex(content, -1) {
+				ranges = append(ranges, [2]int{loc[0], loc[1]})
+			}
+			return ranges
+		},
+	}
+}
+
+func PhoneRule(action Action) Rule {
+	return PatternRule("phone number", action, phonePattern)
+}
+
+func EmailRule(action Action) Rule {
+	return PatternRule("email address", action, emailPattern)
+}
+
+func URLRule(action Action) Rule {
+	return PatternRule("link", action, urlPattern)
+}
+
+// WordsRule matches whole words case-insensitively. It splits on anything that isn't a letter or digit
+// rather than using \b, which only understands ASCII and would miss Cyrillic words.
+func WordsRule(name string, action Action, words []string) Rule {
+	banned := make(map[string]bool, len(words))
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			banned[word] = true
+		}
+	}
+
+	return Rule{
+		Name:   name,
+		Action: action,
+		find: func(content string) [][2]int {
+			if len(banned) == 0 {
+				return nil
+			}
+
+			var ranges [][2]int
+			start := -1
+			for i, r := range content + " " {
+				isWordChar := unicode.IsLetter(r) || unicode.IsDigit(r)
+				if isWordChar && start < 0 {
+					start = i
+				}
+				if !isWordChar && start >= 0 {
+					if banned[strings.ToLower(content[start:i])] {
+						ranges = append(ranges, [2]int{start, i})
+					}
+					start = -1
+				}
+			}
+			return ranges
+		},
+	}
+}
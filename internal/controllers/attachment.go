@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	domain2 "MicroShopik/internal/services/domain"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AttachmentController struct {
+	attachmentService domain2.AttachmentService
+}
+
+func NewAttachmentController(s domain2.AttachmentService) *AttachmentController {
+	return &AttachmentController{attachmentService: s}
+}
+
+// Upload @Summary Upload an attachment
+// @Description Upload a file to a conversation (participants only). Send its ID in attachment_ids of the next message.
+// @Description Images, PDF, plain text and ZIP files are accepted; the type is detected from the content.
+// @Tags messages
+// @Accept multipart/form-data
+// @Produce json
+// @Param conversationID path int true "Conversation ID"
+// @Param file formData file true "File to attach"
+// @Success 201 {object} domain.Attachment
+// @Failure 400 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /conversations/{conversationID}/attachments [post]
+func (ac *AttachmentController) Upload(c echo.Context) error {
+	conversationID, err := strconv.Atoi(c.Param("conversationID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid conversation id"})
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "file is required"})
+	}
+	if file.Size > ac.attachmentService.MaxSize() {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": domain2.ErrAttachmentTooLarge.Error()})
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to open uploaded file"})
+	}
+	defer src.Close()
+
+	attachment, err := ac.attachmentService.Upload(conversationID, c.Get("user_id").(int), file.Filename, src)
+	if err != nil {
+		if errors.Is(err, domain2.ErrAttachmentTooLarge) {
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, attachment)
+}
+
+// Download @Summary Download an attachment
+// @Description Download a conversation attachment (participants only)
+// @Tags messages
+// @Produce octet-stream
+// @Param conversationID path int true "Conversation ID"
+// @Param id path int true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /conversations/{conversationID}/attachments/{id} [get]
+func (ac *AttachmentController) Download(c echo.Context) error {
+	conversationID, err := strconv.Atoi(c.Param("conversationID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid conversation id"})
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid attachment id"})
+	}
+
+	// Missing and forbidden look the same so attachment IDs can't be probed.
+	attachment, content, err := ac.attachmentService.Open(id, c.Get("user_id").(int))
+	if err != nil || attachment.ConversationID != conversationID {
+		if content != nil {
+			content.Close()
+		}
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "attachment not found"})
+	}
+	defer content.Close()
+
+	disposition := "attachment"
+	if strings.HasPrefix(attachment.ContentType, "image/") {
+		disposition = "inline"
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}))
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(attachment.Size, 10))
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Cache-Control", "private, max-age=3600")
+
+	return c.Stream(http.StatusOK, attachment.ContentType, content)
+}
@@ -4,6 +4,7 @@ import (
 	"MicroShopik/internal/domain"
 	domain2 "MicroShopik/internal/services/domain"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
@@ -11,10 +12,11 @@ import (
 
 type CategoryController struct {
 	categoryService domain2.CategoryService
+	slugService     domain2.SlugService
 }
 
-func NewCategoryController(s domain2.CategoryService) *CategoryController {
-	return &CategoryController{categoryService: s}
+func NewCategoryController(s domain2.CategoryService, slugService domain2.SlugService) *CategoryController {
+	return &CategoryController{categoryService: s, slugService: slugService}
 }
 
 // Create @Summary Create a new category
@@ -63,6 +65,36 @@ func (cc *CategoryController) GetById(c echo.Context) error {
 	return c.JSON(http.StatusOK, category)
 }
 
+// GetBySlug @Summary Get a category by slug
+// @Description Get category details by slug. Old slugs answer with a permanent redirect to the current one.
+// @Tags categories
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Success 200 {object} domain.Category
+// @Success 301 {string} string "Moved to the category's current slug"
+// @Failure 404 {object} map[string]string
+// @Router /categories/by-slug/{slug} [get]
+func (cc *CategoryController) GetBySlug(c echo.Context) error {
+	slug := c.Param("slug")
+
+	category, err := cc.categoryService.GetBySlug(slug)
+	if err == nil {
+		return c.JSON(http.StatusOK, category)
+	}
+
+	redirect, redirectErr := cc.slugService.GetRedirect(domain.SlugEntityCategory, slug)
+	if redirectErr != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+
+	category, err = cc.categoryService.GetByID(redirect.EntityID)
+	if err != nil || category.Slug == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "category not found"})
+	}
+
+	return c.Redirect(http.StatusMovedPermanently, "/categories/by-slug/"+url.PathEscape(*category.Slug))
+}
+
 // GetAll @Summary Get all categories
 // @Description Get a list of all categories
 // @Tags categories
@@ -0,0 +1,24 @@
+package controllers
+
+import (
+	"MicroShopik/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+func hasRole(c echo.Context, role string) bool {
+	claims, ok := c.Get("user").(*domain.JWTClaims)
+	if !ok {
+		return false
+	}
+	for _, userRole := range claims.Roles {
+		if userRole == role {
+			return true
+		}
+	}
+	return false
+}
+
+func sessionClient(c echo.Context) domain.SessionClient {
+	return domain.SessionClient{UserAgent: c.Request().UserAgent(), IP: c.RealIP()}
+}
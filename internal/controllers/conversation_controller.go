@@ -5,10 +5,15 @@ import (
 	"MicroShopik/internal/services/application"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
+type markReadRequest struct {
+	MessageID int `json:"message_id"`
+}
+
 type ConversationController struct {
 	conversationAppService *application.ConversationApplicationService
 }
@@ -69,14 +74,15 @@ func (cc *ConversationController) GetByID(c echo.Context) error {
 	}
 
 	userID := c.Get("user_id").(int)
-	conversation, messages, err := cc.conversationAppService.GetConversationWithMessages(id, userID)
+	conversation, page, err := cc.conversationAppService.GetConversationWithMessages(id, userID)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
 
 	response := map[string]interface{}{
 		"conversation": conversation,
-		"messages":     messages,
+		"messages":     page.Messages,
+		"has_older":    page.HasOlder,
 	}
 
 	return c.JSON(http.StatusOK, response)
@@ -104,21 +110,178 @@ func (cc *ConversationController) GetByUserID(c echo.Context) error {
 	return c.JSON(http.StatusOK, conversations)
 }
 
+// MarkRead @Summary Mark a conversation as read
+// @Description Move the current user's read marker to the given message, or to the newest message when none is given.
+// @Description The marker never moves backwards.
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Param id path int true "Conversation ID"
+// @Param request body markReadRequest false "Message ID to mark as read"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /conversations/{id}/read [post]
+func (cc *ConversationController) MarkRead(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid conversation id"})
+	}
+
+	var request markReadRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	userID := c.Get("user_id").(int)
+	messageID, err := cc.conversationAppService.MarkConversationRead(id, userID, request.MessageID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"conversation_id":      id,
+		"last_read_message_id": messageID,
+	})
+}
+
+// GetUnread @Summary Get unread message totals
+// @Description Get the current user's unread message count across all conversations
+// @Tags conversations
+// @Produce json
+// @Success 200 {object} map[string]int
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /me/unread [get]
+func (cc *ConversationController) GetUnread(c echo.Context) error {
+	total, conversations, err := cc.conversationAppService.GetUnreadSummary(c.Get("user_id").(int))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]int{
+		"unread_count":         total,
+		"unread_conversations": conversations,
+	})
+}
+
+// Search @Summary Search my conversations
+// @Description Full-text search over messages in the current user's conversations, best matches first.
+// @Description Each result carries a snippet with matches wrapped in <mark></mark> (the text is not HTML-escaped),
+// @Description its conversation and the message ID to open the history around.
+// @Tags conversations
+// @Produce json
+// @Param q query string true "Search query; supports quoted phrases, or and -exclusions"
+// @Param product_id query int false "Only conversations about this product"
+// @Param order_id query int false "Only conversations about this order"
+// @Param from query string false "Messages sent on or after this date (YYYY-MM-DD or RFC 3339)"
+// @Param to query string false "Messages sent up to this date (YYYY-MM-DD, inclusive, or RFC 3339)"
+// @Param limit query int false "Number of results (default: 20, max: 50)"
+// @Param offset query int false "Offset for pagination (default: 0)"
+// @Success 200 {array} domain.MessageSearchResult
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /me/conversations/search [get]
+func (cc *ConversationController) Search(c echo.Context) error {
+	params := domain.MessageSearchParams{
+		UserID: c.Get("user_id").(int),
+		Query:  c.QueryParam("q"),
+	}
+	params.Limit, _ = strconv.Atoi(c.QueryParam("limit"))
+	params.Offset, _ = strconv.Atoi(c.QueryParam("offset"))
+
+	for name, target := range map[string]**int{"product_id": &params.ProductID, "order_id": &params.OrderID} {
+		value := c.QueryParam(name)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid " + name})
+		}
+		*target = &id
+	}
+
+	var err error
+	if params.From, err = parseSearchDate(c.QueryParam("from"), false); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid from date"})
+	}
+	if params.To, err = parseSearchDate(c.QueryParam("to"), true); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid to date"})
+	}
+
+	results, err := cc.conversationAppService.SearchMessages(params)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, results)
+}
+
+// parseSearchDate accepts a date or an RFC 3339 timestamp. A bare date used as an upper bound
+// covers the whole day.
+func parseSearchDate(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// CreateInquiry @Summary Ask the seller about a product
+// @Description Open the caller's pre-purchase conversation with the product's seller. Repeated calls return the same conversation
+// @Tags conversations
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} domain.Conversation "Existing inquiry"
+// @Success 201 {object} domain.Conversation "New inquiry"
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /products/{id}/inquiry [post]
+func (cc *ConversationController) CreateInquiry(c echo.Context) error {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid product id"})
+	}
+
+	conversation, created, err := cc.conversationAppService.StartProductInquiry(productID, c.Get("user_id").(int))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if created {
+		return c.JSON(http.StatusCreated, conversation)
+	}
+	return c.JSON(http.StatusOK, conversation)
+}
+
 // GetByProductID @Summary Get conversations by product ID
-// @Description Get all conversations related to a specific product
+// @Description Get all conversations related to a specific product, including buyers' inquiries (product seller or admin only)
 // @Tags conversations
 // @Produce json
-// @Param productID path int true "Product ID"
+// @Param id path int true "Product ID"
 // @Success 200 {array} domain.Conversation
 // @Failure 400 {object} map[string]string
-// @Router /products/{productID}/conversations [get]
+// @Security ApiKeyAuth
+// @Router /products/{id}/conversations [get]
 func (cc *ConversationController) GetByProductID(c echo.Context) error {
-	productID, err := strconv.Atoi(c.Param("productID"))
+	productID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid product id"})
 	}
 
-	conversations, err := cc.conversationAppService.GetConversationsByProductID(productID)
+	conversations, err := cc.conversationAppService.GetConversationsByProductID(productID, c.Get("user_id").(int), hasRole(c, "admin"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	domain2 "MicroShopik/internal/services/domain"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type EmailVerificationController struct {
+	verificationService domain2.EmailVerificationService
+}
+
+func NewEmailVerificationController(s domain2.EmailVerificationService) *EmailVerificationController {
+	return &EmailVerificationController{verificationService: s}
+}
+
+type verifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type resendVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+// Verify @Summary Verify email address
+// @Description Confirm the user's email address with the token from the verification email. Each token works once
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body verifyEmailRequest true "Verification token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/verify-email [post]
+func (vc *EmailVerificationController) Verify(c echo.Context) error {
+	var req verifyEmailRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if req.Token == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "token is required"})
+	}
+
+	user, err := vc.verificationService.Verify(req.Token)
+	if err != nil {
+		if errors.Is(err, domain2.ErrEmailAlreadyVerified) {
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":           "email verified",
+		"email_verified_at": user.EmailVerifiedAt,
+	})
+}
+
+// Resend @Summary Resend verification email
+// @Description Send a new verification link to an unverified account. The response is the same whether or not the account exists
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body resendVerificationRequest true "Account email"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/resend-verification [post]
+func (vc *EmailVerificationController) Resend(c echo.Context) error {
+	var req resendVerificationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if req.Email == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "email is required"})
+	}
+
+	if err := vc.verificationService.Resend(req.Email); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "if the account exists and is not verified, a new link has been sent"})
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"MicroShopik/internal/scheduler"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type JobController struct {
+	scheduler *scheduler.Scheduler
+}
+
+func NewJobController(s *scheduler.Scheduler) *JobController {
+	return &JobController{scheduler: s}
+}
+
+// GetJobs @Summary Get background jobs
+// @Description Get the registered background jobs with their schedule, next run and most recent run (admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/jobs [get]
+func (jc *JobController) GetJobs(c echo.Context) error {
+	jobs, err := jc.scheduler.Jobs()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"jobs": jobs})
+}
+
+// GetRuns @Summary Get job runs
+// @Description Get the run history of a background job with durations and errors, newest first (admin only)
+// @Tags admin
+// @Produce json
+// @Param name path string true "Job name"
+// @Param limit query int false "Number of items per page (default: 20)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/jobs/{name}/runs [get]
+func (jc *JobController) GetRuns(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 {
+		limit = 20
+	}
+
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	runs, total, err := jc.scheduler.Runs(c.Param("name"), limit, offset)
+	if err != nil {
+		if errors.Is(err, scheduler.ErrJobNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"runs":  runs,
+		"total": total,
+	})
+}
+
+// Trigger @Summary Run a job now
+// @Description Start a background job outside its schedule. The run continues in the background; follow it in the run history (admin only)
+// @Tags admin
+// @Produce json
+// @Param name path string true "Job name"
+// @Success 202 {object} domain.JobRun
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/jobs/{name}/run [post]
+func (jc *JobController) Trigger(c echo.Context) error {
+	run, err := jc.scheduler.Trigger(c.Param("name"))
+	if err != nil {
+		switch {
+		case errors.Is(err, scheduler.ErrJobNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		case errors.Is(err, scheduler.ErrJobRunning):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+
+	return c.JSON(http.StatusAccepted, run)
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"MicroShopik/internal/domain"
 	domain2 "MicroShopik/internal/services/domain"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -22,18 +23,31 @@ func NewMessageController(s domain2.MessageService) *MessageController {
 // @Tags messages
 // @Accept json
 // @Produce json
-// @Param message body domain.Message true "Message object"
+// @Param conversationID path int true "Conversation ID"
+// @Param message body domain.Message true "Message object; attachment_ids links files uploaded to the conversation"
 // @Success 201 {object} domain.Message
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Security ApiKeyAuth
-// @Router /messages [post]
+// @Router /conversations/{conversationID}/messages [post]
 func (mc *MessageController) Create(c echo.Context) error {
+	conversationID, err := strconv.Atoi(c.Param("conversationID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid conversation id"})
+	}
+
 	var message domain.Message
 	if err := c.Bind(&message); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
+	// The sender and conversation come from the token and the route, never from the body.
+	userID := c.Get("user_id").(int)
+	message.ID = 0
+	message.ConversationID = conversationID
+	message.SenderID = &userID
+	message.IsSystem = false
+
 	if err := mc.messageService.Create(&message); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
@@ -65,14 +79,18 @@ func (mc *MessageController) GetByID(c echo.Context) error {
 }
 
 // GetByConversationID @Summary Get messages by conversation ID
-// @Description Get messages for a specific conversation with pagination
+// @Description Get a page of a conversation's history, oldest message first. Without a cursor the newest messages are returned;
+// @Description before, after and around take a message ID and are mutually exclusive.
 // @Tags messages
 // @Produce json
 // @Param conversationID path int true "Conversation ID"
-// @Param limit query int false "Limit number of messages (default: 50)"
-// @Param offset query int false "Offset for pagination (default: 0)"
-// @Success 200 {array} domain.Message
+// @Param limit query int false "Page size (default: 50, max: 100)"
+// @Param before query int false "Return messages older than this message ID"
+// @Param after query int false "Return messages newer than this message ID"
+// @Param around query int false "Return messages centred on this message ID, including it"
+// @Success 200 {object} domain.MessagePage
 // @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
 // @Router /conversations/{conversationID}/messages [get]
 func (mc *MessageController) GetByConversationID(c echo.Context) error {
 	conversationID, err := strconv.Atoi(c.Param("conversationID"))
@@ -81,21 +99,24 @@ func (mc *MessageController) GetByConversationID(c echo.Context) error {
 	}
 
 	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit <= 0 {
-		limit = 50
-	}
 
-	offset, _ := strconv.Atoi(c.QueryParam("offset"))
-	if offset < 0 {
-		offset = 0
+	var cursor domain.MessageCursor
+	for name, target := range map[string]*int{"before": &cursor.Before, "after": &cursor.After, "around": &cursor.Around} {
+		value := c.QueryParam(name)
+		if value == "" {
+			continue
+		}
+		if *target, err = strconv.Atoi(value); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid " + name + " cursor"})
+		}
 	}
 
-	messages, err := mc.messageService.GetByConversationID(conversationID)
+	page, err := mc.messageService.GetPage(conversationID, c.Get("user_id").(int), cursor, limit)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	return c.JSON(http.StatusOK, messages)
+	return c.JSON(http.StatusOK, page)
 }
 
 // GetByOrderID @Summary Get messages by order ID
@@ -120,60 +141,110 @@ func (mc *MessageController) GetByOrderID(c echo.Context) error {
 	return c.JSON(http.StatusOK, messages)
 }
 
-// Update @Summary Update a message
-// @Description Update a message (only by the sender)
+type editMessageRequest struct {
+	Content string `json:"content"`
+}
+
+// Update @Summary Edit a message
+// @Description Edit the text of your own message within the edit window. The previous text is kept in the message history.
 // @Tags messages
 // @Accept json
 // @Produce json
+// @Param conversationID path int true "Conversation ID"
 // @Param id path int true "Message ID"
-// @Param message body domain.Message true "Message object"
-// @Success 200 {object} map[string]string
+// @Param message body editMessageRequest true "New message text"
+// @Success 200 {object} domain.Message
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Security ApiKeyAuth
-// @Router /messages/{id} [put]
+// @Router /conversations/{conversationID}/messages/{id} [put]
 func (mc *MessageController) Update(c echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := mc.messageInConversation(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid message id"})
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
 
-	var message domain.Message
-	if err := c.Bind(&message); err != nil {
+	var request editMessageRequest
+	if err := c.Bind(&request); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	message.ID = id
-	if err := mc.messageService.Update(&message); err != nil {
+	message, err := mc.messageService.Edit(id, c.Get("user_id").(int), request.Content)
+	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{"message": "message updated successfully"})
+	return c.JSON(http.StatusOK, message)
 }
 
 // Delete @Summary Delete a message
-// @Description Delete a message (only by the sender)
+// @Description Delete your own message within the edit window. Other participants see a "message deleted" placeholder.
 // @Tags messages
 // @Produce json
+// @Param conversationID path int true "Conversation ID"
 // @Param id path int true "Message ID"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} domain.Message
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Security ApiKeyAuth
-// @Router /messages/{id} [delete]
+// @Router /conversations/{conversationID}/messages/{id} [delete]
 func (mc *MessageController) Delete(c echo.Context) error {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, err := mc.messageInConversation(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid message id"})
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
 
-	if err := mc.messageService.Delete(id); err != nil {
+	message, err := mc.messageService.Delete(id, c.Get("user_id").(int))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, message)
+}
+
+// GetRevisions @Summary Get message edit history
+// @Description Get earlier versions of a message, newest first (participants only)
+// @Tags messages
+// @Produce json
+// @Param conversationID path int true "Conversation ID"
+// @Param id path int true "Message ID"
+// @Success 200 {array} domain.MessageRevision
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /conversations/{conversationID}/messages/{id}/revisions [get]
+func (mc *MessageController) GetRevisions(c echo.Context) error {
+	id, err := mc.messageInConversation(c)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+
+	revisions, err := mc.messageService.GetRevisions(id, c.Get("user_id").(int), hasRole(c, "admin"))
+	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{"message": "message deleted successfully"})
+	return c.JSON(http.StatusOK, revisions)
+}
+
+// messageInConversation parses the message ID from the route and checks it belongs to the conversation in the path.
+func (mc *MessageController) messageInConversation(c echo.Context) (int, error) {
+	conversationID, err := strconv.Atoi(c.Param("conversationID"))
+	if err != nil {
+		return 0, errors.New("invalid conversation id")
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, errors.New("invalid message id")
+	}
+
+	message, err := mc.messageService.GetByID(id)
+	if err != nil || message.ConversationID != conversationID {
+		return 0, errors.New("message not found")
+	}
+	return id, nil
 }
 
 // GetSystemMessages @Summary Get system messages
@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"MicroShopik/internal/domain"
+	domain2 "MicroShopik/internal/services/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type MessageModerationController struct {
+	moderationService domain2.MessageModerationService
+}
+
+func NewMessageModerationController(s domain2.MessageModerationService) *MessageModerationController {
+	return &MessageModerationController{moderationService: s}
+}
+
+type reportMessageRequest struct {
+	Reason  string `json:"reason"`
+	Comment string `json:"comment"`
+}
+
+type messageModerationRequest struct {
+	Comment string `json:"comment"`
+	// Days applies to suspensions only; 0 suspends without an end date.
+	Days int `json:"days"`
+}
+
+// Report @Summary Report a message
+// @Description Report another participant's message to the moderators
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param conversationID path int true "Conversation ID"
+// @Param id path int true "Message ID"
+// @Param request body reportMessageRequest true "Reason code and optional comment"
+// @Success 201 {object} domain.MessageReport
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /conversations/{conversationID}/messages/{id}/report [post]
+func (mc *MessageModerationController) Report(c echo.Context) error {
+	conversationID, err := strconv.Atoi(c.Param("conversationID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid conversation id"})
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid message id"})
+	}
+
+	var request reportMessageRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	report, err := mc.moderationService.Report(conversationID, id, c.Get("user_id").(int), request.Reason, request.Comment)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, report)
+}
+
+// GetReportReasons @Summary Get message report reasons
+// @Description Get the reason codes available when reporting a message
+// @Tags moderation
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /moderation/message-report-reasons [get]
+func (mc *MessageModerationController) GetReportReasons(c echo.Context) error {
+	return c.JSON(http.StatusOK, domain.MessageReportReasons)
+}
+
+// GetQueue @Summary Get message moderation queue
+// @Description Get open message reports, oldest first, including reports raised by the content filter (admin only)
+// @Tags moderation
+// @Produce json
+// @Param limit query int false "Number of items per page (default: 20)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/moderation/messages [get]
+func (mc *MessageModerationController) GetQueue(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 {
+		limit = 20
+	}
+
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	reports, total, err := mc.moderationService.GetQueue(limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"reports": reports,
+		"total":   total,
+	})
+}
+
+// Dismiss @Summary Dismiss message reports
+// @Description Close all open reports of a message without action (admin only)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path int true "Message ID"
+// @Param request body messageModerationRequest false "Optional moderator comment"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/moderation/messages/{id}/dismiss [post]
+func (mc *MessageModerationController) Dismiss(c echo.Context) error {
+	return mc.decide(c, "reports dismissed", func(id, moderatorID int, request messageModerationRequest) error {
+		return mc.moderationService.Dismiss(id, moderatorID, request.Comment)
+	})
+}
+
+// Delete @Summary Delete a reported message
+// @Description Replace a reported message with a "message deleted" placeholder and close its reports (admin only)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path int true "Message ID"
+// @Param request body messageModerationRequest false "Optional moderator comment"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/moderation/messages/{id}/delete [post]
+func (mc *MessageModerationController) Delete(c echo.Context) error {
+	return mc.decide(c, "message deleted", func(id, moderatorID int, request messageModerationRequest) error {
+		return mc.moderationService.DeleteMessage(id, moderatorID, request.Comment)
+	})
+}
+
+// Warn @Summary Warn the sender of a reported message
+// @Description Record a warning for the sender and close the message's reports (admin only)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path int true "Message ID"
+// @Param request body messageModerationRequest false "Warning text shown to the sender"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/moderation/messages/{id}/warn [post]
+func (mc *MessageModerationController) Warn(c echo.Context) error {
+	return mc.decide(c, "sender warned", func(id, moderatorID int, request messageModerationRequest) error {
+		return mc.moderationService.WarnSender(id, moderatorID, request.Comment)
+	})
+}
+
+// Suspend @Summary Suspend the sender of a reported message
+// @Description Stop the sender from sending messages for a number of days (0 for no end date) and close the message's reports (admin only)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path int true "Message ID"
+// @Param request body messageModerationRequest true "Suspension length in days and reason"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/moderation/messages/{id}/suspend [post]
+func (mc *MessageModerationController) Suspend(c echo.Context) error {
+	return mc.decide(c, "sender suspended", func(id, moderatorID int, request messageModerationRequest) error {
+		return mc.moderationService.SuspendSender(id, moderatorID, request.Days, request.Comment)
+	})
+}
+
+// GetMySanctions @Summary Get my warnings and suspensions
+// @Description Get the warnings and messaging suspensions issued to the current user, newest first
+// @Tags moderation
+// @Produce json
+// @Success 200 {array} domain.UserSanction
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /me/sanctions [get]
+func (mc *MessageModerationController) GetMySanctions(c echo.Context) error {
+	sanctions, err := mc.moderationService.GetSanctions(c.Get("user_id").(int))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, sanctions)
+}
+
+func (mc *MessageModerationController) decide(c echo.Context, done string, action func(id, moderatorID int, request messageModerationRequest) error) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid message id"})
+	}
+
+	var request messageModerationRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if err := action(id, c.Get("user_id").(int), request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": done})
+}
@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	domain2 "MicroShopik/internal/services/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// notificationPreferencesRequest leaves every omitted field at its current value.
+type notificationPreferencesRequest struct {
+	Channels        map[string]map[string]bool `json:"channels"`
+	Timezone        *string                    `json:"timezone"`
+	QuietHoursStart *string                    `json:"quiet_hours_start"`
+	QuietHoursEnd   *string                    `json:"quiet_hours_end"`
+	DigestFrequency *string                    `json:"digest_frequency"`
+}
+
+type NotificationController struct {
+	notificationService domain2.NotificationService
+	preferenceService   domain2.NotificationPreferenceService
+}
+
+func NewNotificationController(s domain2.NotificationService, p domain2.NotificationPreferenceService) *NotificationController {
+	return &NotificationController{notificationService: s, preferenceService: p}
+}
+
+// GetNotifications @Summary Get my notifications
+// @Description Get the current user's notifications, newest first, with the number of unread ones
+// @Tags notifications
+// @Produce json
+// @Param limit query int false "Number of items per page (default: 20)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /me/notifications [get]
+func (nc *NotificationController) GetNotifications(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	notifications, total, err := nc.notificationService.GetNotifications(userID, limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	unread, err := nc.notificationService.CountUnread(userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"notifications": notifications,
+		"total":         total,
+		"unread":        unread,
+	})
+}
+
+// MarkRead @Summary Mark a notification as read
+// @Description Mark one of the current user's notifications as read
+// @Tags notifications
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /me/notifications/{id}/read [post]
+func (nc *NotificationController) MarkRead(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid notification id"})
+	}
+
+	if err := nc.notificationService.MarkRead(id, c.Get("user_id").(int)); err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "notification marked as read"})
+}
+
+// MarkAllRead @Summary Mark all notifications as read
+// @Description Mark every unread notification of the current user as read
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /me/notifications/read-all [post]
+func (nc *NotificationController) MarkAllRead(c echo.Context) error {
+	if err := nc.notificationService.MarkAllRead(c.Get("user_id").(int)); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "all notifications marked as read"})
+}
+
+// GetPreferences @Summary Get my notification preferences
+// @Description Get the per-event and per-channel switches, quiet hours and digest frequency of the current user
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} domain.NotificationPreference
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /me/notification-preferences [get]
+func (nc *NotificationController) GetPreferences(c echo.Context) error {
+	preference, err := nc.preferenceService.Get(c.Get("user_id").(int))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, preference)
+}
+
+// UpdatePreferences @Summary Update my notification preferences
+// @Description Change the notification settings of the current user. Omitted fields keep their current values and channels may list only the switches that change. Empty quiet hours turn them off.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param preferences body notificationPreferencesRequest true "Notification preferences"
+// @Success 200 {object} domain.NotificationPreference
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /me/notification-preferences [put]
+func (nc *NotificationController) UpdatePreferences(c echo.Context) error {
+	var request notificationPreferencesRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	preference, err := nc.preferenceService.Update(c.Get("user_id").(int), domain2.NotificationPreferenceUpdate{
+		Channels:        request.Channels,
+		Timezone:        request.Timezone,
+		QuietHoursStart: request.QuietHoursStart,
+		QuietHoursEnd:   request.QuietHoursEnd,
+		DigestFrequency: request.DigestFrequency,
+	})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, preference)
+}
@@ -3,6 +3,8 @@ package controllers
 import (
 	"MicroShopik/internal/domain"
 	"MicroShopik/internal/services/application"
+	domain2 "MicroShopik/internal/services/domain"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -50,6 +52,9 @@ func (oc *OrderController) Create(c echo.Context) error {
 	order.CustomerID = &userID
 
 	if err := oc.orderAppService.CreateOrder(&order); err != nil {
+		if errors.Is(err, domain2.ErrEmailNotVerified) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
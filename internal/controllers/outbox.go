@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"MicroShopik/internal/domain"
+	domain2 "MicroShopik/internal/services/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type OutboxController struct {
+	outboxService domain2.OutboxService
+}
+
+func NewOutboxController(s domain2.OutboxService) *OutboxController {
+	return &OutboxController{outboxService: s}
+}
+
+// GetEvents @Summary Get outbox events
+// @Description Get stored domain events with their delivery status, newest first, and the number of events per status (admin only)
+// @Tags admin
+// @Produce json
+// @Param status query string false "Filter by status: pending, delivered or dead"
+// @Param limit query int false "Number of items per page (default: 20)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/outbox [get]
+func (oc *OutboxController) GetEvents(c echo.Context) error {
+	status := c.QueryParam("status")
+	switch status {
+	case "", domain.OutboxStatusPending, domain.OutboxStatusDelivered, domain.OutboxStatusDead:
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid status"})
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 {
+		limit = 20
+	}
+
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	outboxEvents, total, err := oc.outboxService.GetEvents(status, limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	counts, err := oc.outboxService.CountByStatus()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"events": outboxEvents,
+		"total":  total,
+		"counts": counts,
+	})
+}
+
+// Retry @Summary Retry a dead outbox event
+// @Description Put a dead event back in the delivery queue with a fresh attempt budget (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Outbox event ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/outbox/{id}/retry [post]
+func (oc *OutboxController) Retry(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid event id"})
+	}
+
+	if err := oc.outboxService.Retry(id); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "event queued for delivery"})
+}
@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	domain2 "MicroShopik/internal/services/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PresenceController struct {
+	presenceService domain2.PresenceService
+}
+
+func NewPresenceController(s domain2.PresenceService) *PresenceController {
+	return &PresenceController{presenceService: s}
+}
+
+type presenceSettingsRequest struct {
+	HideOnlineStatus bool `json:"hide_online_status"`
+}
+
+// Get @Summary Get user presence
+// @Description Whether the user is connected right now and when they were last seen.
+// @Description Users who hide their online status always appear offline to others.
+// @Tags users
+// @Produce json
+// @Param userID path int true "User ID"
+// @Success 200 {object} domain.Presence
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /users/{userID}/presence [get]
+func (pc *PresenceController) Get(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("userID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid user id"})
+	}
+
+	presence, err := pc.presenceService.Get(userID, c.Get("user_id").(int))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, presence)
+}
+
+// UpdateSettings @Summary Update presence settings
+// @Description Hide or show your online status to other users
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param settings body presenceSettingsRequest true "Presence settings"
+// @Success 200 {object} map[string]bool
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /me/presence [put]
+func (pc *PresenceController) UpdateSettings(c echo.Context) error {
+	var request presenceSettingsRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if err := pc.presenceService.SetHidden(c.Get("user_id").(int), request.HideOnlineStatus); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]bool{"hide_online_status": request.HideOnlineStatus})
+}
@@ -3,7 +3,10 @@ package controllers
 import (
 	"MicroShopik/internal/domain"
 	"MicroShopik/internal/services/application"
+	domain2 "MicroShopik/internal/services/domain"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
@@ -38,6 +41,9 @@ func (pc *ProductController) Create(c echo.Context) error {
 	userID := c.Get("user_id").(int)
 
 	if err := pc.productAppService.CreateProductWithValidation(&product, userID); err != nil {
+		if errors.Is(err, domain2.ErrEmailNotVerified) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
@@ -67,6 +73,27 @@ func (pc *ProductController) GetById(c echo.Context) error {
 	return c.JSON(http.StatusOK, product)
 }
 
+// GetBySlug @Summary Get a product by slug
+// @Description Get product details by slug. Old slugs answer with a permanent redirect to the current one.
+// @Tags products
+// @Produce json
+// @Param slug path string true "Product slug"
+// @Success 200 {object} domain.Product
+// @Success 301 {string} string "Moved to the product's current slug"
+// @Failure 404 {object} map[string]string
+// @Router /products/by-slug/{slug} [get]
+func (pc *ProductController) GetBySlug(c echo.Context) error {
+	product, currentSlug, err := pc.productAppService.GetProductBySlug(c.Param("slug"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	if currentSlug != "" {
+		return c.Redirect(http.StatusMovedPermanently, "/products/by-slug/"+url.PathEscape(currentSlug))
+	}
+
+	return c.JSON(http.StatusOK, product)
+}
+
 // Update UpdateProduct godoc
 // @Summary Update a product
 // @Description Update a product (only by the seller)
@@ -127,6 +154,89 @@ func (pc *ProductController) Delete(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"message": "product deleted successfully"})
 }
 
+// GetRelated @Summary Get related products
+// @Description Get products that buyers of this product also bought, falling back to popular products in the same category
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param limit query int false "Number of products to return (default: 8, max: 50)"
+// @Success 200 {array} domain.Product
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /products/{id}/related [get]
+func (pc *ProductController) GetRelated(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid product id"})
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 {
+		limit = 8
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	products, err := pc.productAppService.GetRelatedProducts(id, limit)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, products)
+}
+
+// GetRevisions @Summary Get product revisions
+// @Description Get the change history of a product, newest first (seller or admin)
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} domain.ProductRevision
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /products/{id}/revisions [get]
+func (pc *ProductController) GetRevisions(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid product id"})
+	}
+
+	revisions, err := pc.productAppService.GetProductRevisions(id, c.Get("user_id").(int), hasRole(c, "admin"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, revisions)
+}
+
+// Rollback @Summary Roll back a product to a revision
+// @Description Restore the product to the state it had after the chosen revision (only by the seller)
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param revisionID path int true "Revision ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /products/{id}/revisions/{revisionID}/rollback [post]
+func (pc *ProductController) Rollback(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid product id"})
+	}
+
+	revisionID, err := strconv.Atoi(c.Param("revisionID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid revision id"})
+	}
+
+	if err := pc.productAppService.RollbackProductToRevision(id, revisionID, c.Get("user_id").(int)); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "product rolled back successfully"})
+}
+
 // IsAvailable checks if a product is available
 // @Summary Check product availability
 // @Description Returns whether the product with the given ID is available for purchase
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"MicroShopik/internal/services/application"
+	domain2 "MicroShopik/internal/services/domain"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const maxProductImportSize = 10 << 20
+
+type ProductImportController struct {
+	importAppService *application.ProductImportApplicationService
+}
+
+func NewProductImportController(s *application.ProductImportApplicationService) *ProductImportController {
+	return &ProductImportController{importAppService: s}
+}
+
+// Import @Summary Import products
+// @Description Create or update the seller's products from a CSV or JSON file, matching existing products by SKU.
+// @Description Large imports run in the background and return a job to poll.
+// @Tags seller
+// @Accept json,text/csv,multipart/form-data
+// @Produce json
+// @Param format query string false "Import format: csv or json (detected from the upload when omitted)"
+// @Param dry_run query bool false "Validate rows without saving anything"
+// @Param file formData file false "Import file"
+// @Success 200 {object} application.ProductImportResult
+// @Success 202 {object} domain.ProductImportJob
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /seller/products/import [post]
+func (pc *ProductImportController) Import(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+	dryRun, _ := strconv.ParseBool(c.QueryParam("dry_run"))
+	format := strings.ToLower(c.QueryParam("format"))
+
+	var reader io.Reader
+	if file, err := c.FormFile("file"); err == nil {
+		src, err := file.Open()
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to open uploaded file"})
+		}
+		defer src.Close()
+
+		reader = src
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file.Filename)), ".")
+		}
+	} else {
+		reader = c.Request().Body
+		if format == "" {
+			format = importFormatFromContentType(c.Request().Header.Get(echo.HeaderContentType))
+		}
+	}
+
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(reader, maxProductImportSize+1))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to read import file"})
+	}
+	if n > maxProductImportSize {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "import file is too large"})
+	}
+
+	result, job, err := pc.importAppService.ImportProducts(userID, format, buf.Bytes(), dryRun)
+	if err != nil {
+		if errors.Is(err, domain2.ErrEmailNotVerified) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if job != nil {
+		return c.JSON(http.StatusAccepted, job)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetImportJob @Summary Get product import progress
+// @Description Get the status and progress of a background product import
+// @Tags seller
+// @Produce json
+// @Param jobID path int true "Import job ID"
+// @Success 200 {object} domain.ProductImportJob
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /seller/products/import/{jobID} [get]
+func (pc *ProductImportController) GetImportJob(c echo.Context) error {
+	jobID, err := strconv.Atoi(c.Param("jobID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid job id"})
+	}
+
+	job, err := pc.importAppService.GetImportJob(jobID, c.Get("user_id").(int))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, job)
+}
+
+// Export @Summary Export products
+// @Description Download all of the seller's products as CSV or JSON, in a shape the import accepts
+// @Tags seller
+// @Produce json,text/csv
+// @Param format query string false "Export format: csv (default) or json"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /seller/products/export [get]
+func (pc *ProductImportController) Export(c echo.Context) error {
+	format := strings.ToLower(c.QueryParam("format"))
+	if format == "" {
+		format = application.ImportFormatCSV
+	}
+
+	var buf bytes.Buffer
+	if err := pc.importAppService.ExportProducts(c.Get("user_id").(int), format, &buf); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == application.ImportFormatJSON {
+		contentType = echo.MIMEApplicationJSONCharsetUTF8
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="products.`+format+`"`)
+	return c.Blob(http.StatusOK, contentType, buf.Bytes())
+}
+
+func importFormatFromContentType(contentType string) string {
+	switch {
+	case strings.HasPrefix(contentType, "text/csv"):
+		return application.ImportFormatCSV
+	case strings.HasPrefix(contentType, echo.MIMEApplicationJSON):
+		return application.ImportFormatJSON
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"MicroShopik/internal/domain"
+	"MicroShopik/internal/services/application"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ProductModerationController struct {
+	productAppService *application.ProductApplicationService
+}
+
+func NewProductModerationController(s *application.ProductApplicationService) *ProductModerationController {
+	return &ProductModerationController{productAppService: s}
+}
+
+type moderationDecisionRequest struct {
+	ReasonCode string `json:"reason_code"`
+	Comment    string `json:"comment"`
+}
+
+// GetQueue @Summary Get product moderation queue
+// @Description Get products waiting for review, oldest first (admin only)
+// @Tags moderation
+// @Produce json
+// @Param limit query int false "Number of items per page (default: 20)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/moderation/products [get]
+func (mc *ProductModerationController) GetQueue(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 {
+		limit = 20
+	}
+
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	products, total, err := mc.productAppService.GetModerationQueue(limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"products": products,
+		"total":    total,
+	})
+}
+
+// GetReasonCodes @Summary Get rejection reason codes
+// @Description Get the reason codes available when rejecting a product
+// @Tags moderation
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/moderation/products/reasons [get]
+func (mc *ProductModerationController) GetReasonCodes(c echo.Context) error {
+	return c.JSON(http.StatusOK, domain.ProductRejectionReasons)
+}
+
+// Approve @Summary Approve a product
+// @Description Approve a product pending review and publish it (admin only)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param request body moderationDecisionRequest false "Optional moderator comment"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/moderation/products/{id}/approve [post]
+func (mc *ProductModerationController) Approve(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid product id"})
+	}
+
+	var request moderationDecisionRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	userID := c.Get("user_id").(int)
+	if err := mc.productAppService.ApproveProduct(id, userID, request.Comment); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "product approved successfully"})
+}
+
+// Reject @Summary Reject a product
+// @Description Reject a product with a reason code (admin only)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param request body moderationDecisionRequest true "Reason code and optional comment"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/moderation/products/{id}/reject [post]
+func (mc *ProductModerationController) Reject(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid product id"})
+	}
+
+	var request moderationDecisionRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	userID := c.Get("user_id").(int)
+	if err := mc.productAppService.RejectProduct(id, userID, request.ReasonCode, request.Comment); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "product rejected successfully"})
+}
+
+// Resubmit @Summary Resubmit a rejected product
+// @Description Send a rejected product back to the moderation queue (only by the seller)
+// @Tags moderation
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /products/{id}/resubmit [post]
+func (mc *ProductModerationController) Resubmit(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid product id"})
+	}
+
+	userID := c.Get("user_id").(int)
+	if err := mc.productAppService.ResubmitProductForReview(id, userID); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "product resubmitted for review"})
+}
+
+// GetHistory @Summary Get product moderation history
+// @Description Get every moderation decision for a product (seller or admin)
+// @Tags moderation
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} domain.ProductModerationLog
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /products/{id}/moderation [get]
+func (mc *ProductModerationController) GetHistory(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid product id"})
+	}
+
+	userID := c.Get("user_id").(int)
+	history, err := mc.productAppService.GetModerationHistory(id, userID, hasRole(c, "admin"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
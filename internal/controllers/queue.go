@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"MicroShopik/internal/domain"
+	"MicroShopik/internal/jobqueue"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type QueueController struct {
+	queue *jobqueue.Queue
+}
+
+func NewQueueController(q *jobqueue.Queue) *QueueController {
+	return &QueueController{queue: q}
+}
+
+// GetJobs @Summary Get queued jobs
+// @Description Get background jobs from the queue, newest first, with the number of jobs per status and the known job types (admin only)
+// @Tags admin
+// @Produce json
+// @Param status query string false "Filter by status: queued, running, succeeded, dead or cancelled"
+// @Param type query string false "Filter by job type"
+// @Param limit query int false "Number of items per page (default: 20)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/queue/jobs [get]
+func (qc *QueueController) GetJobs(c echo.Context) error {
+	status := c.QueryParam("status")
+	switch status {
+	case "", domain.QueuedJobStatusQueued, domain.QueuedJobStatusRunning, domain.QueuedJobStatusSucceeded,
+		domain.QueuedJobStatusDead, domain.QueuedJobStatusCancelled:
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid status"})
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 {
+		limit = 20
+	}
+
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	jobs, total, err := qc.queue.GetJobs(status, c.QueryParam("type"), limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	counts, err := qc.queue.CountByStatus()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"jobs":   jobs,
+		"total":  total,
+		"counts": counts,
+		"types":  qc.queue.Types(),
+	})
+}
+
+// GetJob @Summary Get a queued job
+// @Description Get a background job with its payload, attempts and last error (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 200 {object} domain.QueuedJob
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/queue/jobs/{id} [get]
+func (qc *QueueController) GetJob(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid job id"})
+	}
+
+	job, err := qc.queue.GetJob(id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, job)
+}
+
+// Retry @Summary Retry a queued job
+// @Description Queue a dead or cancelled job again with a fresh attempt budget (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/queue/jobs/{id}/retry [post]
+func (qc *QueueController) Retry(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid job id"})
+	}
+
+	if err := qc.queue.Retry(id); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "job queued"})
+}
+
+// Cancel @Summary Cancel a queued job
+// @Description Cancel a job that has not started yet (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /admin/queue/jobs/{id}/cancel [post]
+func (qc *QueueController) Cancel(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid job id"})
+	}
+
+	if err := qc.queue.Cancel(id); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "job cancelled"})
+}
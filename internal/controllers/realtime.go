@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"MicroShopik/internal/domain"
+	"MicroShopik/internal/realtime"
+	"log"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RealtimeController struct {
+	hub *realtime.Hub
+}
+
+func NewRealtimeController(hub *realtime.Hub) *RealtimeController {
+	return &RealtimeController{hub: hub}
+}
+
+// Connect @Summary Open a real-time connection
+// @Description Upgrade to a WebSocket that pushes new messages and participant changes for all of the user's conversations.
+// @Description Browsers may pass the JWT as the token query parameter. Pass since to replay messages missed while disconnected.
+// @Description The connection is closed with code 1008 when its session is revoked.
+// @Tags conversations
+// @Param token query string false "JWT, when the Authorization header can't be set"
+// @Param since query int false "Replay messages with an ID greater than this one"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 401 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /ws [get]
+func (rc *RealtimeController) Connect(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+	since, _ := strconv.Atoi(c.QueryParam("since"))
+
+	sessionID := ""
+	if claims, ok := c.Get("user").(*domain.JWTClaims); ok {
+		sessionID = claims.SessionID
+	}
+
+	// The upgrader has already answered the request when it fails.
+	if err := rc.hub.ServeWS(c.Response(), c.Request(), userID, sessionID, since); err != nil {
+		log.Printf("Realtime: connection for user %d failed: %v", userID, err)
+	}
+	return nil
+}
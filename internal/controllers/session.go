@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"MicroShopik/internal/domain"
+	domain2 "MicroShopik/internal/services/domain"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SessionController struct {
+	sessionService domain2.SessionService
+}
+
+func NewSessionController(s domain2.SessionService) *SessionController {
+	return &SessionController{sessionService: s}
+}
+
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Refresh @Summary Refresh access token
+// @Description Trade a refresh token for a new access token and refresh token. Each refresh token works once; using one again revokes its session
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body refreshTokenRequest true "Refresh token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /auth/refresh [post]
+func (sc *SessionController) Refresh(c echo.Context) error {
+	var req refreshTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if req.RefreshToken == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "refresh_token is required"})
+	}
+
+	tokens, user, err := sc.sessionService.Refresh(req.RefreshToken, sessionClient(c))
+	if err != nil {
+		if errors.Is(err, domain2.ErrInvalidRefreshToken) {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"user":          user,
+	})
+}
+
+// Logout @Summary Log out
+// @Description End the session of a refresh token. Access tokens issued for it are rejected within 30 seconds on every server, right away on this one, and its WebSocket connections are closed
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body refreshTokenRequest true "Refresh token"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/logout [post]
+func (sc *SessionController) Logout(c echo.Context) error {
+	var req refreshTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if req.RefreshToken == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "refresh_token is required"})
+	}
+
+	if err := sc.sessionService.Logout(req.RefreshToken); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "logged out"})
+}
+
+// GetSessions @Summary Get my sessions
+// @Description Get the current user's active sessions, marking the one of this request
+// @Tags users
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /me/sessions [get]
+func (sc *SessionController) GetSessions(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+
+	currentID := ""
+	if claims, ok := c.Get("user").(*domain.JWTClaims); ok {
+		currentID = claims.SessionID
+	}
+
+	sessions, err := sc.sessionService.GetActive(userID, currentID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"sessions": sessions})
+}
+
+// Revoke @Summary Revoke a session
+// @Description End one of the current user's sessions; its refresh token stops working right away. Its access tokens are rejected within 30 seconds on every server, right away on this one, and its WebSocket connections are closed
+// @Tags users
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /me/sessions/{id} [delete]
+func (sc *SessionController) Revoke(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+
+	if err := sc.sessionService.Revoke(userID, c.Param("id")); err != nil {
+		if errors.Is(err, domain2.ErrSessionNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "session revoked"})
+}
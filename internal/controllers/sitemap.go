@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"MicroShopik/internal/services/application"
+	"encoding/xml"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+var sitemapPageName = regexp.MustCompile(`^([a-z]+)-(\d+)\.xml$`)
+
+type SitemapController struct {
+	sitemapAppService *application.SitemapApplicationService
+}
+
+func NewSitemapController(s *application.SitemapApplicationService) *SitemapController {
+	return &SitemapController{sitemapAppService: s}
+}
+
+// GetRoot @Summary Get the sitemap
+// @Description Sitemap of active products and categories. Large catalogs get a sitemap index instead.
+// @Tags sitemap
+// @Produce xml
+// @Success 200 {string} string "Sitemap or sitemap index"
+// @Failure 500 {string} string
+// @Router /sitemap.xml [get]
+func (sc *SitemapController) GetRoot(c echo.Context) error {
+	doc, err := sc.sitemapAppService.Root()
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	return writeSitemap(c, doc)
+}
+
+// GetPage @Summary Get a sitemap page
+// @Description One page of the sitemap, as listed in the sitemap index (e.g. products-2.xml)
+// @Tags sitemap
+// @Produce xml
+// @Param name path string true "Sitemap page file name"
+// @Success 200 {string} string "Sitemap"
+// @Failure 404 {string} string
+// @Router /sitemaps/{name} [get]
+func (sc *SitemapController) GetPage(c echo.Context) error {
+	match := sitemapPageName.FindStringSubmatch(c.Param("name"))
+	if match == nil {
+		return c.String(http.StatusNotFound, "sitemap page not found")
+	}
+
+	page, _ := strconv.Atoi(match[2])
+	doc, err := sc.sitemapAppService.Page(match[1], page)
+	if err != nil {
+		return c.String(http.StatusNotFound, err.Error())
+	}
+	return writeSitemap(c, doc)
+}
+
+func writeSitemap(c echo.Context, doc interface{}) error {
+	body, err := xml.Marshal(doc)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, append([]byte(xml.Header), body...))
+}
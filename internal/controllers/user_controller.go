@@ -3,6 +3,7 @@ package controllers
 import (
 	"MicroShopik/internal/domain"
 	"MicroShopik/internal/dto"
+	"MicroShopik/internal/mailer"
 	"MicroShopik/internal/services/application"
 	"net/http"
 	"strconv"
@@ -28,6 +29,7 @@ func (a *UserController) Register(c echo.Context) error {
 		Username: req.Username,
 		Email:    req.Email,
 		Password: req.Password,
+		Locale:   mailer.NormalizeLocale(req.Locale),
 	}
 	if err := a.userAppService.RegisterUser(user); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
@@ -47,16 +49,45 @@ func (a *UserController) Login(c echo.Context) error {
 	if err := c.Bind(&auth); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
-	token, user, err := a.userAppService.LoginUser(auth.Email, auth.Password)
+	tokens, user, err := a.userAppService.LoginUser(auth.Email, auth.Password, sessionClient(c))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 	return c.JSON(http.StatusCreated, map[string]interface{}{
-		"token": token,
-		"user":  user,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"user":          user,
 	})
 }
 
+type updateLocaleRequest struct {
+	Locale string `json:"locale"`
+}
+
+// UpdateLocale @Summary Update email language
+// @Description Set the language used for emails sent to the current user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param locale body updateLocaleRequest true "Locale, e.g. en or ru"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /me/locale [put]
+func (a *UserController) UpdateLocale(c echo.Context) error {
+	var req updateLocaleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	user := domain.User{ID: c.Get("user_id").(int), Locale: mailer.NormalizeLocale(req.Locale)}
+	if err := a.userAppService.UpdateUserProfile(&user); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"locale": user.Locale})
+}
+
 type adminUpdateUserRequest struct {
 	Username *string `json:"username"`
 	Email    *string `json:"email"`
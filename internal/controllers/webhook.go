@@ -0,0 +1,238 @@
+package controllers
+
+import (
+	"MicroShopik/internal/domain"
+	domain2 "MicroShopik/internal/services/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookController struct {
+	webhookService domain2.WebhookService
+}
+
+func NewWebhookController(s domain2.WebhookService) *WebhookController {
+	return &WebhookController{webhookService: s}
+}
+
+type webhookEndpointRequest struct {
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	EventTypes  []string `json:"event_types"`
+	// IsActive applies to updates only and is left unchanged when omitted; new endpoints start active.
+	IsActive *bool `json:"is_active"`
+}
+
+// GetEventTypes @Summary Get webhook event types
+// @Description Get the event types a webhook endpoint can subscribe to
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /seller/webhooks/event-types [get]
+func (wc *WebhookController) GetEventTypes(c echo.Context) error {
+	return c.JSON(http.StatusOK, domain.WebhookEventTypes)
+}
+
+// GetEndpoints @Summary Get my webhook endpoints
+// @Description Get the current seller's webhook endpoints
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} domain.WebhookEndpoint
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /seller/webhooks [get]
+func (wc *WebhookController) GetEndpoints(c echo.Context) error {
+	endpoints, err := wc.webhookService.GetEndpoints(c.Get("user_id").(int))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, endpoints)
+}
+
+// CreateEndpoint @Summary Register a webhook endpoint
+// @Description Register a URL to receive signed order and chat events. The signing secret is returned only once.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param request body webhookEndpointRequest true "Endpoint URL, description and event types"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /seller/webhooks [post]
+func (wc *WebhookController) CreateEndpoint(c echo.Context) error {
+	var request webhookEndpointRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	endpoint, secret, err := wc.webhookService.CreateEndpoint(c.Get("user_id").(int), request.URL, request.Description, request.EventTypes)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"endpoint": endpoint,
+		"secret":   secret,
+	})
+}
+
+// UpdateEndpoint @Summary Update a webhook endpoint
+// @Description Change the URL, description or event types of a webhook endpoint, or pause it
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Endpoint ID"
+// @Param request body webhookEndpointRequest true "Endpoint settings"
+// @Success 200 {object} domain.WebhookEndpoint
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /seller/webhooks/{id} [put]
+func (wc *WebhookController) UpdateEndpoint(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid endpoint id"})
+	}
+
+	var request webhookEndpointRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	endpoint, err := wc.webhookService.UpdateEndpoint(id, c.Get("user_id").(int), request.URL, request.Description, request.EventTypes, request.IsActive)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, endpoint)
+}
+
+// DeleteEndpoint @Summary Delete a webhook endpoint
+// @Description Stop sending events to a webhook endpoint
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Endpoint ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /seller/webhooks/{id} [delete]
+func (wc *WebhookController) DeleteEndpoint(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid endpoint id"})
+	}
+
+	if err := wc.webhookService.DeleteEndpoint(id, c.Get("user_id").(int)); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "webhook endpoint deleted"})
+}
+
+// RotateSecret @Summary Rotate a webhook signing secret
+// @Description Replace the signing secret of a webhook endpoint. The new secret is returned only once.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Endpoint ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /seller/webhooks/{id}/rotate-secret [post]
+func (wc *WebhookController) RotateSecret(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid endpoint id"})
+	}
+
+	secret, err := wc.webhookService.RotateSecret(id, c.Get("user_id").(int))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"secret": secret})
+}
+
+// SendTest @Summary Send a test event
+// @Description Send a signed ping event to a webhook endpoint right away and return the delivery
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Endpoint ID"
+// @Success 200 {object} domain.WebhookDelivery
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /seller/webhooks/{id}/test [post]
+func (wc *WebhookController) SendTest(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid endpoint id"})
+	}
+
+	delivery, err := wc.webhookService.SendTest(id, c.Get("user_id").(int))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, delivery)
+}
+
+// GetDeliveries @Summary Get webhook deliveries
+// @Description Get the delivery log of a webhook endpoint, newest first
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Endpoint ID"
+// @Param limit query int false "Number of items per page (default: 20)"
+// @Param offset query int false "Number of items to skip (default: 0)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /seller/webhooks/{id}/deliveries [get]
+func (wc *WebhookController) GetDeliveries(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid endpoint id"})
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	deliveries, total, err := wc.webhookService.GetDeliveries(id, c.Get("user_id").(int), limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"deliveries": deliveries,
+		"total":      total,
+	})
+}
+
+// Redeliver @Summary Redeliver a webhook event
+// @Description Send the payload of an earlier delivery again, right away, as a new delivery
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Endpoint ID"
+// @Param deliveryID path int true "Delivery ID"
+// @Success 200 {object} domain.WebhookDelivery
+// @Failure 400 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /seller/webhooks/{id}/deliveries/{deliveryID}/redeliver [post]
+func (wc *WebhookController) Redeliver(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid endpoint id"})
+	}
+	deliveryID, err := strconv.Atoi(c.Param("deliveryID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid delivery id"})
+	}
+
+	delivery, err := wc.webhookService.Redeliver(id, deliveryID, c.Get("user_id").(int))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, delivery)
+}
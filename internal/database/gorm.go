@@ -24,6 +24,9 @@ func InitDB(cfg *configs.Config) error {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Checked before migrating, since AutoMigrate adds the column.
+	hadEmailVerification := db.Migrator().HasColumn(&domain.User{}, "email_verified_at")
+
 	err = db.AutoMigrate(
 		&domain.User{},
 		&domain.Product{},
@@ -34,11 +37,54 @@ func InitDB(cfg *configs.Config) error {
 		&domain.Conversation{},
 		&domain.Message{},
 		&domain.Participant{},
+		&domain.ProductModerationLog{},
+		&domain.ProductImportJob{},
+		&domain.ProductRevision{},
+		&domain.ProductCoPurchase{},
+		&domain.SlugRedirect{},
+		&domain.Attachment{},
+		&domain.MessageRevision{},
+		&domain.MessageReport{},
+		&domain.UserSanction{},
+		&domain.OutboxEvent{},
+		&domain.Notification{},
+		&domain.NotificationPreference{},
+		&domain.WebhookEndpoint{},
+		&domain.WebhookDelivery{},
+		&domain.JobRun{},
+		&domain.QueuedJob{},
+		&domain.Session{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	// Expression indexes can't be declared in struct tags. The 'simple' configuration
+	// doesn't stem, so Russian and English messages are matched the same way.
+	err = db.Exec(`CREATE INDEX IF NOT EXISTS idx_messages_content_search
+		ON messages USING gin (to_tsvector('simple', content))`).Error
+	if err != nil {
+		return fmt.Errorf("failed to create message search index: %w", err)
+	}
+
+	// Orders processed before orders.conversation_id existed are linked through their order card.
+	err = db.Exec(`UPDATE orders SET conversation_id = cards.conversation_id
+		FROM (SELECT order_id, MIN(conversation_id) AS conversation_id FROM messages
+			WHERE order_id IS NOT NULL GROUP BY order_id) AS cards
+		WHERE orders.id = cards.order_id AND orders.conversation_id IS NULL`).Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill order conversations: %w", err)
+	}
+
+	// Accounts created before email verification existed count as verified, so they keep
+	// ordering and selling without going through the new flow.
+	if !hadEmailVerification {
+		err = db.Exec(`UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL`).Error
+		if err != nil {
+			return fmt.Errorf("failed to backfill email verification: %w", err)
+		}
+	}
+
 	DB = db
 	log.Println("Database connected and migrated successfully")
 	return nil
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Attachment is a file uploaded to a conversation. It is linked to a message once that message is sent.
+type Attachment struct {
+	ID             int            `json:"id" gorm:"primaryKey;autoIncrement"`
+	ConversationID int            `json:"conversation_id" gorm:"not null;index"`
+	MessageID      *int           `json:"message_id" gorm:"index"`
+	UploaderID     int            `json:"uploader_id" gorm:"not null"`
+	FileName       string         `json:"file_name" gorm:"not null;size:255"`
+	ContentType    string         `json:"content_type" gorm:"not null;size:100"`
+	Size           int64          `json:"size" gorm:"not null"`
+	StorageKey     string         `json:"-" gorm:"not null;size:255"`
+	CreatedAt      time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+}
@@ -7,6 +7,7 @@ import (
 type Category struct {
 	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
 	Name      string    `json:"name" gorm:"not null;uniqueIndex:uni_categories_name;size:50"`
+	Slug      *string   `json:"slug,omitempty" gorm:"size:100;uniqueIndex"`
 	Products  []Product `json:"products,omitempty" gorm:"foreignKey:CategoryID"`
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
 }
@@ -5,14 +5,19 @@ import (
 	"time"
 )
 
+// Conversation is a chat between participants. A pre-purchase inquiry has both ProductID and
+// BuyerID set; there is at most one live inquiry per buyer and product.
 type Conversation struct {
 	ID              int            `json:"id" gorm:"primaryKey;autoIncrement"`
-	ProductID       *int           `json:"product_id"`
+	ProductID       *int           `json:"product_id" gorm:"uniqueIndex:idx_conversations_product_buyer,where:buyer_id IS NOT NULL AND deleted_at IS NULL"`
+	BuyerID         *int           `json:"buyer_id,omitempty" gorm:"uniqueIndex:idx_conversations_product_buyer"`
 	Product         *Product       `json:"product,omitempty" gorm:"foreignKey:ProductID;references:ID"`
 	Participants    []Participant  `json:"participants" gorm:"foreignKey:ConversationID"`
-	Messages        []Message      `json:"messages" gorm:"foreignKey:ConversationID"`
+	Messages        []Message      `json:"messages,omitempty" gorm:"foreignKey:ConversationID"`
 	LastMessage     string         `json:"last_message,omitempty" gorm:"-"`
 	LastMessageTime *time.Time     `json:"last_message_time,omitempty" gorm:"-"`
+	UnreadCount     int            `json:"unread_count" gorm:"-"`
+	OrderID         *int           `json:"order_id,omitempty" gorm:"-"`
 	CreatedAt       time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt       time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+const (
+	JobRunStatusRunning   = "running"
+	JobRunStatusSucceeded = "succeeded"
+	JobRunStatusFailed    = "failed"
+
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual"
+	JobTriggerStartup  = "startup"
+)
+
+// JobRun is one execution of a scheduled job. Scheduled runs record the slot they were started
+// for, which is unique per job, so a slot runs once however many instances are up.
+type JobRun struct {
+	ID          int        `json:"id" gorm:"primaryKey;autoIncrement"`
+	JobName     string     `json:"job_name" gorm:"not null;size:100;index:idx_job_runs_job_started,priority:1;uniqueIndex:idx_job_runs_slot,priority:1,where:scheduled_at IS NOT NULL"`
+	Trigger     string     `json:"trigger" gorm:"not null;size:20"`
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty" gorm:"uniqueIndex:idx_job_runs_slot,priority:2,where:scheduled_at IS NOT NULL"`
+	Status      string     `json:"status" gorm:"not null;size:20"`
+	Host        string     `json:"host" gorm:"size:255"`
+	StartedAt   time.Time  `json:"started_at" gorm:"not null;index:idx_job_runs_job_started,priority:2"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	DurationMs  int64      `json:"duration_ms"`
+	Error       string     `json:"error,omitempty" gorm:"type:text"`
+}
@@ -12,10 +12,29 @@ type Message struct {
 	OrderID        *int           `json:"order_id"`
 	Content        string         `json:"content" gorm:"type:text"`
 	IsSystem       bool           `json:"is_system" gorm:"default:false"`
+	IsDeleted      bool           `json:"is_deleted" gorm:"not null;default:false"`
+	EditedAt       *time.Time     `json:"edited_at,omitempty"`
 	Conversation   Conversation   `json:"conversation" gorm:"foreignKey:ConversationID"`
 	Sender         *User          `json:"sender" gorm:"foreignKey:SenderID"`
 	Order          *Order         `json:"order" gorm:"foreignKey:OrderID"`
+	Attachments    []Attachment   `json:"attachments" gorm:"foreignKey:MessageID"`
+	AttachmentIDs  []int          `json:"attachment_ids,omitempty" gorm:"-"`
 	CreatedAt      time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt      time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
 }
+
+// MessageCursor selects a page of a conversation's history by message ID. At most one field is set;
+// with none set the newest messages are returned.
+type MessageCursor struct {
+	Before int
+	After  int
+	Around int
+}
+
+// MessagePage is one window of a conversation's history, oldest message first.
+type MessagePage struct {
+	Messages []*Message `json:"messages"`
+	HasOlder bool       `json:"has_older"`
+	HasNewer bool       `json:"has_newer"`
+}
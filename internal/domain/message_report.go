@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+const (
+	MessageReportStatusOpen     = "open"
+	MessageReportStatusResolved = "resolved"
+)
+
+const (
+	MessageResolutionDismissed = "dismissed"
+	MessageResolutionDeleted   = "message_deleted"
+	MessageResolutionWarned    = "sender_warned"
+	MessageResolutionSuspended = "sender_suspended"
+)
+
+// MessageReportReasonContentFilter marks reports raised automatically by the content filter.
+const MessageReportReasonContentFilter = "content_filter"
+
+// MessageReportReasons lists the reason codes a participant can pick when reporting a message.
+var MessageReportReasons = map[string]string{
+	"off_platform": "Asks to pay or talk outside the marketplace",
+	"spam":         "Spam or advertising",
+	"abuse":        "Harassment or abusive language",
+	"fraud":        "Scam or fraud attempt",
+	"other":        "Other reason, see the comment",
+}
+
+// MessageReport puts a message in the moderation queue. ReporterID is empty for reports
+// raised by the content filter.
+type MessageReport struct {
+	ID               int        `json:"id" gorm:"primaryKey;autoIncrement"`
+	MessageID        int        `json:"message_id" gorm:"not null;uniqueIndex:idx_message_reports_message_reporter"`
+	ReporterID       *int       `json:"reporter_id" gorm:"uniqueIndex:idx_message_reports_message_reporter"`
+	Reason           string     `json:"reason" gorm:"not null;size:50"`
+	Comment          string     `json:"comment,omitempty" gorm:"type:text"`
+	Status           string     `json:"status" gorm:"not null;size:20;default:open;index"`
+	Resolution       string     `json:"resolution,omitempty" gorm:"size:30"`
+	ModeratorComment string     `json:"moderator_comment,omitempty" gorm:"type:text"`
+	ResolvedByID     *int       `json:"resolved_by_id,omitempty"`
+	ResolvedAt       *time.Time `json:"resolved_at,omitempty"`
+	Message          *Message   `json:"message,omitempty" gorm:"foreignKey:MessageID"`
+	Reporter         *User      `json:"reporter,omitempty" gorm:"foreignKey:ReporterID"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+const (
+	SanctionWarning    = "warning"
+	SanctionSuspension = "suspension"
+)
+
+// UserSanction records a warning or a messaging suspension issued by a moderator.
+type UserSanction struct {
+	ID         int        `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     int        `json:"user_id" gorm:"not null;index"`
+	Kind       string     `json:"kind" gorm:"not null;size:20"`
+	Reason     string     `json:"reason,omitempty" gorm:"type:text"`
+	MessageID  *int       `json:"message_id,omitempty"`
+	IssuedByID int        `json:"issued_by_id" gorm:"not null"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+const DeletedMessagePlaceholder = "message deleted"
+
+// MessageRevision keeps the content a message had before an edit or deletion.
+type MessageRevision struct {
+	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	MessageID int       `json:"message_id" gorm:"not null;index"`
+	EditorID  int       `json:"editor_id" gorm:"not null"`
+	Content   string    `json:"content" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// MessageSearchParams filters a full-text search over the messages of one user's conversations.
+type MessageSearchParams struct {
+	UserID    int
+	Query     string
+	ProductID *int
+	OrderID   *int
+	From      *time.Time
+	To        *time.Time
+	Limit     int
+	Offset    int
+}
+
+// MessageSearchResult is one matching message. Snippet is the raw message text around the match
+// with matched words wrapped in <mark></mark>; it is not HTML-escaped.
+type MessageSearchResult struct {
+	MessageID      int           `json:"message_id"`
+	ConversationID int           `json:"conversation_id"`
+	SenderID       *int          `json:"sender_id"`
+	SentAt         time.Time     `json:"sent_at"`
+	Snippet        string        `json:"snippet"`
+	Conversation   *Conversation `json:"conversation,omitempty" gorm:"-"`
+}
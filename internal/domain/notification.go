@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	NotificationOrderStatus       = "order_status"
+	NotificationNewMessage        = "new_message"
+	NotificationProductModeration = "product_moderation"
+	NotificationMessageModeration = "message_moderation"
+)
+
+// Notification is an item in a user's in-app notification center. Link is a frontend route
+// the item opens; Payload carries the type-specific details the client renders.
+type Notification struct {
+	ID        int             `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int             `json:"user_id" gorm:"not null;index:idx_notifications_user_read,priority:1"`
+	Type      string          `json:"type" gorm:"not null;size:50"`
+	Payload   json.RawMessage `json:"payload" gorm:"type:jsonb;not null"`
+	Link      string          `json:"link" gorm:"size:255"`
+	IsRead    bool            `json:"is_read" gorm:"not null;default:false;index:idx_notifications_user_read,priority:2"`
+	ReadAt    *time.Time      `json:"read_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	NotificationChannelInApp   = "in_app"
+	NotificationChannelEmail   = "email"
+	NotificationChannelWebhook = "webhook"
+)
+
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// NotificationEventChannels lists the channels each notification event type can be sent on.
+var NotificationEventChannels = map[string][]string{
+	NotificationOrderStatus:       {NotificationChannelInApp, NotificationChannelEmail, NotificationChannelWebhook},
+	NotificationNewMessage:        {NotificationChannelInApp, NotificationChannelEmail, NotificationChannelWebhook},
+	NotificationProductModeration: {NotificationChannelInApp},
+	NotificationMessageModeration: {NotificationChannelInApp},
+}
+
+// DigestPeriods maps a digest frequency to the time between two digests.
+var DigestPeriods = map[string]time.Duration{
+	DigestDaily:  24 * time.Hour,
+	DigestWeekly: 7 * 24 * time.Hour,
+}
+
+// NotificationPreference holds a user's notification settings. Channels maps an event type to
+// its per-channel switches; a missing switch means the channel is on. Quiet hours are "HH:MM"
+// in the user's timezone and may wrap past midnight; empty values turn them off.
+type NotificationPreference struct {
+	UserID          int                        `json:"-" gorm:"primaryKey;autoIncrement:false"`
+	Channels        map[string]map[string]bool `json:"channels" gorm:"serializer:json;type:jsonb;not null"`
+	Timezone        string                     `json:"timezone" gorm:"not null;size:64;default:'UTC'"`
+	QuietHoursStart string                     `json:"quiet_hours_start" gorm:"size:5"`
+	QuietHoursEnd   string                     `json:"quiet_hours_end" gorm:"size:5"`
+	DigestFrequency string                     `json:"digest_frequency" gorm:"not null;size:10;default:'off'"`
+	LastDigestAt    *time.Time                 `json:"last_digest_at,omitempty"`
+	UpdatedAt       time.Time                  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// DefaultNotificationPreference is what a user who never saved preferences gets: every channel
+// on, no quiet hours and no digest.
+func DefaultNotificationPreference(userID int) *NotificationPreference {
+	return &NotificationPreference{
+		UserID:          userID,
+		Channels:        map[string]map[string]bool{},
+		Timezone:        "UTC",
+		DigestFrequency: DigestOff,
+	}
+}
+
+func (p *NotificationPreference) Allows(eventType, channel string) bool {
+	enabled, ok := p.Channels[eventType][channel]
+	return !ok || enabled
+}
+
+// QuietUntil reports whether now falls in the user's quiet hours and, if so, when they end.
+func (p *NotificationPreference) QuietUntil(now time.Time) (time.Time, bool) {
+	if p.QuietHoursStart == "" || p.QuietHoursEnd == "" {
+		return time.Time{}, false
+	}
+	location, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	start, err1 := ParseClock(p.QuietHoursStart)
+	end, err2 := ParseClock(p.QuietHoursEnd)
+	if err1 != nil || err2 != nil || start == end {
+		return time.Time{}, false
+	}
+
+	local := now.In(location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+	minute := local.Hour()*60 + local.Minute()
+
+	if start < end {
+		if minute >= start && minute < end {
+			return midnight.Add(time.Duration(end) * time.Minute), true
+		}
+		return time.Time{}, false
+	}
+	// The quiet period wraps past midnight, e.g. 22:00-08:00.
+	if minute >= start {
+		return midnight.AddDate(0, 0, 1).Add(time.Duration(end) * time.Minute), true
+	}
+	if minute < end {
+		return midnight.Add(time.Duration(end) * time.Minute), true
+	}
+	return time.Time{}, false
+}
+
+// ParseClock turns "HH:MM" into minutes since midnight.
+func ParseClock(value string) (int, error) {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hours, &minutes); err != nil || len(value) != 5 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	if hours < 0 || hours > 23 || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return hours*60 + minutes, nil
+}
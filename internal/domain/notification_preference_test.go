@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestNotificationPreferenceQuietUntil(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.January, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name      string
+		timezone  string
+		start     string
+		end       string
+		now       time.Time
+		wantQuiet bool
+		wantUntil time.Time
+	}{
+		{name: "no quiet hours", timezone: "UTC", now: at(1, 12, 0)},
+		{name: "only a start", timezone: "UTC", start: "22:00", now: at(1, 23, 0)},
+		{name: "start equals end", timezone: "UTC", start: "08:00", end: "08:00", now: at(1, 8, 0)},
+		{name: "invalid clock", timezone: "UTC", start: "25:00", end: "08:00", now: at(1, 1, 0)},
+
+		{name: "same day, inside", timezone: "UTC", start: "09:00", end: "17:00", now: at(1, 12, 0), wantQuiet: true, wantUntil: at(1, 17, 0)},
+		{name: "same day, at start", timezone: "UTC", start: "09:00", end: "17:00", now: at(1, 9, 0), wantQuiet: true, wantUntil: at(1, 17, 0)},
+		{name: "same day, at end", timezone: "UTC", start: "09:00", end: "17:00", now: at(1, 17, 0)},
+		{name: "same day, before", timezone: "UTC", start: "09:00", end: "17:00", now: at(1, 8, 59)},
+
+		{name: "wrapping, before midnight", timezone: "UTC", start: "22:00", end: "08:00", now: at(1, 23, 30), wantQuiet: true, wantUntil: at(2, 8, 0)},
+		{name: "wrapping, at start", timezone: "UTC", start: "22:00", end: "08:00", now: at(1, 22, 0), wantQuiet: true, wantUntil: at(2, 8, 0)},
+		{name: "wrapping, after midnight", timezone: "UTC", start: "22:00", end: "08:00", now: at(2, 2, 0), wantQuiet: true, wantUntil: at(2, 8, 0)},
+		{name: "wrapping, at end", timezone: "UTC", start: "22:00", end: "08:00", now: at(2, 8, 0)},
+		{name: "wrapping, daytime", timezone: "UTC", start: "22:00", end: "08:00", now: at(2, 12, 0)},
+		{name: "wrapping, end of month", timezone: "UTC", start: "22:00", end: "08:00", now: time.Date(2024, time.January, 31, 23, 0, 0, 0, time.UTC), wantQuiet: true, wantUntil: time.Date(2024, time.February, 1, 8, 0, 0, 0, time.UTC)},
+
+		// 20:00 UTC is 23:00 in Moscow (UTC+3), so quiet hours end at 08:00 Moscow, 05:00 UTC.
+		{name: "user timezone", timezone: "Europe/Moscow", start: "22:00", end: "08:00", now: at(1, 20, 0), wantQuiet: true, wantUntil: at(2, 5, 0)},
+		{name: "user timezone, not yet quiet", timezone: "Europe/Moscow", start: "22:00", end: "08:00", now: at(1, 18, 30)},
+		{name: "unknown timezone falls back to UTC", timezone: "Mars/Olympus", start: "22:00", end: "08:00", now: at(1, 23, 0), wantQuiet: true, wantUntil: at(2, 8, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preference := &NotificationPreference{Timezone: tt.timezone, QuietHoursStart: tt.start, QuietHoursEnd: tt.end}
+			until, quiet := preference.QuietUntil(tt.now)
+			if quiet != tt.wantQuiet {
+				t.Fatalf("QuietUntil(%v) quiet = %v, want %v", tt.now, quiet, tt.wantQuiet)
+			}
+			if !until.Equal(tt.wantUntil) {
+				t.Errorf("QuietUntil(%v) = %v, want %v", tt.now, until, tt.wantUntil)
+			}
+		})
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{value: "00:00", want: 0},
+		{value: "08:30", want: 8*60 + 30},
+		{value: "23:59", want: 23*60 + 59},
+		{value: "24:00", wantErr: true},
+		{value: "12:60", wantErr: true},
+		{value: "8:30", wantErr: true},
+		{value: "08:30:00", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseClock(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseClock(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseClock(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusDelivered = "delivered"
+	OutboxStatusDead      = "dead"
+)
+
+// OutboxEvent is a domain event stored in the same transaction as the change that caused it.
+// The relay delivers it at least once; events that keep failing end up dead for an admin to retry.
+// DeliveredTo lists the subscribers that already handled it, so retries only reach the others.
+type OutboxEvent struct {
+	ID            int             `json:"id" gorm:"primaryKey;autoIncrement"`
+	EventName     string          `json:"event_name" gorm:"not null;size:100"`
+	Payload       json.RawMessage `json:"payload" gorm:"type:jsonb;not null"`
+	Status        string          `json:"status" gorm:"not null;size:20;default:pending;index:idx_outbox_events_due,priority:1"`
+	Attempts      int             `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt time.Time       `json:"next_attempt_at" gorm:"not null;index:idx_outbox_events_due,priority:2"`
+	LastError     string          `json:"last_error,omitempty" gorm:"type:text"`
+	DeliveredTo   []string        `json:"delivered_to" gorm:"serializer:json;type:jsonb"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
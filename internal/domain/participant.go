@@ -6,11 +6,22 @@ import (
 )
 
 type Participant struct {
-	ConversationID int            `json:"conversation_id" gorm:"primaryKey"`
-	UserID         int            `json:"user_id" gorm:"primaryKey"`
-	Conversation   Conversation   `json:"conversation" gorm:"foreignKey:ConversationID"`
-	User           User           `json:"user" gorm:"foreignKey:UserID"`
-	CreatedAt      time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt      time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+	ConversationID       int            `json:"conversation_id" gorm:"primaryKey"`
+	UserID               int            `json:"user_id" gorm:"primaryKey"`
+	Conversation         Conversation   `json:"conversation" gorm:"foreignKey:ConversationID"`
+	User                 User           `json:"user" gorm:"foreignKey:UserID"`
+	LastReadMessageID    *int           `json:"last_read_message_id"`
+	LastReadAt           *time.Time     `json:"last_read_at"`
+	LastEmailedMessageID *int           `json:"-"`
+	CreatedAt            time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt            time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt            gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// UnreadNotice describes messages a participant has neither read nor been emailed about yet.
+type UnreadNotice struct {
+	ConversationID int
+	UserID         int
+	Count          int
+	LastMessageID  int
 }
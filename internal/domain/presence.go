@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// Presence is what other users may see about someone's connection state.
+// Online and LastSeenAt stay empty for users who hide their online status.
+type Presence struct {
+	UserID     int        `json:"user_id"`
+	Online     bool       `json:"online"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	Hidden     bool       `json:"hidden"`
+}
@@ -7,18 +7,23 @@ import (
 )
 
 type Product struct {
-	ID          int            `json:"id" gorm:"primaryKey;autoIncrement"`
-	SellerID    int            `json:"seller_id" gorm:"not null"`
-	Title       string         `json:"title" gorm:"not null;size:255"`
-	Description string         `json:"description" gorm:"type:text"`
-	Price       int64          `json:"price" gorm:"not null"`
-	CategoryID  int            `json:"category_id" gorm:"not null"`
-	IsActive    bool           `json:"is_active" gorm:"not null"`
-	Category    Category       `json:"category" gorm:"foreignKey:CategoryID;references:ID"`
-	Disposable  bool           `json:"disposable" gorm:"default:false"`
-	MaxSales    int            `json:"max_sales" gorm:"not null;default:0"`
-	SoldCount   int            `json:"sold_count" gorm:"not null;default:0"`
-	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID               int            `json:"id" gorm:"primaryKey;autoIncrement"`
+	SellerID         int            `json:"seller_id" gorm:"not null;uniqueIndex:idx_products_seller_sku"`
+	SKU              *string        `json:"sku,omitempty" gorm:"size:100;uniqueIndex:idx_products_seller_sku"`
+	Title            string         `json:"title" gorm:"not null;size:255"`
+	Slug             *string        `json:"slug,omitempty" gorm:"size:255;uniqueIndex"`
+	Description      string         `json:"description" gorm:"type:text"`
+	Price            int64          `json:"price" gorm:"not null"`
+	CategoryID       int            `json:"category_id" gorm:"not null"`
+	IsActive         bool           `json:"is_active" gorm:"not null"`
+	Category         Category       `json:"category" gorm:"foreignKey:CategoryID;references:ID"`
+	Disposable       bool           `json:"disposable" gorm:"default:false"`
+	MaxSales         int            `json:"max_sales" gorm:"not null;default:0"`
+	SoldCount        int            `json:"sold_count" gorm:"not null;default:0"`
+	ModerationStatus string         `json:"moderation_status" gorm:"not null;default:'approved';size:20;index"`
+	RejectionCode    string         `json:"rejection_code,omitempty" gorm:"size:50"`
+	RejectionReason  string         `json:"rejection_reason,omitempty" gorm:"type:text"`
+	CreatedAt        time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
 }
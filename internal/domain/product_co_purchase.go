@@ -0,0 +1,13 @@
+package domain
+
+import (
+	"time"
+)
+
+// ProductCoPurchase counts the distinct buyers who bought both ProductID and RelatedProductID.
+type ProductCoPurchase struct {
+	ProductID        int       `json:"product_id" gorm:"primaryKey"`
+	RelatedProductID int       `json:"related_product_id" gorm:"primaryKey"`
+	Score            int       `json:"score" gorm:"not null;index"`
+	UpdatedAt        time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ImportJobStatusQueued    = "queued"
+	ImportJobStatusRunning   = "running"
+	ImportJobStatusCompleted = "completed"
+	ImportJobStatusFailed    = "failed"
+)
+
+const (
+	ImportRowActionCreate = "create"
+	ImportRowActionUpdate = "update"
+	ImportRowActionError  = "error"
+)
+
+// ProductImportRow is a single product line from a seller's CSV or JSON import file.
+type ProductImportRow struct {
+	Row         int    `json:"-"`
+	SKU         string `json:"sku"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Price       int64  `json:"price"`
+	CategoryID  int    `json:"category_id"`
+	Disposable  bool   `json:"disposable"`
+	MaxSales    int    `json:"max_sales"`
+	IsActive    *bool  `json:"is_active,omitempty"`
+}
+
+type ProductImportRowResult struct {
+	Row       int      `json:"row"`
+	SKU       string   `json:"sku,omitempty"`
+	Action    string   `json:"action"`
+	ProductID int      `json:"product_id,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+type ProductImportJob struct {
+	ID            int             `json:"id" gorm:"primaryKey;autoIncrement"`
+	SellerID      int             `json:"seller_id" gorm:"not null;index"`
+	Format        string          `json:"format" gorm:"not null;size:10"`
+	Status        string          `json:"status" gorm:"not null;default:'queued';size:20"`
+	TotalRows     int             `json:"total_rows" gorm:"not null;default:0"`
+	ProcessedRows int             `json:"processed_rows" gorm:"not null;default:0"`
+	CreatedCount  int             `json:"created_count" gorm:"not null;default:0"`
+	UpdatedCount  int             `json:"updated_count" gorm:"not null;default:0"`
+	FailedCount   int             `json:"failed_count" gorm:"not null;default:0"`
+	Errors        json.RawMessage `json:"errors,omitempty" gorm:"type:jsonb"`
+	CreatedAt     time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+	FinishedAt    *time.Time      `json:"finished_at"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	ProductStatusPendingReview = "pending_review"
+	ProductStatusApproved      = "approved"
+	ProductStatusRejected      = "rejected"
+)
+
+const (
+	ModerationActionSubmitted   = "submitted"
+	ModerationActionResubmitted = "resubmitted"
+	ModerationActionApproved    = "approved"
+	ModerationActionRejected    = "rejected"
+)
+
+// ProductRejectionReasons lists the reason codes an admin can pick when rejecting a product.
+var ProductRejectionReasons = map[string]string{
+	"prohibited_item":     "The product is not allowed on the marketplace",
+	"misleading_content":  "Title or description is misleading or inaccurate",
+	"wrong_category":      "The product is listed in the wrong category",
+	"pricing_violation":   "The price looks incorrect or violates pricing rules",
+	"insufficient_detail": "The description does not explain what the buyer receives",
+	"duplicate_listing":   "The product duplicates an existing listing",
+	"other":               "Other reason, see the moderator comment",
+}
+
+type ProductModerationLog struct {
+	ID         int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	ProductID  int       `json:"product_id" gorm:"not null;index"`
+	ActorID    int       `json:"actor_id" gorm:"not null"`
+	Action     string    `json:"action" gorm:"not null;size:20"`
+	ReasonCode string    `json:"reason_code,omitempty" gorm:"size:50"`
+	Comment    string    `json:"comment,omitempty" gorm:"type:text"`
+	Actor      *User     `json:"actor,omitempty" gorm:"foreignKey:ActorID"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProductFieldChange is the before and after value of a single product field.
+type ProductFieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+type ProductRevision struct {
+	ID        int             `json:"id" gorm:"primaryKey;autoIncrement"`
+	ProductID int             `json:"product_id" gorm:"not null;index"`
+	ActorID   int             `json:"actor_id" gorm:"not null"`
+	Changes   json.RawMessage `json:"changes" gorm:"type:jsonb;not null"`
+	Actor     *User           `json:"actor,omitempty" gorm:"foreignKey:ActorID"`
+	CreatedAt time.Time       `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	QueuedJobStatusQueued    = "queued"
+	QueuedJobStatusRunning   = "running"
+	QueuedJobStatusSucceeded = "succeeded"
+	QueuedJobStatusDead      = "dead"
+	QueuedJobStatusCancelled = "cancelled"
+)
+
+// QueuedJob is a unit of background work stored in Postgres. Workers claim due jobs, highest
+// priority first; a failed job is queued again with a later run-at until it runs out of attempts
+// and is marked dead. A unique key allows only one unfinished job with that key at a time.
+type QueuedJob struct {
+	ID          int             `json:"id" gorm:"primaryKey;autoIncrement"`
+	Type        string          `json:"type" gorm:"not null;size:100;index"`
+	Payload     json.RawMessage `json:"payload" gorm:"type:jsonb;not null"`
+	Status      string          `json:"status" gorm:"not null;size:20;default:queued;index:idx_queued_jobs_due,priority:1"`
+	Priority    int             `json:"priority" gorm:"not null;default:0;index:idx_queued_jobs_due,priority:2,sort:desc"`
+	RunAt       time.Time       `json:"run_at" gorm:"not null;index:idx_queued_jobs_due,priority:3"`
+	Attempts    int             `json:"attempts" gorm:"not null;default:0"`
+	MaxAttempts int             `json:"max_attempts" gorm:"not null"`
+	UniqueKey   *string         `json:"unique_key,omitempty" gorm:"size:255;uniqueIndex:idx_queued_jobs_unique_key,where:unique_key IS NOT NULL AND finished_at IS NULL"`
+	LastError   string          `json:"last_error,omitempty" gorm:"type:text"`
+	LockedBy    string          `json:"locked_by,omitempty" gorm:"size:255"`
+	LockedAt    *time.Time      `json:"locked_at,omitempty"`
+	HeartbeatAt *time.Time      `json:"heartbeat_at,omitempty"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
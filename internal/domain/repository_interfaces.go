@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -14,6 +16,8 @@ type ProductQueryParams struct {
 	SearchQuery *string
 	Limit       *int
 	Offset      *int
+
+	ModerationStatus *string
 }
 
 type ProductUpdateData struct {
@@ -24,6 +28,7 @@ type ProductUpdateData struct {
 	IsActive    *bool
 	Disposable  *bool
 	MaxSales    *int
+	Slug        *string
 }
 
 type UserRepository interface {
@@ -37,6 +42,16 @@ type UserRepository interface {
 	RemoveRole(userID int, roleName string) error
 	GetRoles(userID int) ([]string, error)
 	LastLoginUpdate(userID int) error
+	UpdateLastSeen(userID int, at time.Time) error
+	SetHideOnlineStatus(userID int, hide bool) error
+	// MarkEmailVerified verifies the user's email if it is still the given address and not
+	// verified yet, and reports whether it did.
+	MarkEmailVerified(userID int, email string, at time.Time) (bool, error)
+	// ClearEmailVerification also resets the resend cooldown, which belonged to the old address.
+	ClearEmailVerification(userID int) error
+	// ClaimVerificationSend records a verification email unless one went out after the cutoff,
+	// and reports whether it did.
+	ClaimVerificationSend(userID int, at, cutoff time.Time) (bool, error)
 }
 
 type RoleRepository interface {
@@ -54,6 +69,7 @@ type ProductRepository interface {
 	Create(product *Product) (int, error)
 	GetById(id int) (*Product, error)
 	Update(id int, data ProductUpdateData) error
+	UpdateWithRevision(id int, data ProductUpdateData, revision *ProductRevision) error
 	Delete(id int) error
 	IsAvailable(id int) (bool, error)
 	IncrementSoldCount(id int, delta int) error
@@ -63,6 +79,32 @@ type ProductRepository interface {
 	Count(params ProductQueryParams) (int, error)
 	GetAll() ([]*Product, error)
 	GetBySellerID(sellerID int) ([]*Product, error)
+	GetBySellerAndSKU(sellerID int, sku string) (*Product, error)
+	GetBySlug(slug string) (*Product, error)
+	GetByModerationStatus(status string, limit, offset int) ([]*Product, error)
+	UpdateModerationStatus(id int, status string, isActive bool, reasonCode, reason string) error
+}
+
+type ProductRecommendationRepository interface {
+	RebuildCoPurchases(orderStatuses []string) (int64, error)
+	GetCoPurchased(productID, minScore, limit int) ([]*Product, error)
+	GetPopularInCategory(categoryID int, excludeIDs []int, limit int) ([]*Product, error)
+}
+
+type ProductRevisionRepository interface {
+	GetByID(id int) (*ProductRevision, error)
+	GetByProductID(productID int) ([]*ProductRevision, error)
+}
+
+type ProductImportJobRepository interface {
+	Create(job *ProductImportJob) error
+	GetByID(id int) (*ProductImportJob, error)
+	Update(job *ProductImportJob) error
+}
+
+type ProductModerationLogRepository interface {
+	Create(entry *ProductModerationLog) error
+	GetByProductID(productID int) ([]*ProductModerationLog, error)
 }
 
 type CategoryRepository interface {
@@ -72,6 +114,18 @@ type CategoryRepository interface {
 	Update(category *Category) (*Category, error)
 	Delete(category *Category) error
 	GetAllCategories() (*[]Category, error)
+	GetBySlug(slug string) (*Category, error)
+}
+
+type SlugRepository interface {
+	IsTaken(entityType, slug string, entityID int) (bool, error)
+	SetSlug(entityType string, entityID int, slug string) error
+	GetMissing(entityType string, limit int) ([]SlugSource, error)
+	CreateRedirect(redirect *SlugRedirect) error
+	GetRedirect(entityType, slug string) (*SlugRedirect, error)
+	DeleteRedirect(entityType, slug string, entityID int) error
+	CountSitemapEntries(entityType string) (int, error)
+	GetSitemapEntries(entityType string, limit, offset int) ([]SitemapEntry, error)
 }
 
 type ConversationRepository interface {
@@ -79,6 +133,14 @@ type ConversationRepository interface {
 	GetByID(id int) (*Conversation, error)
 	GetByUserID(userID int) ([]*Conversation, error)
 	GetByProductID(productID int) ([]*Conversation, error)
+	// FindOrCreateInquiry creates the inquiry with its participants unless the buyer already has one
+	// for the product, in which case conversation is filled with the existing row and created is false.
+	FindOrCreateInquiry(conversation *Conversation, participantIDs []int) (created bool, err error)
+	// GetInquiry returns the buyer's live inquiry about the product, or nil if there is none.
+	GetInquiry(productID, buyerID int) (*Conversation, error)
+	// GetLatestBetween returns the newest conversation whose only participants are the two users, or nil.
+	GetLatestBetween(userID, otherUserID int) (*Conversation, error)
+	GetByIDs(ids []int) ([]*Conversation, error)
 	Update(conversation *Conversation) error
 	Delete(id int) error
 	AddParticipant(conversationID, userID int) error
@@ -90,12 +152,39 @@ type ConversationRepository interface {
 	Rollback(tx *gorm.DB) error
 }
 
+type MessageReportRepository interface {
+	Create(report *MessageReport) error
+	GetOpen(limit, offset int) ([]*MessageReport, int64, error)
+	CountOpenByMessageID(messageID int) (int64, error)
+	ResolveOpen(messageID int, resolution, comment string, resolverID int) error
+}
+
+type UserSanctionRepository interface {
+	Create(sanction *UserSanction) error
+	GetByUserID(userID int) ([]*UserSanction, error)
+	GetActiveSuspension(userID int) (*UserSanction, error)
+}
+
+type AttachmentRepository interface {
+	Create(attachment *Attachment) error
+	GetByID(id int) (*Attachment, error)
+	GetByMessageID(messageID int) ([]Attachment, error)
+	CountAttachable(ids []int, conversationID, uploaderID int) (int, error)
+	AttachToMessage(ids []int, messageID int) error
+}
+
 type ParticipantRepository interface {
 	Create(participant *Participant) error
 	GetByConversationID(conversationID int) ([]*Participant, error)
 	GetByUserID(userID int) ([]*Participant, error)
 	Delete(conversationID, userID int) error
 	IsParticipant(conversationID, userID int) (bool, error)
+	MarkRead(conversationID, userID, messageID int) (bool, error)
+	GetUnreadCounts(userID int) (map[int]int, error)
+	// GetUnreadForEmail finds participants whose oldest unread, not yet emailed message from
+	// someone else was sent before olderThan.
+	GetUnreadForEmail(olderThan time.Time, limit int) ([]*UnreadNotice, error)
+	MarkEmailed(conversationID, userID, messageID int) error
 }
 
 type OrderRepository interface {
@@ -109,14 +198,148 @@ type OrderRepository interface {
 	Update(order *Order) error
 	Delete(id int) error
 	UpdateStatus(id int, status string) error
+	SetConversationID(id, conversationID int) error
+	BeginTx() (*gorm.DB, error)
+	CreateTx(tx *gorm.DB, order *Order) error
+	UpdateStatusTx(tx *gorm.DB, id int, status string) error
+	Commit(tx *gorm.DB) error
+	Rollback(tx *gorm.DB) error
+}
+
+type OutboxRepository interface {
+	Create(event *OutboxEvent) error
+	CreateTx(tx *gorm.DB, event *OutboxEvent) error
+	// ClaimDue takes up to limit pending events that are due and pushes their next attempt
+	// lease into the future, so other relays skip them until the lease runs out. An event
+	// whose relay dies midway is therefore picked up again once its lease expires.
+	ClaimDue(limit int, lease time.Duration) ([]*OutboxEvent, error)
+	Update(event *OutboxEvent) error
+	GetByStatus(status string, limit, offset int) ([]*OutboxEvent, int64, error)
+	CountByStatus() (map[string]int64, error)
+	// Requeue makes a dead event pending again with a fresh attempt budget.
+	Requeue(id int) error
+}
+
+type NotificationPreferenceRepository interface {
+	// GetByUserID returns nil when the user never saved preferences.
+	GetByUserID(userID int) (*NotificationPreference, error)
+	Save(preference *NotificationPreference) error
+	// GetDigestDue returns preferences with a digest whose last one went out before the cutoff
+	// for its frequency.
+	GetDigestDue(now time.Time, limit int) ([]*NotificationPreference, error)
+	SetLastDigestAt(userID int, at time.Time) error
+}
+
+type JobRunRepository interface {
+	// Create skips a scheduled run whose slot already has a run and reports whether a row was
+	// inserted.
+	Create(run *JobRun) (bool, error)
+	Update(run *JobRun) error
+	GetByJobName(jobName string, limit, offset int) ([]*JobRun, int64, error)
+	// GetLatest returns the most recent run of each of the given jobs, keyed by job name.
+	GetLatest(jobNames []string) (map[string]*JobRun, error)
+	// FailRunning marks the job's runs that are still running as failed; call it only while
+	// holding the job's lock, when no run can actually be in progress.
+	FailRunning(jobName, reason string, at time.Time) error
+}
+
+type SessionRepository interface {
+	BeginTx() (*gorm.DB, error)
+	CreateTx(tx *gorm.DB, session *Session) error
+	// GetByTokenHashTx locks the row until tx ends, so two refreshes with the same token run
+	// one after the other.
+	GetByTokenHashTx(tx *gorm.DB, tokenHash string) (*Session, error)
+	UpdateTx(tx *gorm.DB, session *Session) error
+	RevokeFamilyTx(tx *gorm.DB, familyID, reason string, at time.Time) error
+	// GetActiveByUserID returns the current token of each of the user's live sessions, most
+	// recently used first.
+	GetActiveByUserID(userID int, now time.Time) ([]*Session, error)
+	// RevokeUserFamily reports false when the user has no live session with that ID.
+	RevokeUserFamily(userID int, familyID, reason string, at time.Time) (bool, error)
+	RevokeAllByUserID(userID int, reason string, at time.Time) error
+	// IsFamilyRevoked reports true when no token of the family is left unrevoked.
+	IsFamilyRevoked(familyID string) (bool, error)
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+type QueuedJobRepository interface {
+	// Create skips a job whose unique key belongs to an unfinished job and reports whether a row
+	// was inserted.
+	Create(job *QueuedJob) (bool, error)
+	GetByID(id int) (*QueuedJob, error)
+	// GetActiveByUniqueKey returns the unfinished job holding the key.
+	GetActiveByUniqueKey(key string) (*QueuedJob, error)
+	// Claim marks up to limit due jobs of the given types as running on the worker and counts
+	// the attempt. Jobs locked by another worker are skipped.
+	Claim(workerID string, types []string, limit int) ([]*QueuedJob, error)
+	// Release stores the outcome of a claimed job. It reports false when the job no longer
+	// belongs to its worker, for instance after it was presumed dead and claimed again.
+	Release(job *QueuedJob) (bool, error)
+	Heartbeat(ids []int, at time.Time) error
+	// RequeueStale puts back running jobs whose worker has not sent a heartbeat since the
+	// cutoff, or marks them dead when they are out of attempts. It returns how many were put
+	// back and the jobs that died.
+	RequeueStale(heartbeatBefore time.Time) (int64, []*QueuedJob, error)
+	// GetByStatus returns jobs newest first; empty filters match all.
+	GetByStatus(status, jobType string, limit, offset int) ([]*QueuedJob, int64, error)
+	CountByStatus() (map[string]int64, error)
+	// Requeue makes a dead or cancelled job due now with a fresh attempt budget.
+	Requeue(id int) error
+	// Cancel finishes a queued job without running it.
+	Cancel(id int) error
+}
+
+type WebhookEndpointRepository interface {
+	Create(endpoint *WebhookEndpoint) error
+	GetByID(id int) (*WebhookEndpoint, error)
+	GetBySellerID(sellerID int) ([]*WebhookEndpoint, error)
+	CountBySellerID(sellerID int) (int64, error)
+	Update(endpoint *WebhookEndpoint) error
+	Delete(id int) error
+}
+
+type WebhookDeliveryRepository interface {
+	// Create skips a delivery whose event was already queued for the endpoint and reports
+	// whether a row was inserted, so event handlers can run more than once.
+	Create(delivery *WebhookDelivery) (bool, error)
+	GetByID(id int) (*WebhookDelivery, error)
+	GetByEndpointID(endpointID int, limit, offset int) ([]*WebhookDelivery, int64, error)
+	// ClaimDue picks up to limit pending deliveries that are due and pushes their next attempt
+	// lease into the future, so other workers skip them while they are being sent.
+	ClaimDue(limit int, lease time.Duration) ([]*WebhookDelivery, error)
+	Update(delivery *WebhookDelivery) error
+}
+
+type NotificationRepository interface {
+	Create(notification *Notification) error
+	// ReplaceUnread drops the user's unread notifications of the same type and link before
+	// creating this one, so a busy conversation shows up once instead of once per message.
+	ReplaceUnread(notification *Notification) error
+	GetByUserID(userID int, limit, offset int) ([]*Notification, int64, error)
+	CountUnread(userID int) (int64, error)
+	// GetUnreadSince returns up to limit unread notifications created after since, oldest first,
+	// and how many there are in total.
+	GetUnreadSince(userID int, since time.Time, limit int) ([]*Notification, int64, error)
+	MarkRead(id, userID int) error
+	MarkAllRead(userID int) error
 }
 
 type MessageRepository interface {
 	Create(message *Message) error
 	GetByID(id int) (*Message, error)
-	GetByConversationID(conversationID int, limit, offset int) ([]*Message, error)
+	GetBefore(conversationID, beforeID, limit int) ([]*Message, error)
+	GetAfter(conversationID, afterID, limit int) ([]*Message, error)
+	GetLatestForConversations(conversationIDs []int) ([]*Message, error)
+	GetOrderIDsForConversations(conversationIDs []int) (map[int]int, error)
+	Search(params MessageSearchParams) ([]*MessageSearchResult, error)
 	GetByOrderID(orderID int) ([]*Message, error)
 	Update(message *Message) error
 	Delete(id int) error
 	GetSystemMessages(conversationID int) ([]*Message, error)
+	GetSinceForUser(userID, sinceID, limit int) ([]*Message, error)
+	GetLatestID(conversationID int) (int, error)
+	Edit(id int, content string, revision *MessageRevision) error
+	// Tombstone returns the attachments it removed from the message.
+	Tombstone(id int, revision *MessageRevision) ([]Attachment, error)
+	GetRevisions(messageID int) ([]*MessageRevision, error)
 }
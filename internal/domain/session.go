@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+const (
+	SessionRevokeLogout      = "logout"
+	SessionRevokeUser        = "revoked by user"
+	SessionRevokeReuse       = "refresh token reuse"
+	SessionRevokeRoleChange  = "role removed"
+	SessionRevokePassword    = "password changed"
+	SessionRevokeUserDeleted = "user deleted"
+)
+
+// Session is one refresh token of a login. Every refresh rotates the token: the row is marked
+// rotated and a new row joins the same family, whose ID is the session ID users see. Only the
+// SHA-256 hash of a token is stored. Presenting a rotated token again means it leaked, and the
+// whole family is revoked.
+type Session struct {
+	ID           int        `json:"-" gorm:"primaryKey;autoIncrement"`
+	FamilyID     string     `json:"id" gorm:"not null;size:64;index"`
+	UserID       int        `json:"-" gorm:"not null;index"`
+	TokenHash    string     `json:"-" gorm:"not null;size:64;uniqueIndex"`
+	UserAgent    string     `json:"user_agent" gorm:"size:255"`
+	IP           string     `json:"ip" gorm:"size:64"`
+	StartedAt    time.Time  `json:"started_at" gorm:"not null"`
+	CreatedAt    time.Time  `json:"last_used_at" gorm:"autoCreateTime"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null;index"`
+	RotatedAt    *time.Time `json:"-"`
+	RevokedAt    *time.Time `json:"-"`
+	RevokeReason string     `json:"-" gorm:"size:50"`
+
+	Current bool `json:"current" gorm:"-"`
+}
+
+// SessionClient describes who is starting or refreshing a session, for the sessions list.
+type SessionClient struct {
+	UserAgent string
+	IP        string
+}
+
+// AuthTokens is what a login or refresh hands out. ExpiresIn is the access token lifetime in
+// seconds.
+type AuthTokens struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+const (
+	SlugEntityProduct  = "product"
+	SlugEntityCategory = "category"
+)
+
+// SlugRedirect keeps a retired slug pointing at its entity so old links keep working.
+type SlugRedirect struct {
+	ID         int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	EntityType string    `json:"entity_type" gorm:"not null;size:20;uniqueIndex:idx_slug_redirects_entity_slug"`
+	OldSlug    string    `json:"old_slug" gorm:"not null;size:255;uniqueIndex:idx_slug_redirects_entity_slug"`
+	EntityID   int       `json:"entity_id" gorm:"not null;index"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// SlugSource is an entity that still needs a slug, with the text to build it from.
+type SlugSource struct {
+	ID     int
+	Source string
+}
+
+type SitemapEntry struct {
+	Slug      string
+	UpdatedAt time.Time
+}
@@ -16,7 +16,14 @@ type User struct {
 	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 	LastLogin *time.Time     `json:"last_login"`
+	Locale    string         `json:"locale" gorm:"not null;size:10;default:'en'"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	LastSeenAt       *time.Time `json:"-"`
+	HideOnlineStatus bool       `json:"hide_online_status" gorm:"not null;default:false"`
+
+	EmailVerifiedAt    *time.Time `json:"email_verified_at"`
+	VerificationSentAt *time.Time `json:"-"`
 }
 
 type AuthRequest struct {
@@ -26,5 +33,7 @@ type AuthRequest struct {
 type JWTClaims struct {
 	UserID int      `json:"user_id"`
 	Roles  []string `json:"roles"`
+	// SessionID is the session the access token was issued for.
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
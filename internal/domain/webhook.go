@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	WebhookEventOrderCreated    = "order.created"
+	WebhookEventOrderConfirmed  = "order.confirmed"
+	WebhookEventOrderCancelled  = "order.cancelled"
+	WebhookEventMessageReceived = "message.received"
+	// WebhookEventPing is only sent on request, to test an endpoint.
+	WebhookEventPing = "ping"
+)
+
+// WebhookEventTypes lists the events a seller can subscribe an endpoint to.
+var WebhookEventTypes = map[string]string{
+	WebhookEventOrderCreated:    "A customer placed an order for one of your products",
+	WebhookEventOrderConfirmed:  "An order for one of your products was confirmed",
+	WebhookEventOrderCancelled:  "An order for one of your products was cancelled",
+	WebhookEventMessageReceived: "You received a chat message",
+}
+
+// WebhookEventNotificationTypes maps webhook events to the notification event type whose
+// webhook preference controls them.
+var WebhookEventNotificationTypes = map[string]string{
+	WebhookEventOrderCreated:    NotificationOrderStatus,
+	WebhookEventOrderConfirmed:  NotificationOrderStatus,
+	WebhookEventOrderCancelled:  NotificationOrderStatus,
+	WebhookEventMessageReceived: NotificationNewMessage,
+}
+
+const (
+	WebhookDeliveryStatusPending   = "pending"
+	WebhookDeliveryStatusSucceeded = "succeeded"
+	WebhookDeliveryStatusFailed    = "failed"
+)
+
+// WebhookEndpoint is a seller's URL that receives signed event notifications. The secret is
+// shown once, when the endpoint is created or the secret is rotated.
+type WebhookEndpoint struct {
+	ID          int            `json:"id" gorm:"primaryKey;autoIncrement"`
+	SellerID    int            `json:"seller_id" gorm:"not null;index"`
+	URL         string         `json:"url" gorm:"not null;size:2048"`
+	Description string         `json:"description,omitempty" gorm:"size:255"`
+	EventTypes  []string       `json:"event_types" gorm:"serializer:json;type:jsonb;not null"`
+	Secret      string         `json:"-" gorm:"not null;size:100"`
+	IsActive    bool           `json:"is_active" gorm:"not null;default:true"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Subscribes reports whether the endpoint wants events of the given type.
+func (e *WebhookEndpoint) Subscribes(eventType string) bool {
+	for _, t := range e.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event sent to one endpoint, with the outcome of its latest attempt.
+// EventID identifies the event itself, so receivers can drop duplicates; a redelivery keeps the
+// EventID and points at the delivery it repeats.
+type WebhookDelivery struct {
+	ID             int              `json:"id" gorm:"primaryKey;autoIncrement"`
+	EndpointID     int              `json:"endpoint_id" gorm:"not null;index;uniqueIndex:idx_webhook_deliveries_endpoint_event,where:redelivery_of_id IS NULL"`
+	EventID        string           `json:"event_id" gorm:"not null;size:100;uniqueIndex:idx_webhook_deliveries_endpoint_event,where:redelivery_of_id IS NULL"`
+	EventType      string           `json:"event_type" gorm:"not null;size:50"`
+	Payload        json.RawMessage  `json:"payload" gorm:"type:jsonb;not null"`
+	RedeliveryOfID *int             `json:"redelivery_of_id,omitempty"`
+	Status         string           `json:"status" gorm:"not null;size:20;default:pending;index:idx_webhook_deliveries_due,priority:1"`
+	Attempts       int              `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time        `json:"next_attempt_at" gorm:"not null;index:idx_webhook_deliveries_due,priority:2"`
+	ResponseStatus int              `json:"response_status,omitempty"`
+	ResponseBody   string           `json:"response_body,omitempty" gorm:"type:text"`
+	LastError      string           `json:"last_error,omitempty" gorm:"type:text"`
+	DurationMs     int64            `json:"duration_ms,omitempty"`
+	DeliveredAt    *time.Time       `json:"delivered_at,omitempty"`
+	Endpoint       *WebhookEndpoint `json:"-" gorm:"foreignKey:EndpointID"`
+	CreatedAt      time.Time        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -4,6 +4,7 @@ type RegisterRequest struct {
 	Username string `json:"username" validate:"required,min=3,max=25"`
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=8"`
+	Locale   string `json:"locale"`
 }
 
 type UserResponse struct {
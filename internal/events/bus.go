@@ -0,0 +1,86 @@
+package events
+
+import (
+	"log"
+	"runtime/debug"
+	"sync"
+)
+
+// Mode selects how a subscriber receives events.
+type Mode int
+
+const (
+	// Sync runs the handler inside Publish, so its effects are visible when the publisher returns.
+	Sync Mode = iota
+	// Async runs the handler on its own goroutine; use it for slow or external work.
+	Async
+)
+
+// Handler reacts to one event. Errors are logged by the bus and never reach the publisher.
+type Handler func(event Event) error
+
+type EventBus interface {
+	Publish(event Event)
+	Subscribe(eventName, subscriber string, mode Mode, handler Handler)
+}
+
+type subscription struct {
+	name    string
+	mode    Mode
+	handler Handler
+}
+
+// Bus is the in-process EventBus. A failing or panicking subscriber affects neither the
+// publisher nor the other subscribers of the same event.
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions map[string][]subscription
+	inFlight      sync.WaitGroup
+}
+
+func NewBus() *Bus {
+	return &Bus{subscriptions: make(map[string][]subscription)}
+}
+
+func (b *Bus) Subscribe(eventName, subscriber string, mode Mode, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions[eventName] = append(b.subscriptions[eventName], subscription{name: subscriber, mode: mode, handler: handler})
+}
+
+// Publish delivers the event to its subscribers in registration order. Sync subscribers have
+// finished when Publish returns; async ones may still be running.
+func (b *Bus) Publish(event Event) {
+	b.mu.RLock()
+	subscriptions := b.subscriptions[event.Name()]
+	b.mu.RUnlock()
+
+	for _, sub := range subscriptions {
+		if sub.mode == Async {
+			b.inFlight.Add(1)
+			go func(sub subscription) {
+				defer b.inFlight.Done()
+				b.deliver(sub, event)
+			}(sub)
+			continue
+		}
+		b.deliver(sub, event)
+	}
+}
+
+// Close waits for async deliveries that are still running.
+func (b *Bus) Close() {
+	b.inFlight.Wait()
+}
+
+func (b *Bus) deliver(sub subscription, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Event subscriber %s panicked on %s: %v\n%s", sub.name, event.Name(), r, debug.Stack())
+		}
+	}()
+
+	if err := sub.handler(event); err != nil {
+		log.Printf("Event subscriber %s failed on %s: %v", sub.name, event.Name(), err)
+	}
+}
@@ -0,0 +1,41 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+var decoders = map[string]func(payload []byte) (Event, error){
+	OrderCreatedEvent:       decode[OrderCreated],
+	OrderStatusChangedEvent: decode[OrderStatusChanged],
+	ProductCreatedEvent:     decode[ProductCreated],
+	ProductUpdatedEvent:     decode[ProductUpdated],
+	MessageSentEvent:        decode[MessageSent],
+	UserRegisteredEvent:     decode[UserRegistered],
+	UserEmailChangedEvent:   decode[UserEmailChanged],
+	EmailQueuedEvent:        decode[EmailQueued],
+	ProductModeratedEvent:   decode[ProductModerated],
+	MessageModeratedEvent:   decode[MessageModerated],
+}
+
+// Encode serializes an event for the outbox.
+func Encode(event Event) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+// Decode restores an event stored by Encode.
+func Decode(name string, payload []byte) (Event, error) {
+	decoder, ok := decoders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %q", name)
+	}
+	return decoder(payload)
+}
+
+func decode[E Event](payload []byte) (Event, error) {
+	var event E
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
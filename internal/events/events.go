@@ -0,0 +1,99 @@
+// Package events carries marketplace domain events from the services that cause them to the
+// subscribers registered in the container.
+package events
+
+import (
+	"MicroShopik/internal/domain"
+	"MicroShopik/internal/mailer"
+)
+
+const (
+	OrderCreatedEvent       = "order.created"
+	OrderStatusChangedEvent = "order.status_changed"
+	ProductCreatedEvent     = "product.created"
+	ProductUpdatedEvent     = "product.updated"
+	MessageSentEvent        = "message.sent"
+	UserRegisteredEvent     = "user.registered"
+	UserEmailChangedEvent   = "user.email_changed"
+	EmailQueuedEvent        = "email.queued"
+	ProductModeratedEvent   = "product.moderated"
+	MessageModeratedEvent   = "message.moderated"
+)
+
+// Event is a fact that already happened; subscribers must not try to veto it.
+type Event interface {
+	Name() string
+}
+
+type OrderCreated struct {
+	Order *domain.Order
+}
+
+func (OrderCreated) Name() string { return OrderCreatedEvent }
+
+type OrderStatusChanged struct {
+	Order     *domain.Order
+	OldStatus string
+	NewStatus string
+}
+
+func (OrderStatusChanged) Name() string { return OrderStatusChangedEvent }
+
+type ProductCreated struct {
+	Product *domain.Product
+}
+
+func (ProductCreated) Name() string { return ProductCreatedEvent }
+
+// ProductUpdated carries the product as saved and the state it had before the update.
+type ProductUpdated struct {
+	Product  *domain.Product
+	Previous *domain.Product
+}
+
+func (ProductUpdated) Name() string { return ProductUpdatedEvent }
+
+// MessageSent is published for every stored message, system messages included.
+type MessageSent struct {
+	Message *domain.Message
+}
+
+func (MessageSent) Name() string { return MessageSentEvent }
+
+type UserRegistered struct {
+	User *domain.User
+}
+
+func (UserRegistered) Name() string { return UserRegisteredEvent }
+
+// UserEmailChanged is published after a user's email address changed and lost its verification.
+type UserEmailChanged struct {
+	User *domain.User
+}
+
+func (UserEmailChanged) Name() string { return UserEmailChangedEvent }
+
+// ProductModerated is published when a moderator approves or rejects a product.
+type ProductModerated struct {
+	Product *domain.Product
+	Action  string
+	Comment string
+}
+
+func (ProductModerated) Name() string { return ProductModeratedEvent }
+
+// MessageModerated is published when a moderator resolves the reports of a message.
+type MessageModerated struct {
+	Message    *domain.Message
+	Resolution string
+	Comment    string
+}
+
+func (MessageModerated) Name() string { return MessageModeratedEvent }
+
+// EmailQueued carries a rendered email; the outbox relay sends it, with retries.
+type EmailQueued struct {
+	Email mailer.Message
+}
+
+func (EmailQueued) Name() string { return EmailQueuedEvent }
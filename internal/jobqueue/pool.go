@@ -0,0 +1,230 @@
+package jobqueue
+
+import (
+	"MicroShopik/internal/domain"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+const (
+	// heartbeatInterval is how often a pool tells the database its jobs are still running.
+	heartbeatInterval = 30 * time.Second
+	// staleAfter is how long a running job may go without a heartbeat before it is presumed
+	// lost with its worker and queued again.
+	staleAfter = 3 * heartbeatInterval
+	// stopTimeout is how long Stop waits for running jobs before cancelling their context.
+	stopTimeout = 30 * time.Second
+
+	baseBackoff = 30 * time.Second
+	maxBackoff  = time.Hour
+)
+
+// Pool runs queued jobs with a fixed number of workers. Several pools, in the server or in
+// separate worker processes, can share one queue.
+type Pool struct {
+	queue        *Queue
+	size         int
+	pollInterval time.Duration
+	workerID     string
+
+	mu      sync.Mutex
+	running map[int]struct{}
+
+	ctx        context.Context
+	cancel     context.CancelFunc
+	runCtx     context.Context
+	cancelRuns context.CancelFunc
+	workers    sync.WaitGroup
+	done       chan struct{}
+}
+
+func NewPool(queue *Queue, size int, pollInterval time.Duration) *Pool {
+	ctx, cancel := context.WithCancel(context.Background())
+	runCtx, cancelRuns := context.WithCancel(context.Background())
+	host, _ := os.Hostname()
+
+	return &Pool{
+		queue:        queue,
+		size:         size,
+		pollInterval: pollInterval,
+		workerID:     fmt.Sprintf("%s:%d", host, os.Getpid()),
+		running:      make(map[int]struct{}),
+		ctx:          ctx,
+		cancel:       cancel,
+		runCtx:       runCtx,
+		cancelRuns:   cancelRuns,
+		done:         make(chan struct{}),
+	}
+}
+
+func (p *Pool) Start() {
+	log.Printf("Starting job worker pool %s with %d workers for %v", p.workerID, p.size, p.queue.Types())
+
+	for i := 0; i < p.size; i++ {
+		p.workers.Add(1)
+		go p.work()
+	}
+	go p.maintain()
+}
+
+// Stop stops claiming jobs and waits for running ones to finish. Jobs still running after
+// stopTimeout have their context cancelled and are left behind; without heartbeats they are
+// queued again once they go stale.
+func (p *Pool) Stop() {
+	log.Println("Stopping job worker pool...")
+	p.cancel()
+
+	finished := make(chan struct{})
+	go func() {
+		p.workers.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		log.Println("Job worker pool stopped")
+	case <-time.After(stopTimeout):
+		log.Printf("Job worker pool stopped without waiting for jobs still running after %v", stopTimeout)
+	}
+	p.cancelRuns()
+	<-p.done
+}
+
+func (p *Pool) work() {
+	defer p.workers.Done()
+
+	for p.ctx.Err() == nil {
+		jobs, err := p.queue.jobRepo.Claim(p.workerID, p.queue.Types(), 1)
+		if err != nil {
+			log.Printf("Failed to claim jobs: %v", err)
+		}
+		if len(jobs) == 0 {
+			select {
+			case <-time.After(p.pollInterval):
+			case <-p.ctx.Done():
+			}
+			continue
+		}
+
+		for _, job := range jobs {
+			p.run(job)
+		}
+	}
+}
+
+// maintain sends heartbeats for the pool's running jobs and queues again the jobs of workers
+// that stopped sending theirs.
+func (p *Pool) maintain() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.queue.jobRepo.Heartbeat(p.runningIDs(), time.Now()); err != nil {
+				log.Printf("Failed to send job heartbeats: %v", err)
+			}
+			requeued, dead, err := p.queue.jobRepo.RequeueStale(time.Now().Add(-staleAfter))
+			if err != nil {
+				log.Printf("Failed to requeue stale jobs: %v", err)
+			} else if requeued > 0 {
+				log.Printf("Requeued %d jobs whose worker stopped responding", requeued)
+			}
+			for _, job := range dead {
+				log.Printf("Job %d (%s) is dead after its worker stopped responding", job.ID, job.Type)
+				p.queue.died(job)
+			}
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *Pool) run(job *domain.QueuedJob) {
+	p.mu.Lock()
+	p.running[job.ID] = struct{}{}
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		delete(p.running, job.ID)
+		p.mu.Unlock()
+	}()
+
+	started := time.Now()
+	err := p.call(job)
+
+	if err == nil {
+		now := time.Now()
+		job.Status = domain.QueuedJobStatusSucceeded
+		job.FinishedAt = &now
+		job.LastError = ""
+	} else {
+		job.LastError = err.Error()
+		if job.Attempts >= job.MaxAttempts {
+			now := time.Now()
+			job.Status = domain.QueuedJobStatusDead
+			job.FinishedAt = &now
+			log.Printf("Job %d (%s) is dead after %d attempts: %v", job.ID, job.Type, job.Attempts, err)
+		} else {
+			job.Status = domain.QueuedJobStatusQueued
+			job.RunAt = time.Now().Add(backoff(job.Attempts))
+			log.Printf("Job %d (%s) failed after %v, retrying at %s: %v", job.ID, job.Type, time.Since(started), job.RunAt.Format(time.RFC3339), err)
+		}
+	}
+
+	released, err := p.queue.jobRepo.Release(job)
+	if err != nil {
+		log.Printf("Failed to record the result of job %d (%s): %v", job.ID, job.Type, err)
+	} else if !released {
+		log.Printf("Job %d (%s) was taken over by another worker before it finished here", job.ID, job.Type)
+	} else if job.Status == domain.QueuedJobStatusDead {
+		p.queue.died(job)
+	}
+}
+
+// call runs the job's handler, turning a panic into an error so the job is retried like any
+// other failure.
+func (p *Pool) call(job *domain.QueuedJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Job %d (%s) panicked: %v\n%s", job.ID, job.Type, r, debug.Stack())
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	handler, ok := p.queue.handler(job.Type)
+	if !ok {
+		return fmt.Errorf("unknown job type %q", job.Type)
+	}
+	return handler(p.runCtx, job)
+}
+
+func (p *Pool) runningIDs() []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids := make([]int, 0, len(p.running))
+	for id := range p.running {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// backoff doubles the delay after every failed attempt: 30s, 1m, 2m, ... up to an hour.
+func backoff(attempts int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
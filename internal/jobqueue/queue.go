@@ -0,0 +1,216 @@
+package jobqueue
+
+import (
+	"MicroShopik/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Handler runs one job. Returning an error schedules a retry until the job runs out of attempts,
+// so handlers must be safe to run more than once. Its context is cancelled when the worker pool
+// stops waiting for it on shutdown.
+type Handler func(ctx context.Context, job *domain.QueuedJob) error
+
+// Typed adapts fn into a Handler that decodes the job payload into T.
+func Typed[T any](fn func(ctx context.Context, payload T) error) Handler {
+	return func(ctx context.Context, job *domain.QueuedJob) error {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid payload for job type %s: %w", job.Type, err)
+		}
+		return fn(ctx, payload)
+	}
+}
+
+// DeadHandler is told about a job that will not run again because it ran out of attempts or was
+// cancelled, so whatever tracks the job's work can be marked failed. Its job has the final
+// status and last error.
+type DeadHandler func(job *domain.QueuedJob)
+
+// TypedDead adapts fn into a DeadHandler that decodes the job payload into T.
+func TypedDead[T any](fn func(payload T, job *domain.QueuedJob)) DeadHandler {
+	return func(job *domain.QueuedJob) {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			log.Printf("Invalid payload for dead job %d (%s): %v", job.ID, job.Type, err)
+			return
+		}
+		fn(payload, job)
+	}
+}
+
+// Options tune a single job. Zero values mean priority 0, run now and the queue's default
+// attempt budget.
+type Options struct {
+	// Priority orders due jobs; higher runs first.
+	Priority    int
+	RunAt       time.Time
+	MaxAttempts int
+	// UniqueKey, when set, makes Enqueue return the unfinished job with the same key instead of
+	// adding another one.
+	UniqueKey string
+}
+
+// Queue stores jobs in Postgres and holds the handlers of the job types this process knows.
+// Register every handler before enqueueing or starting a worker pool.
+type Queue struct {
+	jobRepo            domain.QueuedJobRepository
+	defaultMaxAttempts int
+
+	mu           sync.RWMutex
+	handlers     map[string]Handler
+	deadHandlers map[string]DeadHandler
+}
+
+func NewQueue(jobRepo domain.QueuedJobRepository, defaultMaxAttempts int) *Queue {
+	return &Queue{
+		jobRepo:            jobRepo,
+		defaultMaxAttempts: defaultMaxAttempts,
+		handlers:           make(map[string]Handler),
+		deadHandlers:       make(map[string]DeadHandler),
+	}
+}
+
+// Handle registers the handler of a job type.
+func (q *Queue) Handle(jobType string, handler Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.handlers[jobType]; ok {
+		panic(fmt.Sprintf("jobqueue: job type %q registered twice", jobType))
+	}
+	q.handlers[jobType] = handler
+}
+
+// HandleDead registers what to do when a job of the type dies. The job type needs a handler.
+func (q *Queue) HandleDead(jobType string, handler DeadHandler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.handlers[jobType]; !ok {
+		panic(fmt.Sprintf("jobqueue: dead handler for unknown job type %q", jobType))
+	}
+	q.deadHandlers[jobType] = handler
+}
+
+// Enqueue stores a job with payload encoded as JSON. When the unique key is taken by an
+// unfinished job, that job is returned and created is false.
+func (q *Queue) Enqueue(jobType string, payload interface{}, opts Options) (job *domain.QueuedJob, created bool, err error) {
+	if _, ok := q.handler(jobType); !ok {
+		return nil, false, fmt.Errorf("unknown job type %q", jobType)
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	job = &domain.QueuedJob{
+		Type:        jobType,
+		Payload:     encoded,
+		Status:      domain.QueuedJobStatusQueued,
+		Priority:    opts.Priority,
+		RunAt:       opts.RunAt,
+		MaxAttempts: opts.MaxAttempts,
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = q.defaultMaxAttempts
+	}
+	if opts.UniqueKey != "" {
+		key := opts.UniqueKey
+		job.UniqueKey = &key
+	}
+
+	created, err = q.jobRepo.Create(job)
+	if err != nil {
+		return nil, false, err
+	}
+	if !created {
+		existing, err := q.jobRepo.GetActiveByUniqueKey(opts.UniqueKey)
+		if err != nil {
+			return nil, false, err
+		}
+		return existing, false, nil
+	}
+	return job, true, nil
+}
+
+func (q *Queue) GetJob(id int) (*domain.QueuedJob, error) {
+	return q.jobRepo.GetByID(id)
+}
+
+// GetJobs returns jobs newest first; empty filters match all.
+func (q *Queue) GetJobs(status, jobType string, limit, offset int) ([]*domain.QueuedJob, int64, error) {
+	return q.jobRepo.GetByStatus(status, jobType, limit, offset)
+}
+
+func (q *Queue) CountByStatus() (map[string]int64, error) {
+	return q.jobRepo.CountByStatus()
+}
+
+// Retry runs a dead or cancelled job again with a fresh attempt budget.
+func (q *Queue) Retry(id int) error {
+	return q.jobRepo.Requeue(id)
+}
+
+// Cancel drops a job that has not started yet.
+func (q *Queue) Cancel(id int) error {
+	if err := q.jobRepo.Cancel(id); err != nil {
+		return err
+	}
+
+	job, err := q.jobRepo.GetByID(id)
+	if err != nil {
+		log.Printf("Failed to load cancelled job %d: %v", id, err)
+		return nil
+	}
+	q.died(job)
+	return nil
+}
+
+// Types lists the registered job types.
+func (q *Queue) Types() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	types := make([]string, 0, len(q.handlers))
+	for jobType := range q.handlers {
+		types = append(types, jobType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// died runs the dead handler of the job's type, if any. A panic is logged, since there is no
+// attempt left to retry.
+func (q *Queue) died(job *domain.QueuedJob) {
+	q.mu.RLock()
+	handler, ok := q.deadHandlers[job.Type]
+	q.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Dead handler of job %d (%s) panicked: %v", job.ID, job.Type, r)
+		}
+	}()
+	handler(job)
+}
+
+func (q *Queue) handler(jobType string) (Handler, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	handler, ok := q.handlers[jobType]
+	return handler, ok
+}
@@ -0,0 +1,16 @@
+// Package mailer renders transactional email from templates and hands it to a transport.
+package mailer
+
+// Message is a rendered email with a plain-text and an HTML body.
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+// Mailer delivers a message. Implementations send synchronously; callers that must not block
+// queue the message instead.
+type Mailer interface {
+	Send(msg *Message) error
+}
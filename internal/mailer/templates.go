@@ -0,0 +1,111 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Templates live in templates/<name>.<locale>.txt.tmpl and .html.tmpl. The text template
+// defines a "subject" block; the HTML one defines "content", which layout.html.tmpl wraps.
+//
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+const DefaultLocale = "en"
+
+var supportedLocales = map[string]bool{"en": true, "ru": true}
+
+// NormalizeLocale maps values like "ru-RU" to a supported locale, falling back to DefaultLocale.
+func NormalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	if supportedLocales[locale] {
+		return locale
+	}
+	return DefaultLocale
+}
+
+type Renderer struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+func NewRenderer() (*Renderer, error) {
+	r := &Renderer{
+		text: make(map[string]*texttemplate.Template),
+		html: make(map[string]*htmltemplate.Template),
+	}
+
+	textFiles, err := fs.Glob(templateFS, "templates/*.txt.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range textFiles {
+		t, err := texttemplate.ParseFS(templateFS, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		if t.Lookup("subject") == nil {
+			return nil, fmt.Errorf("%s does not define a subject", file)
+		}
+		r.text[strings.TrimSuffix(path.Base(file), ".txt.tmpl")] = t
+	}
+
+	htmlFiles, err := fs.Glob(templateFS, "templates/*.*.html.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range htmlFiles {
+		t, err := htmltemplate.ParseFS(templateFS, "templates/layout.html.tmpl", file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		r.html[strings.TrimSuffix(path.Base(file), ".html.tmpl")] = t
+	}
+
+	return r, nil
+}
+
+// Render produces the subject and both bodies of a template in the recipient's locale. The
+// caller fills in Message.To.
+func (r *Renderer) Render(name, locale string, data interface{}) (*Message, error) {
+	key := name + "." + NormalizeLocale(locale)
+	if _, ok := r.text[key]; !ok {
+		key = name + "." + DefaultLocale
+	}
+	textTemplate, ok := r.text[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, text bytes.Buffer
+	if err := textTemplate.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := textTemplate.Execute(&text, data); err != nil {
+		return nil, err
+	}
+
+	msg := &Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+	}
+
+	if htmlTemplate, ok := r.html[key]; ok {
+		var html bytes.Buffer
+		if err := htmlTemplate.ExecuteTemplate(&html, "layout.html.tmpl", data); err != nil {
+			return nil, err
+		}
+		msg.HTML = html.String()
+	}
+
+	return msg, nil
+}
@@ -2,16 +2,33 @@ package middleware
 
 import (
 	"MicroShopik/internal/domain"
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
-func JWTMiddleware(secret string) echo.MiddlewareFunc {
+// SessionChecker reports whether the session an access token was issued for has ended.
+type SessionChecker interface {
+	IsRevoked(userID int, sessionID string) (bool, error)
+}
+
+// JWTMiddleware accepts valid access tokens whose session is still live, so logging out or
+// revoking a session locks its access tokens out too.
+func JWTMiddleware(secret string, sessions SessionChecker) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			authHeader := c.Request().Header.Get("Authorization")
+
+			// Browsers can't set headers on a WebSocket handshake, so it may carry the token in the query instead.
+			if authHeader == "" && isWebSocketUpgrade(c.Request()) {
+				if token := c.QueryParam("token"); token != "" {
+					authHeader = "Bearer " + token
+				}
+			}
+
 			if authHeader == "" {
 				return c.JSON(http.StatusUnauthorized,
 					map[string]string{"error": "missing token"})
@@ -22,22 +39,47 @@ func JWTMiddleware(secret string) echo.MiddlewareFunc {
 					map[string]string{"error": "invalid authorization header format"})
 			}
 
-			tokenString := authHeader[7:]
-
-			token, err := jwt.ParseWithClaims(tokenString, &domain.JWTClaims{},
-				func(t *jwt.Token) (interface{}, error) {
-					return []byte(secret), nil
-				})
-
-			if err != nil || !token.Valid {
+			claims, err := ParseToken(authHeader[7:], secret)
+			if err != nil {
 				return c.JSON(http.StatusUnauthorized,
 					map[string]string{"error": "invalid token"})
 			}
 
-			claims := token.Claims.(*domain.JWTClaims)
+			if claims.SessionID != "" {
+				revoked, err := sessions.IsRevoked(claims.UserID, claims.SessionID)
+				if err != nil {
+					return c.JSON(http.StatusInternalServerError,
+						map[string]string{"error": "failed to check session"})
+				}
+				if revoked {
+					return c.JSON(http.StatusUnauthorized,
+						map[string]string{"error": "session revoked"})
+				}
+			}
+
 			c.Set("user_id", claims.UserID)
 			c.Set("user", claims)
 			return next(c)
 		}
 	}
 }
+
+// ParseToken validates a signed access token and returns its claims.
+func ParseToken(tokenString, secret string) (*domain.JWTClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &domain.JWTClaims{},
+		func(t *jwt.Token) (interface{}, error) {
+			return []byte(secret), nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	return token.Claims.(*domain.JWTClaims), nil
+}
+
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimit allows each client IP at most limit requests per window. Counters live in
+// memory, so the limit applies per server instance.
+func RateLimit(limit int, window time.Duration) echo.MiddlewareFunc {
+	type counter struct {
+		count   int
+		resetAt time.Time
+	}
+
+	var mu sync.Mutex
+	counters := make(map[string]*counter)
+	lastSweep := time.Now()
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			now := time.Now()
+			ip := c.RealIP()
+
+			mu.Lock()
+			if now.Sub(lastSweep) > window {
+				for key, ctr := range counters {
+					if now.After(ctr.resetAt) {
+						delete(counters, key)
+					}
+				}
+				lastSweep = now
+			}
+
+			ctr, ok := counters[ip]
+			if !ok || now.After(ctr.resetAt) {
+				ctr = &counter{resetAt: now.Add(window)}
+				counters[ip] = ctr
+			}
+			ctr.count++
+			allowed := ctr.count <= limit
+			retryAfter := ctr.resetAt.Sub(now)
+			mu.Unlock()
+
+			if !allowed {
+				c.Response().Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+				return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "too many requests"})
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package realtime
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = 30 * time.Second
+	maxFrameSize   = 4096
+	sendBufferSize = 256
+
+	// typingThrottle is how often a client may re-announce typing in the same conversation.
+	// Clients should treat a typing indicator as stale after a few seconds without a refresh.
+	typingThrottle = 2 * time.Second
+)
+
+// Client is one WebSocket connection of a user. A user may have several (tabs, devices).
+type Client struct {
+	hub    *Hub
+	conn   *websocket.Conn
+	userID int
+	// sessionID is the session of the access token the connection was opened with.
+	sessionID string
+	send      chan []byte
+
+	// closeCode is set by the hub before send is closed and tells writePump how to say goodbye.
+	closeCode int
+
+	// lastTyping throttles typing notices per conversation. Only readPump touches it.
+	lastTyping map[int]time.Time
+}
+
+func newClient(hub *Hub, conn *websocket.Conn, userID int, sessionID string) *Client {
+	return &Client{
+		hub:       hub,
+		conn:      conn,
+		userID:    userID,
+		sessionID: sessionID,
+		send:      make(chan []byte, sendBufferSize),
+
+		lastTyping: make(map[int]time.Time),
+	}
+}
+
+// readPump keeps the read deadline moving on pongs and answers application pings.
+// It is the only reader of the connection.
+func (c *Client) readPump() {
+	defer func() {
+		c.hub.unregister(c, websocket.CloseNormalClosure)
+		c.conn.Close()
+		c.hub.touchLastSeen(c.userID)
+	}()
+
+	c.conn.SetReadLimit(maxFrameSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var frame clientFrame
+		if json.Unmarshal(data, &frame) != nil {
+			continue
+		}
+		switch frame.Type {
+		case "ping":
+			c.hub.sendToClient(c, Event{Type: EventPong})
+		case "typing":
+			if time.Since(c.lastTyping[frame.ConversationID]) < typingThrottle {
+				continue
+			}
+			c.lastTyping[frame.ConversationID] = time.Now()
+			c.hub.typing(c, frame.ConversationID, true)
+		case "typing.stop":
+			delete(c.lastTyping, frame.ConversationID)
+			c.hub.typing(c, frame.ConversationID, false)
+		}
+	}
+}
+
+// writePump drains the send buffer and sends heartbeats. It is the only writer of the connection.
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case data, ok := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				_ = c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(c.closeCode, ""))
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package realtime
+
+import "MicroShopik/internal/domain"
+
+const (
+	EventMessageCreated    = "message.created"
+	EventMessageUpdated    = "message.updated"
+	EventParticipantJoined = "participant.joined"
+	EventParticipantLeft   = "participant.left"
+	EventConversationRead  = "conversation.read"
+	EventTypingStarted     = "typing.started"
+	EventTypingStopped     = "typing.stopped"
+	EventResumeComplete    = "resume.complete"
+	EventResyncRequired    = "resync.required"
+	EventPong              = "pong"
+
+	EventNotificationCreated = "notification.created"
+)
+
+// Event is the JSON frame pushed to WebSocket clients.
+type Event struct {
+	Type           string          `json:"type"`
+	ConversationID int             `json:"conversation_id,omitempty"`
+	UserID         int             `json:"user_id,omitempty"`
+	Message        *domain.Message `json:"message,omitempty"`
+	LastMessageID  int             `json:"last_message_id,omitempty"`
+
+	Notification *domain.Notification `json:"notification,omitempty"`
+}
+
+// clientFrame is what clients may send: application-level pings and typing notices.
+type clientFrame struct {
+	Type           string `json:"type"`
+	ConversationID int    `json:"conversation_id"`
+}
@@ -0,0 +1,325 @@
+package realtime
+
+import (
+	"MicroShopik/internal/domain"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// maxResumeMessages caps how many missed messages are replayed on reconnect.
+// Clients that missed more get a resync.required event and should reload over REST.
+const maxResumeMessages = 200
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Authentication is a bearer token rather than a cookie, so any origin may connect,
+	// same as the CORS policy for the REST API.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// Hub fans conversation events out to the connected clients of every participant.
+type Hub struct {
+	participantRepo domain.ParticipantRepository
+	messageRepo     domain.MessageRepository
+	userRepo        domain.UserRepository
+
+	mu      sync.RWMutex
+	clients map[int]map[*Client]struct{}
+	closed  bool
+}
+
+func NewHub(pRepo domain.ParticipantRepository, mRepo domain.MessageRepository, uRepo domain.UserRepository) *Hub {
+	return &Hub{
+		participantRepo: pRepo,
+		messageRepo:     mRepo,
+		userRepo:        uRepo,
+		clients:         make(map[int]map[*Client]struct{}),
+	}
+}
+
+// ServeWS upgrades the request and starts streaming events to the user. When since is set,
+// messages newer than that ID are replayed first so a reconnecting client doesn't miss anything.
+func (h *Hub) ServeWS(w http.ResponseWriter, r *http.Request, userID int, sessionID string, since int) error {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return err
+	}
+
+	client := newClient(h, conn, userID, sessionID)
+	if !h.register(client) {
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+		conn.Close()
+		return errors.New("hub is closed")
+	}
+
+	go client.writePump()
+	go client.readPump()
+	h.touchLastSeen(userID)
+
+	// Register before replaying so nothing published in between is lost; clients de-duplicate by message ID.
+	if since > 0 {
+		h.resume(client, since)
+	}
+	return nil
+}
+
+func (h *Hub) resume(c *Client, since int) {
+	messages, err := h.messageRepo.GetSinceForUser(c.userID, since, maxResumeMessages+1)
+	if err != nil {
+		log.Printf("Realtime: failed to load missed messages for user %d: %v", c.userID, err)
+		h.sendToClient(c, Event{Type: EventResyncRequired})
+		return
+	}
+	if len(messages) > maxResumeMessages {
+		h.sendToClient(c, Event{Type: EventResyncRequired})
+		return
+	}
+
+	lastID := since
+	for _, message := range messages {
+		h.sendToClient(c, Event{Type: EventMessageCreated, ConversationID: message.ConversationID, Message: message})
+		lastID = message.ID
+	}
+	h.sendToClient(c, Event{Type: EventResumeComplete, LastMessageID: lastID})
+}
+
+// IsOnline reports whether the user has at least one open connection.
+func (h *Hub) IsOnline(userID int) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.clients[userID]) > 0
+}
+
+func (h *Hub) touchLastSeen(userID int) {
+	if err := h.userRepo.UpdateLastSeen(userID, time.Now()); err != nil {
+		log.Printf("Realtime: failed to update last seen of user %d: %v", userID, err)
+	}
+}
+
+// typing relays a typing notice to the other participants. Notices are never stored.
+func (h *Hub) typing(c *Client, conversationID int, typing bool) {
+	if conversationID <= 0 {
+		return
+	}
+	isParticipant, err := h.participantRepo.IsParticipant(conversationID, c.userID)
+	if err != nil || !isParticipant {
+		return
+	}
+
+	eventType := EventTypingStopped
+	if typing {
+		eventType = EventTypingStarted
+	}
+	h.publishExcept(conversationID, c.userID, Event{
+		Type:           eventType,
+		ConversationID: conversationID,
+		UserID:         c.userID,
+	})
+}
+
+func (h *Hub) MessageCreated(message *domain.Message) {
+	h.publish(message.ConversationID, nil, Event{
+		Type:           EventMessageCreated,
+		ConversationID: message.ConversationID,
+		Message:        message,
+	})
+}
+
+// MessageUpdated covers edits and deletions; a deleted message arrives as a tombstone.
+func (h *Hub) MessageUpdated(message *domain.Message) {
+	h.publish(message.ConversationID, nil, Event{
+		Type:           EventMessageUpdated,
+		ConversationID: message.ConversationID,
+		Message:        message,
+	})
+}
+
+func (h *Hub) ParticipantJoined(conversationID, userID int) {
+	h.publish(conversationID, nil, Event{
+		Type:           EventParticipantJoined,
+		ConversationID: conversationID,
+		UserID:         userID,
+	})
+}
+
+// ParticipantLeft is published after the participant is removed, so the user who left is told explicitly.
+func (h *Hub) ParticipantLeft(conversationID, userID int) {
+	h.publish(conversationID, []int{userID}, Event{
+		Type:           EventParticipantLeft,
+		ConversationID: conversationID,
+		UserID:         userID,
+	})
+}
+
+// ConversationRead lets the other participants show a read receipt, and the reader's other tabs clear their counters.
+func (h *Hub) ConversationRead(conversationID, userID, messageID int) {
+	h.publish(conversationID, nil, Event{
+		Type:           EventConversationRead,
+		ConversationID: conversationID,
+		UserID:         userID,
+		LastMessageID:  messageID,
+	})
+}
+
+// NotificationCreated goes to the recipient only; notifications are not tied to a conversation.
+func (h *Hub) NotificationCreated(notification *domain.Notification) {
+	h.sendToUsers([]int{notification.UserID}, Event{
+		Type:         EventNotificationCreated,
+		Notification: notification,
+	})
+}
+
+// SessionsRevoked disconnects the clients opened with the session, or every client of the user
+// when sessionID is empty.
+func (h *Hub) SessionsRevoked(userID int, sessionID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for client := range h.clients[userID] {
+		if sessionID == "" || client.sessionID == sessionID {
+			h.removeLocked(client, websocket.ClosePolicyViolation)
+		}
+	}
+}
+
+// Close disconnects every client. New connections are refused afterwards.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, clients := range h.clients {
+		for client := range clients {
+			h.removeLocked(client, websocket.CloseGoingAway)
+		}
+	}
+}
+
+func (h *Hub) publish(conversationID int, extraUserIDs []int, event Event) {
+	h.deliver(conversationID, extraUserIDs, 0, event)
+}
+
+// publishExcept skips every connection of exceptUserID, so the sender's other tabs don't echo their own typing.
+func (h *Hub) publishExcept(conversationID, exceptUserID int, event Event) {
+	h.deliver(conversationID, nil, exceptUserID, event)
+}
+
+func (h *Hub) deliver(conversationID int, extraUserIDs []int, exceptUserID int, event Event) {
+	participants, err := h.participantRepo.GetByConversationID(conversationID)
+	if err != nil {
+		log.Printf("Realtime: failed to load participants of conversation %d: %v", conversationID, err)
+		return
+	}
+
+	userIDs := extraUserIDs
+	for _, participant := range participants {
+		if participant.UserID != exceptUserID {
+			userIDs = append(userIDs, participant.UserID)
+		}
+	}
+
+	h.sendToUsers(userIDs, event)
+}
+
+func (h *Hub) sendToUsers(userIDs []int, event Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Realtime: failed to encode %s event: %v", event.Type, err)
+		return
+	}
+
+	var slow []*Client
+	h.mu.RLock()
+	for _, userID := range userIDs {
+		for client := range h.clients[userID] {
+			if !trySend(client, data) {
+				slow = append(slow, client)
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	h.dropSlow(slow)
+}
+
+func (h *Hub) sendToClient(c *Client, event Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Realtime: failed to encode %s event: %v", event.Type, err)
+		return
+	}
+
+	h.mu.RLock()
+	_, connected := h.clients[c.userID][c]
+	ok := !connected || trySend(c, data)
+	h.mu.RUnlock()
+
+	if !ok {
+		h.dropSlow([]*Client{c})
+	}
+}
+
+// trySend never blocks: a client whose buffer is full is too slow to keep up. Callers hold the read lock.
+func trySend(c *Client, data []byte) bool {
+	select {
+	case c.send <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+// dropSlow disconnects clients that fell behind. They reconnect with since to catch up.
+func (h *Hub) dropSlow(clients []*Client) {
+	for _, client := range clients {
+		log.Printf("Realtime: dropping slow client of user %d", client.userID)
+		h.unregister(client, websocket.CloseTryAgainLater)
+	}
+}
+
+func (h *Hub) register(c *Client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return false
+	}
+	if h.clients[c.userID] == nil {
+		h.clients[c.userID] = make(map[*Client]struct{})
+	}
+	h.clients[c.userID][c] = struct{}{}
+	return true
+}
+
+func (h *Hub) unregister(c *Client, closeCode int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.removeLocked(c, closeCode)
+}
+
+func (h *Hub) removeLocked(c *Client, closeCode int) {
+	clients, ok := h.clients[c.userID]
+	if !ok {
+		return
+	}
+	if _, ok := clients[c]; !ok {
+		return
+	}
+
+	delete(clients, c)
+	if len(clients) == 0 {
+		delete(h.clients, c.userID)
+	}
+
+	c.closeCode = closeCode
+	close(c.send)
+}
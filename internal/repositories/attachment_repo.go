@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) domain.AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) Create(attachment *domain.Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+func (r *attachmentRepository) GetByID(id int) (*domain.Attachment, error) {
+	var attachment domain.Attachment
+	err := r.db.Where("id = ?", id).First(&attachment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attachment not found")
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) GetByMessageID(messageID int) ([]domain.Attachment, error) {
+	var attachments []domain.Attachment
+	err := r.db.Where("message_id = ?", messageID).Order("id ASC").Find(&attachments).Error
+	return attachments, err
+}
+
+// CountAttachable counts the given attachments that the uploader put in this conversation and that no message uses yet.
+func (r *attachmentRepository) CountAttachable(ids []int, conversationID, uploaderID int) (int, error) {
+	var count int64
+	err := r.db.Model(&domain.Attachment{}).
+		Where("id IN ? AND conversation_id = ? AND uploader_id = ? AND message_id IS NULL", ids, conversationID, uploaderID).
+		Count(&count).Error
+	return int(count), err
+}
+
+func (r *attachmentRepository) AttachToMessage(ids []int, messageID int) error {
+	return r.db.Model(&domain.Attachment{}).
+		Where("id IN ? AND message_id IS NULL", ids).
+		Update("message_id", messageID).Error
+}
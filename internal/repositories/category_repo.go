@@ -31,6 +31,18 @@ func (c *categoryRepository) GetCategoryById(id int) (*domain.Category, error) {
 	return &category, err
 }
 
+func (c *categoryRepository) GetBySlug(slug string) (*domain.Category, error) {
+	var category domain.Category
+	err := c.db.Where("slug = ?", slug).First(&category).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("category with slug %q not found", slug)
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
 func (c *categoryRepository) GetByID(id int) (*domain.Category, error) {
 	return c.GetCategoryById(id)
 }
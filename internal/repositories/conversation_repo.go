@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type conversationRepository struct {
@@ -21,7 +22,7 @@ func (r *conversationRepository) Create(conversation *domain.Conversation) error
 
 func (r *conversationRepository) GetByID(id int) (*domain.Conversation, error) {
 	var conversation domain.Conversation
-	err := r.db.Preload("Product").Preload("Participants.User").Preload("Messages.Sender").Where("id = ?", id).First(&conversation).Error
+	err := r.db.Preload("Product").Preload("Participants.User").Where("id = ?", id).First(&conversation).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("conversation not found")
@@ -37,7 +38,6 @@ func (r *conversationRepository) GetByUserID(userID int) ([]*domain.Conversation
 		Where("participants.user_id = ?", userID).
 		Preload("Product").
 		Preload("Participants.User").
-		Preload("Messages.Sender").
 		Find(&conversations).Error
 	if err != nil {
 		return nil, err
@@ -50,7 +50,83 @@ func (r *conversationRepository) GetByProductID(productID int) ([]*domain.Conver
 	err := r.db.Where("product_id = ?", productID).
 		Preload("Product").
 		Preload("Participants.User").
-		Preload("Messages.Sender").
+		Find(&conversations).Error
+	if err != nil {
+		return nil, err
+	}
+	return conversations, nil
+}
+
+func (r *conversationRepository) FindOrCreateInquiry(conversation *domain.Conversation, participantIDs []int) (bool, error) {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	defer tx.Rollback()
+
+	// Concurrent requests race on idx_conversations_product_buyer; the loser re-reads the winner's row.
+	result := tx.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "product_id"}, {Name: "buyer_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "buyer_id IS NOT NULL AND deleted_at IS NULL"}}},
+		DoNothing:   true,
+	}).Create(conversation)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		err := r.db.Where("product_id = ? AND buyer_id = ?", conversation.ProductID, conversation.BuyerID).First(conversation).Error
+		return false, err
+	}
+
+	for _, userID := range participantIDs {
+		if err := r.AddParticipantTx(tx, conversation.ID, userID); err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit().Error
+}
+
+func (r *conversationRepository) GetInquiry(productID, buyerID int) (*domain.Conversation, error) {
+	var conversation domain.Conversation
+	err := r.db.Where("product_id = ? AND buyer_id = ?", productID, buyerID).First(&conversation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+func (r *conversationRepository) GetLatestBetween(userID, otherUserID int) (*domain.Conversation, error) {
+	pairs := r.db.Model(&domain.Participant{}).
+		Select("conversation_id").
+		Group("conversation_id").
+		Having("COUNT(*) = 2 AND COUNT(*) FILTER (WHERE user_id IN ?) = 2", []int{userID, otherUserID})
+
+	var conversation domain.Conversation
+	err := r.db.Where("id IN (?)", pairs).Order("id DESC").First(&conversation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+func (r *conversationRepository) GetByIDs(ids []int) ([]*domain.Conversation, error) {
+	var conversations []*domain.Conversation
+	if len(ids) == 0 {
+		return conversations, nil
+	}
+
+	err := r.db.Where("id IN ?", ids).
+		Preload("Product").
+		Preload("Participants.User").
 		Find(&conversations).Error
 	if err != nil {
 		return nil, err
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"MicroShopik/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type jobRunRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRunRepository(db *gorm.DB) domain.JobRunRepository {
+	return &jobRunRepository{db: db}
+}
+
+func (r *jobRunRepository) Create(run *domain.JobRun) (bool, error) {
+	if run.ScheduledAt == nil {
+		if err := r.db.Create(run).Error; err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "job_name"}, {Name: "scheduled_at"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "scheduled_at IS NOT NULL"}}},
+		DoNothing:   true,
+	}).Create(run)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *jobRunRepository) Update(run *domain.JobRun) error {
+	return r.db.Save(run).Error
+}
+
+func (r *jobRunRepository) GetByJobName(jobName string, limit, offset int) ([]*domain.JobRun, int64, error) {
+	query := r.db.Model(&domain.JobRun{}).Where("job_name = ?", jobName)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var runs []*domain.JobRun
+	err := query.Order("started_at DESC, id DESC").Limit(limit).Offset(offset).Find(&runs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
+
+func (r *jobRunRepository) GetLatest(jobNames []string) (map[string]*domain.JobRun, error) {
+	latest := make(map[string]*domain.JobRun, len(jobNames))
+	if len(jobNames) == 0 {
+		return latest, nil
+	}
+
+	var runs []*domain.JobRun
+	err := r.db.Raw(`SELECT DISTINCT ON (job_name) * FROM job_runs
+		WHERE job_name IN ? ORDER BY job_name, started_at DESC, id DESC`, jobNames).
+		Scan(&runs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, run := range runs {
+		latest[run.JobName] = run
+	}
+	return latest, nil
+}
+
+func (r *jobRunRepository) FailRunning(jobName, reason string, at time.Time) error {
+	return r.db.Model(&domain.JobRun{}).
+		Where("job_name = ? AND status = ?", jobName, domain.JobRunStatusRunning).
+		Updates(map[string]interface{}{
+			"status":      domain.JobRunStatusFailed,
+			"error":       reason,
+			"finished_at": at,
+		}).Error
+}
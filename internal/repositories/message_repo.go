@@ -3,6 +3,7 @@ package repositories
 import (
 	"MicroShopik/internal/domain"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -21,12 +22,12 @@ func (r *messageRepository) Create(message *domain.Message) error {
 	}
 
 	// Reload with associations to return a fully populated entity
-	return r.db.Preload("Sender").Preload("Order").First(message, message.ID).Error
+	return r.db.Preload("Sender").Preload("Order").Preload("Attachments").First(message, message.ID).Error
 }
 
 func (r *messageRepository) GetByID(id int) (*domain.Message, error) {
 	var message domain.Message
-	err := r.db.Preload("Sender").Preload("Order").Where("id = ?", id).First(&message).Error
+	err := r.db.Preload("Sender").Preload("Order").Preload("Attachments").Where("id = ?", id).First(&message).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("message not found")
@@ -36,27 +37,120 @@ func (r *messageRepository) GetByID(id int) (*domain.Message, error) {
 	return &message, nil
 }
 
-func (r *messageRepository) GetByConversationID(conversationID int, limit, offset int) ([]*domain.Message, error) {
+// GetBefore returns up to limit messages with an ID below beforeID, newest first. A beforeID of 0 starts from the newest message.
+func (r *messageRepository) GetBefore(conversationID, beforeID, limit int) ([]*domain.Message, error) {
 	var messages []*domain.Message
-	query := r.db.Preload("Sender").Where("conversation_id = ?", conversationID).Order("created_at DESC")
+	query := r.db.Preload("Sender").Preload("Attachments").Where("conversation_id = ?", conversationID)
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
 
-	if limit > 0 {
-		query = query.Limit(limit)
+	err := query.Order("id DESC").Limit(limit).Find(&messages).Error
+	if err != nil {
+		return nil, err
 	}
-	if offset > 0 {
-		query = query.Offset(offset)
+	return messages, nil
+}
+
+// GetAfter returns up to limit messages with an ID above afterID, oldest first.
+func (r *messageRepository) GetAfter(conversationID, afterID, limit int) ([]*domain.Message, error) {
+	var messages []*domain.Message
+	err := r.db.Preload("Sender").Preload("Attachments").
+		Where("conversation_id = ? AND id > ?", conversationID, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
 	}
+	return messages, nil
+}
 
-	err := query.Find(&messages).Error
+// GetLatestForConversations returns the newest message of each conversation that has one.
+func (r *messageRepository) GetLatestForConversations(conversationIDs []int) ([]*domain.Message, error) {
+	var messages []*domain.Message
+	if len(conversationIDs) == 0 {
+		return messages, nil
+	}
+
+	err := r.db.Raw(`
+		SELECT DISTINCT ON (conversation_id) *
+		FROM messages
+		WHERE conversation_id IN ? AND deleted_at IS NULL
+		ORDER BY conversation_id, id DESC`, conversationIDs).
+		Scan(&messages).Error
 	if err != nil {
 		return nil, err
 	}
 	return messages, nil
 }
 
+// GetOrderIDsForConversations maps each conversation to the latest order posted to it.
+func (r *messageRepository) GetOrderIDsForConversations(conversationIDs []int) (map[int]int, error) {
+	orderIDs := make(map[int]int)
+	if len(conversationIDs) == 0 {
+		return orderIDs, nil
+	}
+
+	var rows []struct {
+		ConversationID int
+		OrderID        int
+	}
+	err := r.db.Model(&domain.Message{}).
+		Select("conversation_id, MAX(order_id) AS order_id").
+		Where("conversation_id IN ? AND order_id IS NOT NULL", conversationIDs).
+		Group("conversation_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		orderIDs[row.ConversationID] = row.OrderID
+	}
+	return orderIDs, nil
+}
+
+// Search matches message content against a web-style query ("quoted phrases", -exclusions, or)
+// using the idx_messages_content_search GIN index. Deleted messages never match.
+func (r *messageRepository) Search(params domain.MessageSearchParams) ([]*domain.MessageSearchResult, error) {
+	query := r.db.Table("messages AS m").
+		Select(`m.id AS message_id, m.conversation_id, m.sender_id, m.created_at AS sent_at,
+			ts_headline('simple', m.content, q.query, 'StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=1') AS snippet`).
+		Joins("CROSS JOIN websearch_to_tsquery('simple', ?) AS q(query)", params.Query).
+		Joins("JOIN participants AS p ON p.conversation_id = m.conversation_id AND p.user_id = ? AND p.deleted_at IS NULL", params.UserID).
+		Joins("JOIN conversations AS c ON c.id = m.conversation_id AND c.deleted_at IS NULL").
+		Where("to_tsvector('simple', m.content) @@ q.query").
+		Where("m.deleted_at IS NULL AND m.is_deleted = ?", false)
+
+	if params.ProductID != nil {
+		query = query.Where("c.product_id = ?", *params.ProductID)
+	}
+	if params.OrderID != nil {
+		query = query.Where("EXISTS (SELECT 1 FROM messages AS om WHERE om.conversation_id = m.conversation_id AND om.order_id = ?)", *params.OrderID)
+	}
+	if params.From != nil {
+		query = query.Where("m.created_at >= ?", *params.From)
+	}
+	if params.To != nil {
+		query = query.Where("m.created_at < ?", *params.To)
+	}
+
+	var results []*domain.MessageSearchResult
+	err := query.
+		Order("ts_rank(to_tsvector('simple', m.content), q.query) DESC, m.id DESC").
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 func (r *messageRepository) GetByOrderID(orderID int) ([]*domain.Message, error) {
 	var messages []*domain.Message
-	err := r.db.Preload("Sender").Where("order_id = ?", orderID).Order("created_at ASC").Find(&messages).Error
+	err := r.db.Preload("Sender").Preload("Attachments").Where("order_id = ?", orderID).Order("created_at ASC").Find(&messages).Error
 	if err != nil {
 		return nil, err
 	}
@@ -80,3 +174,73 @@ func (r *messageRepository) GetSystemMessages(conversationID int) ([]*domain.Mes
 	}
 	return messages, nil
 }
+
+// GetSinceForUser returns messages newer than sinceID from every conversation the user takes part in, oldest first.
+func (r *messageRepository) GetSinceForUser(userID, sinceID, limit int) ([]*domain.Message, error) {
+	var messages []*domain.Message
+	err := r.db.Preload("Sender").Preload("Attachments").
+		Where("id > ?", sinceID).
+		Where("conversation_id IN (?)", r.db.Model(&domain.Participant{}).Select("conversation_id").Where("user_id = ?", userID)).
+		Order("id ASC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// GetLatestID returns the ID of the newest message in the conversation, or 0 when it has none.
+func (r *messageRepository) GetLatestID(conversationID int) (int, error) {
+	var id int
+	err := r.db.Model(&domain.Message{}).
+		Where("conversation_id = ?", conversationID).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&id).Error
+	return id, err
+}
+
+// Edit replaces the message content and stores the previous version in the same transaction.
+func (r *messageRepository) Edit(id int, content string, revision *domain.MessageRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(revision).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.Message{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"content":   content,
+			"edited_at": time.Now(),
+		}).Error
+	})
+}
+
+// Tombstone blanks a message for everyone while keeping the row, so the conversation shows where it was.
+// The last content goes to the revisions and the message's attachments are soft-deleted and returned,
+// so their files can be removed once the transaction has committed.
+func (r *messageRepository) Tombstone(id int, revision *domain.MessageRevision) ([]domain.Attachment, error) {
+	var attachments []domain.Attachment
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(revision).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("message_id = ?", id).Find(&attachments).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("message_id = ?", id).Delete(&domain.Attachment{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.Message{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"content":    domain.DeletedMessagePlaceholder,
+			"is_deleted": true,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *messageRepository) GetRevisions(messageID int) ([]*domain.MessageRevision, error) {
+	var revisions []*domain.MessageRevision
+	err := r.db.Where("message_id = ?", messageID).Order("id DESC").Find(&revisions).Error
+	return revisions, err
+}
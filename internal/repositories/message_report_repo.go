@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type messageReportRepository struct {
+	db *gorm.DB
+}
+
+func NewMessageReportRepository(db *gorm.DB) domain.MessageReportRepository {
+	return &messageReportRepository{db: db}
+}
+
+// Create stores the report. A participant can report a message only once.
+func (r *messageReportRepository) Create(report *domain.MessageReport) error {
+	if report.ReporterID != nil {
+		var count int64
+		err := r.db.Model(&domain.MessageReport{}).
+			Where("message_id = ? AND reporter_id = ?", report.MessageID, *report.ReporterID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("you have already reported this message")
+		}
+	}
+	return r.db.Create(report).Error
+}
+
+// GetOpen returns unresolved reports, oldest first, with the reported message and its sender.
+func (r *messageReportRepository) GetOpen(limit, offset int) ([]*domain.MessageReport, int64, error) {
+	var total int64
+	query := r.db.Model(&domain.MessageReport{}).Where("status = ?", domain.MessageReportStatusOpen)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var reports []*domain.MessageReport
+	err := query.
+		Preload("Message.Sender").
+		Preload("Reporter").
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&reports).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return reports, total, nil
+}
+
+func (r *messageReportRepository) CountOpenByMessageID(messageID int) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.MessageReport{}).
+		Where("message_id = ? AND status = ?", messageID, domain.MessageReportStatusOpen).
+		Count(&count).Error
+	return count, err
+}
+
+// ResolveOpen closes every open report of the message with the same decision.
+func (r *messageReportRepository) ResolveOpen(messageID int, resolution, comment string, resolverID int) error {
+	return r.db.Model(&domain.MessageReport{}).
+		Where("message_id = ? AND status = ?", messageID, domain.MessageReportStatusOpen).
+		Updates(map[string]interface{}{
+			"status":            domain.MessageReportStatusResolved,
+			"resolution":        resolution,
+			"moderator_comment": comment,
+			"resolved_by_id":    resolverID,
+			"resolved_at":       time.Now(),
+		}).Error
+}
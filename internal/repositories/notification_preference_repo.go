@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationPreferenceRepository(db *gorm.DB) domain.NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db}
+}
+
+func (r *notificationPreferenceRepository) GetByUserID(userID int) (*domain.NotificationPreference, error) {
+	var preference domain.NotificationPreference
+	err := r.db.Where("user_id = ?", userID).First(&preference).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if preference.Channels == nil {
+		preference.Channels = map[string]map[string]bool{}
+	}
+	return &preference, nil
+}
+
+func (r *notificationPreferenceRepository) Save(preference *domain.NotificationPreference) error {
+	return r.db.Save(preference).Error
+}
+
+func (r *notificationPreferenceRepository) GetDigestDue(now time.Time, limit int) ([]*domain.NotificationPreference, error) {
+	query := r.db.Where("1 = 0")
+	for frequency, period := range domain.DigestPeriods {
+		query = query.Or("digest_frequency = ? AND (last_digest_at IS NULL OR last_digest_at <= ?)", frequency, now.Add(-period))
+	}
+
+	var preferences []*domain.NotificationPreference
+	err := r.db.Where(query).Order("user_id ASC").Limit(limit).Find(&preferences).Error
+	return preferences, err
+}
+
+func (r *notificationPreferenceRepository) SetLastDigestAt(userID int, at time.Time) error {
+	return r.db.Model(&domain.NotificationPreference{}).
+		Where("user_id = ?", userID).
+		UpdateColumn("last_digest_at", at).Error
+}
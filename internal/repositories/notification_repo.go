@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) domain.NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) Create(notification *domain.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+func (r *notificationRepository) ReplaceUnread(notification *domain.Notification) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ? AND type = ? AND link = ? AND is_read = ?",
+			notification.UserID, notification.Type, notification.Link, false).
+			Delete(&domain.Notification{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(notification).Error
+	})
+}
+
+// GetByUserID returns the user's notifications, newest first.
+func (r *notificationRepository) GetByUserID(userID int, limit, offset int) ([]*domain.Notification, int64, error) {
+	var total int64
+	query := r.db.Model(&domain.Notification{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []*domain.Notification
+	err := query.
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&notifications).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+func (r *notificationRepository) CountUnread(userID int) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *notificationRepository) GetUnreadSince(userID int, since time.Time, limit int) ([]*domain.Notification, int64, error) {
+	var total int64
+	query := r.db.Model(&domain.Notification{}).Where("user_id = ? AND is_read = ? AND created_at > ?", userID, false, since)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []*domain.Notification
+	err := query.Order("created_at ASC, id ASC").Limit(limit).Find(&notifications).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+// MarkRead is scoped to the owner, so users cannot touch each other's notifications.
+func (r *notificationRepository) MarkRead(id, userID int) error {
+	var notification domain.Notification
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&notification).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("notification not found")
+		}
+		return err
+	}
+	if notification.IsRead {
+		return nil
+	}
+
+	return r.db.Model(&notification).Updates(map[string]interface{}{
+		"is_read": true,
+		"read_at": time.Now(),
+	}).Error
+}
+
+func (r *notificationRepository) MarkAllRead(userID int) error {
+	return r.db.Model(&domain.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Updates(map[string]interface{}{
+			"is_read": true,
+			"read_at": time.Now(),
+		}).Error
+}
@@ -107,6 +107,16 @@ func (r *orderRepository) BeginTx() (*gorm.DB, error) {
 	return r.db.Begin(), nil
 }
 
+func (r *orderRepository) SetConversationID(id, conversationID int) error {
+	return r.db.Model(&domain.Order{}).
+		Where("id = ?", id).
+		UpdateColumn("conversation_id", conversationID).Error
+}
+
+func (r *orderRepository) CreateTx(tx *gorm.DB, order *domain.Order) error {
+	return tx.Create(order).Error
+}
+
 func (r *orderRepository) UpdateStatusTx(tx *gorm.DB, id int, status string) error {
 	return tx.Model(&domain.Order{}).
 		Where("id = ?", id).
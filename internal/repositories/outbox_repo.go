@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) domain.OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Create(event *domain.OutboxEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r *outboxRepository) CreateTx(tx *gorm.DB, event *domain.OutboxEvent) error {
+	return tx.Create(event).Error
+}
+
+func (r *outboxRepository) ClaimDue(limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+	var events []*domain.OutboxEvent
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", domain.OutboxStatusPending, now).
+			Order("id ASC").
+			Limit(limit).
+			Find(&events).Error
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		ids := make([]int, len(events))
+		for i, event := range events {
+			ids[i] = event.ID
+			event.NextAttemptAt = now.Add(lease)
+		}
+		return tx.Model(&domain.OutboxEvent{}).
+			Where("id IN ?", ids).
+			UpdateColumn("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *outboxRepository) Update(event *domain.OutboxEvent) error {
+	return r.db.Save(event).Error
+}
+
+// GetByStatus returns events with the given status, newest first; an empty status matches all.
+func (r *outboxRepository) GetByStatus(status string, limit, offset int) ([]*domain.OutboxEvent, int64, error) {
+	query := r.db.Model(&domain.OutboxEvent{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []*domain.OutboxEvent
+	err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+func (r *outboxRepository) CountByStatus() (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := r.db.Model(&domain.OutboxEvent{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int64{
+		domain.OutboxStatusPending:   0,
+		domain.OutboxStatusDelivered: 0,
+		domain.OutboxStatusDead:      0,
+	}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *outboxRepository) Requeue(id int) error {
+	result := r.db.Model(&domain.OutboxEvent{}).
+		Where("id = ? AND status = ?", id, domain.OutboxStatusDead).
+		Updates(map[string]interface{}{
+			"status":          domain.OutboxStatusPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("dead outbox event not found")
+	}
+	return nil
+}
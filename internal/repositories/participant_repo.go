@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"MicroShopik/internal/domain"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -51,3 +52,73 @@ func (r *participantRepository) IsParticipant(conversationID, userID int) (bool,
 	}
 	return count > 0, nil
 }
+
+// MarkRead moves the participant's read marker forward to messageID. It never moves it back,
+// and reports whether the marker changed.
+func (r *participantRepository) MarkRead(conversationID, userID, messageID int) (bool, error) {
+	result := r.db.Model(&domain.Participant{}).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		Where("last_read_message_id IS NULL OR last_read_message_id < ?", messageID).
+		Updates(map[string]interface{}{
+			"last_read_message_id": messageID,
+			"last_read_at":         time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *participantRepository) GetUnreadForEmail(olderThan time.Time, limit int) ([]*domain.UnreadNotice, error) {
+	var notices []*domain.UnreadNotice
+	err := r.db.Raw(`
+		SELECT p.conversation_id, p.user_id, COUNT(m.id) AS count, MAX(m.id) AS last_message_id
+		FROM participants p
+		JOIN messages m ON m.conversation_id = p.conversation_id
+			AND m.id > GREATEST(COALESCE(p.last_read_message_id, 0), COALESCE(p.last_emailed_message_id, 0))
+			AND m.deleted_at IS NULL
+			AND NOT m.is_deleted
+			AND NOT m.is_system
+			AND m.sender_id <> p.user_id
+		WHERE p.deleted_at IS NULL
+		GROUP BY p.conversation_id, p.user_id
+		HAVING MIN(m.created_at) <= ?
+		ORDER BY MIN(m.created_at)
+		LIMIT ?`, olderThan, limit).
+		Scan(&notices).Error
+	if err != nil {
+		return nil, err
+	}
+	return notices, nil
+}
+
+func (r *participantRepository) MarkEmailed(conversationID, userID, messageID int) error {
+	return r.db.Model(&domain.Participant{}).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		UpdateColumn("last_emailed_message_id", messageID).Error
+}
+
+// GetUnreadCounts returns, per conversation, how many messages from others arrived after the user's read marker.
+// Conversations without unread messages are left out.
+func (r *participantRepository) GetUnreadCounts(userID int) (map[int]int, error) {
+	var rows []struct {
+		ConversationID int
+		Unread         int
+	}
+	err := r.db.Raw(`
+		SELECT p.conversation_id, COUNT(m.id) AS unread
+		FROM participants p
+		JOIN messages m ON m.conversation_id = p.conversation_id
+			AND m.id > COALESCE(p.last_read_message_id, 0)
+			AND m.deleted_at IS NULL
+			AND (m.sender_id IS NULL OR m.sender_id <> p.user_id)
+		WHERE p.user_id = ? AND p.deleted_at IS NULL
+		GROUP BY p.conversation_id`, userID).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int]int, len(rows))
+	for _, row := range rows {
+		counts[row.ConversationID] = row.Unread
+	}
+	return counts, nil
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type productImportJobRepository struct {
+	db *gorm.DB
+}
+
+func NewProductImportJobRepository(db *gorm.DB) domain.ProductImportJobRepository {
+	return &productImportJobRepository{db: db}
+}
+
+func (r *productImportJobRepository) Create(job *domain.ProductImportJob) error {
+	return r.db.Create(job).Error
+}
+
+func (r *productImportJobRepository) GetByID(id int) (*domain.ProductImportJob, error) {
+	var job domain.ProductImportJob
+	err := r.db.Where("id = ?", id).First(&job).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("import job not found")
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *productImportJobRepository) Update(job *domain.ProductImportJob) error {
+	return r.db.Save(job).Error
+}
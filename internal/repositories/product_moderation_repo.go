@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"MicroShopik/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type productModerationLogRepository struct {
+	db *gorm.DB
+}
+
+func NewProductModerationLogRepository(db *gorm.DB) domain.ProductModerationLogRepository {
+	return &productModerationLogRepository{db: db}
+}
+
+func (r *productModerationLogRepository) Create(entry *domain.ProductModerationLog) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *productModerationLogRepository) GetByProductID(productID int) ([]*domain.ProductModerationLog, error) {
+	var entries []*domain.ProductModerationLog
+	err := r.db.Preload("Actor").
+		Where("product_id = ?", productID).
+		Order("created_at DESC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"MicroShopik/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type productRecommendationRepository struct {
+	db *gorm.DB
+}
+
+func NewProductRecommendationRepository(db *gorm.DB) domain.ProductRecommendationRepository {
+	return &productRecommendationRepository{db: db}
+}
+
+// RebuildCoPurchases replaces the co-purchase matrix with fresh counts from the orders table.
+func (r *productRecommendationRepository) RebuildCoPurchases(orderStatuses []string) (int64, error) {
+	var rows int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM product_co_purchases").Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(`
+			INSERT INTO product_co_purchases (product_id, related_product_id, score, updated_at)
+			SELECT a.product_id, b.product_id, COUNT(DISTINCT a.customer_id), NOW()
+			FROM orders a
+			JOIN orders b ON a.customer_id = b.customer_id AND a.product_id <> b.product_id
+			WHERE a.status IN ? AND b.status IN ?
+				AND a.deleted_at IS NULL AND b.deleted_at IS NULL
+				AND a.customer_id IS NOT NULL
+				AND a.product_id IS NOT NULL AND b.product_id IS NOT NULL
+			GROUP BY a.product_id, b.product_id`,
+			orderStatuses, orderStatuses)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		rows = result.RowsAffected
+		return nil
+	})
+	return rows, err
+}
+
+func (r *productRecommendationRepository) GetCoPurchased(productID, minScore, limit int) ([]*domain.Product, error) {
+	var products []*domain.Product
+	err := r.db.Preload("Category").
+		Joins("JOIN product_co_purchases ON product_co_purchases.related_product_id = products.id").
+		Where("product_co_purchases.product_id = ? AND product_co_purchases.score >= ?", productID, minScore).
+		Where("products.is_active = ? AND (products.max_sales = 0 OR products.sold_count < products.max_sales)", true).
+		Order("product_co_purchases.score DESC, products.sold_count DESC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *productRecommendationRepository) GetPopularInCategory(categoryID int, excludeIDs []int, limit int) ([]*domain.Product, error) {
+	query := r.db.Preload("Category").
+		Where("category_id = ?", categoryID).
+		Where("is_active = ? AND (max_sales = 0 OR sold_count < max_sales)", true)
+
+	if len(excludeIDs) > 0 {
+		query = query.Where("id NOT IN ?", excludeIDs)
+	}
+
+	var products []*domain.Product
+	err := query.Order("sold_count DESC, created_at DESC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
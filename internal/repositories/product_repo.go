@@ -32,7 +32,45 @@ func (r *productRepository) GetById(id int) (*domain.Product, error) {
 	return &product, nil
 }
 
+func (r *productRepository) GetBySlug(slug string) (*domain.Product, error) {
+	var product domain.Product
+	err := r.db.Where("slug = ?", slug).First(&product).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
 func (r *productRepository) Update(id int, data domain.ProductUpdateData) error {
+	updates := productUpdates(data)
+	if len(updates) == 0 {
+		return nil // nothing to update
+	}
+
+	return r.db.Model(&domain.Product{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *productRepository) UpdateWithRevision(id int, data domain.ProductUpdateData, revision *domain.ProductRevision) error {
+	updates := productUpdates(data)
+	if len(updates) == 0 {
+		return nil // nothing to update
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.Product{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+		if revision == nil {
+			return nil
+		}
+		return tx.Create(revision).Error
+	})
+}
+
+func productUpdates(data domain.ProductUpdateData) map[string]interface{} {
 	updates := make(map[string]interface{})
 
 	if data.Title != nil {
@@ -56,12 +94,11 @@ func (r *productRepository) Update(id int, data domain.ProductUpdateData) error
 	if data.MaxSales != nil {
 		updates["max_sales"] = *data.MaxSales
 	}
-
-	if len(updates) == 0 {
-		return nil // nothing to update
+	if data.Slug != nil {
+		updates["slug"] = *data.Slug
 	}
 
-	return r.db.Model(&domain.Product{}).Where("id = ?", id).Updates(updates).Error
+	return updates
 }
 
 func (r *productRepository) Delete(id int) error {
@@ -147,6 +184,9 @@ func (r *productRepository) Find(params domain.ProductQueryParams) ([]*domain.Pr
 	if params.MaxPrice != nil {
 		query = query.Where("price <= ?", *params.MaxPrice)
 	}
+	if params.ModerationStatus != nil {
+		query = query.Where("moderation_status = ?", *params.ModerationStatus)
+	}
 	if params.SearchQuery != nil && *params.SearchQuery != "" {
 		query = query.Where("title ILIKE ? OR description ILIKE ?",
 			"%"+*params.SearchQuery+"%", "%"+*params.SearchQuery+"%")
@@ -187,6 +227,9 @@ func (r *productRepository) Count(params domain.ProductQueryParams) (int, error)
 	if params.MaxPrice != nil {
 		query = query.Where("price <= ?", *params.MaxPrice)
 	}
+	if params.ModerationStatus != nil {
+		query = query.Where("moderation_status = ?", *params.ModerationStatus)
+	}
 	if params.SearchQuery != nil && *params.SearchQuery != "" {
 		query = query.Where("title ILIKE ? OR description ILIKE ?", "%"+*params.SearchQuery+"%", "%"+*params.SearchQuery+"%")
 	}
@@ -207,3 +250,50 @@ func (r *productRepository) GetBySellerID(sellerID int) ([]*domain.Product, erro
 	err := r.db.Preload("Category").Where("seller_id = ?", sellerID).Find(&products).Error
 	return products, err
 }
+
+func (r *productRepository) GetBySellerAndSKU(sellerID int, sku string) (*domain.Product, error) {
+	var product domain.Product
+	err := r.db.Where("seller_id = ? AND sku = ?", sellerID, sku).First(&product).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *productRepository) GetByModerationStatus(status string, limit, offset int) ([]*domain.Product, error) {
+	query := r.db.Preload("Category").
+		Where("moderation_status = ?", status).
+		Order("updated_at ASC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var products []*domain.Product
+	err := query.Find(&products).Error
+	return products, err
+}
+
+func (r *productRepository) UpdateModerationStatus(id int, status string, isActive bool, reasonCode, reason string) error {
+	result := r.db.Model(&domain.Product{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"moderation_status": status,
+			"is_active":         isActive,
+			"rejection_code":    reasonCode,
+			"rejection_reason":  reason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("product not found")
+	}
+	return nil
+}
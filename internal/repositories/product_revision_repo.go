@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type productRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewProductRevisionRepository(db *gorm.DB) domain.ProductRevisionRepository {
+	return &productRevisionRepository{db: db}
+}
+
+func (r *productRevisionRepository) GetByID(id int) (*domain.ProductRevision, error) {
+	var revision domain.ProductRevision
+	err := r.db.Preload("Actor").Where("id = ?", id).First(&revision).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("revision not found")
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *productRevisionRepository) GetByProductID(productID int) ([]*domain.ProductRevision, error) {
+	var revisions []*domain.ProductRevision
+	err := r.db.Preload("Actor").
+		Where("product_id = ?", productID).
+		Order("id DESC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
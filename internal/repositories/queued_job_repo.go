@@ -0,0 +1,263 @@
+package repositories
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type queuedJobRepository struct {
+	db *gorm.DB
+}
+
+func NewQueuedJobRepository(db *gorm.DB) domain.QueuedJobRepository {
+	return &queuedJobRepository{db: db}
+}
+
+func (r *queuedJobRepository) Create(job *domain.QueuedJob) (bool, error) {
+	if job.UniqueKey == nil {
+		if err := r.db.Create(job).Error; err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "unique_key"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "unique_key IS NOT NULL AND finished_at IS NULL"}}},
+		DoNothing:   true,
+	}).Create(job)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *queuedJobRepository) GetByID(id int) (*domain.QueuedJob, error) {
+	var job domain.QueuedJob
+	err := r.db.Where("id = ?", id).First(&job).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("job not found")
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *queuedJobRepository) GetActiveByUniqueKey(key string) (*domain.QueuedJob, error) {
+	var job domain.QueuedJob
+	err := r.db.Where("unique_key = ? AND finished_at IS NULL", key).First(&job).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("job not found")
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *queuedJobRepository) Claim(workerID string, types []string, limit int) ([]*domain.QueuedJob, error) {
+	var jobs []*domain.QueuedJob
+	if len(types) == 0 {
+		return jobs, nil
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_at <= ? AND type IN ?", domain.QueuedJobStatusQueued, now, types).
+			Order("priority DESC, run_at ASC, id ASC").
+			Limit(limit).
+			Find(&jobs).Error
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+
+		ids := make([]int, len(jobs))
+		for i, job := range jobs {
+			ids[i] = job.ID
+			job.Status = domain.QueuedJobStatusRunning
+			job.Attempts++
+			job.LockedBy = workerID
+			job.LockedAt = &now
+			job.HeartbeatAt = &now
+		}
+		return tx.Model(&domain.QueuedJob{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":       domain.QueuedJobStatusRunning,
+				"attempts":     gorm.Expr("attempts + 1"),
+				"locked_by":    workerID,
+				"locked_at":    now,
+				"heartbeat_at": now,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *queuedJobRepository) Release(job *domain.QueuedJob) (bool, error) {
+	result := r.db.Model(&domain.QueuedJob{}).
+		Where("id = ? AND status = ? AND locked_by = ?", job.ID, domain.QueuedJobStatusRunning, job.LockedBy).
+		Updates(map[string]interface{}{
+			"status":       job.Status,
+			"run_at":       job.RunAt,
+			"last_error":   job.LastError,
+			"finished_at":  job.FinishedAt,
+			"locked_by":    "",
+			"locked_at":    nil,
+			"heartbeat_at": nil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *queuedJobRepository) Heartbeat(ids []int, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&domain.QueuedJob{}).
+		Where("id IN ? AND status = ?", ids, domain.QueuedJobStatusRunning).
+		UpdateColumn("heartbeat_at", at).Error
+}
+
+func (r *queuedJobRepository) RequeueStale(heartbeatBefore time.Time) (int64, []*domain.QueuedJob, error) {
+	var requeued int64
+	var dead []*domain.QueuedJob
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var stale []*domain.QueuedJob
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND heartbeat_at < ?", domain.QueuedJobStatusRunning, heartbeatBefore).
+			Find(&stale).Error
+		if err != nil || len(stale) == 0 {
+			return err
+		}
+
+		ids := make([]int, 0, len(stale))
+		for _, job := range stale {
+			ids = append(ids, job.ID)
+		}
+
+		now := time.Now()
+		err = tx.Model(&domain.QueuedJob{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":       gorm.Expr("CASE WHEN attempts >= max_attempts THEN ? ELSE ? END", domain.QueuedJobStatusDead, domain.QueuedJobStatusQueued),
+				"finished_at":  gorm.Expr("CASE WHEN attempts >= max_attempts THEN ?::timestamptz ELSE NULL END", now),
+				"run_at":       now,
+				"last_error":   "worker stopped responding",
+				"locked_by":    "",
+				"locked_at":    nil,
+				"heartbeat_at": nil,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		for _, job := range stale {
+			if job.Attempts >= job.MaxAttempts {
+				job.Status = domain.QueuedJobStatusDead
+				job.FinishedAt = &now
+				job.LastError = "worker stopped responding"
+				dead = append(dead, job)
+			}
+		}
+		requeued = int64(len(stale) - len(dead))
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return requeued, dead, nil
+}
+
+func (r *queuedJobRepository) GetByStatus(status, jobType string, limit, offset int) ([]*domain.QueuedJob, int64, error) {
+	query := r.db.Model(&domain.QueuedJob{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if jobType != "" {
+		query = query.Where("type = ?", jobType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var jobs []*domain.QueuedJob
+	err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&jobs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+func (r *queuedJobRepository) CountByStatus() (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := r.db.Model(&domain.QueuedJob{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int64{
+		domain.QueuedJobStatusQueued:    0,
+		domain.QueuedJobStatusRunning:   0,
+		domain.QueuedJobStatusSucceeded: 0,
+		domain.QueuedJobStatusDead:      0,
+		domain.QueuedJobStatusCancelled: 0,
+	}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *queuedJobRepository) Requeue(id int) error {
+	result := r.db.Model(&domain.QueuedJob{}).
+		Where("id = ? AND status IN ?", id, []string{domain.QueuedJobStatusDead, domain.QueuedJobStatusCancelled}).
+		Updates(map[string]interface{}{
+			"status":      domain.QueuedJobStatusQueued,
+			"attempts":    0,
+			"run_at":      time.Now(),
+			"finished_at": nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("dead or cancelled job not found")
+	}
+	return nil
+}
+
+func (r *queuedJobRepository) Cancel(id int) error {
+	result := r.db.Model(&domain.QueuedJob{}).
+		Where("id = ? AND status = ?", id, domain.QueuedJobStatusQueued).
+		Updates(map[string]interface{}{
+			"status":      domain.QueuedJobStatusCancelled,
+			"finished_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("queued job not found")
+	}
+	return nil
+}
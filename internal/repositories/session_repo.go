@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) domain.SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) BeginTx() (*gorm.DB, error) {
+	tx := r.db.Begin()
+	return tx, tx.Error
+}
+
+func (r *sessionRepository) CreateTx(tx *gorm.DB, session *domain.Session) error {
+	return tx.Create(session).Error
+}
+
+func (r *sessionRepository) GetByTokenHashTx(tx *gorm.DB, tokenHash string) (*domain.Session, error) {
+	var session domain.Session
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", tokenHash).
+		First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("session not found")
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) UpdateTx(tx *gorm.DB, session *domain.Session) error {
+	return tx.Save(session).Error
+}
+
+func (r *sessionRepository) RevokeFamilyTx(tx *gorm.DB, familyID, reason string, at time.Time) error {
+	return tx.Model(&domain.Session{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Updates(map[string]interface{}{
+			"revoked_at":    at,
+			"revoke_reason": reason,
+		}).Error
+}
+
+func (r *sessionRepository) GetActiveByUserID(userID int, now time.Time) ([]*domain.Session, error) {
+	var sessions []*domain.Session
+	err := r.db.
+		Where("user_id = ? AND revoked_at IS NULL AND rotated_at IS NULL AND expires_at > ?", userID, now).
+		Order("created_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepository) RevokeUserFamily(userID int, familyID, reason string, at time.Time) (bool, error) {
+	result := r.db.Model(&domain.Session{}).
+		Where("user_id = ? AND family_id = ? AND revoked_at IS NULL", userID, familyID).
+		Updates(map[string]interface{}{
+			"revoked_at":    at,
+			"revoke_reason": reason,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *sessionRepository) RevokeAllByUserID(userID int, reason string, at time.Time) error {
+	return r.db.Model(&domain.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"revoked_at":    at,
+			"revoke_reason": reason,
+		}).Error
+}
+
+func (r *sessionRepository) IsFamilyRevoked(familyID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.Session{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Count(&count).Error
+	return count == 0, err
+}
+
+func (r *sessionRepository) DeleteExpired(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&domain.Session{})
+	return result.RowsAffected, result.Error
+}
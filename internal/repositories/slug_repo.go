@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type slugTable struct {
+	name      string
+	source    string
+	updatedAt string
+	active    string
+}
+
+// slugTables maps slug entity types to the table that owns the slug column.
+var slugTables = map[string]slugTable{
+	domain.SlugEntityProduct: {
+		name:      "products",
+		source:    "title",
+		updatedAt: "updated_at",
+		active:    "is_active = TRUE AND deleted_at IS NULL AND moderation_status = 'approved'",
+	},
+	domain.SlugEntityCategory: {
+		name:      "categories",
+		source:    "name",
+		updatedAt: "created_at",
+		active:    "TRUE",
+	},
+}
+
+type slugRepository struct {
+	db *gorm.DB
+}
+
+func NewSlugRepository(db *gorm.DB) domain.SlugRepository {
+	return &slugRepository{db: db}
+}
+
+func (r *slugRepository) table(entityType string) (slugTable, error) {
+	table, ok := slugTables[entityType]
+	if !ok {
+		return slugTable{}, fmt.Errorf("unknown slug entity type %q", entityType)
+	}
+	return table, nil
+}
+
+// IsTaken reports whether the slug belongs to another entity, either as its current slug
+// or as a redirect. Soft-deleted rows count, since they still hold the unique index.
+func (r *slugRepository) IsTaken(entityType, slug string, entityID int) (bool, error) {
+	table, err := r.table(entityType)
+	if err != nil {
+		return false, err
+	}
+
+	var count int64
+	err = r.db.Raw("SELECT COUNT(*) FROM "+table.name+" WHERE slug = ? AND id <> ?", slug, entityID).
+		Scan(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+
+	err = r.db.Model(&domain.SlugRedirect{}).
+		Where("entity_type = ? AND old_slug = ? AND entity_id <> ?", entityType, slug, entityID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *slugRepository) SetSlug(entityType string, entityID int, slug string) error {
+	table, err := r.table(entityType)
+	if err != nil {
+		return err
+	}
+	return r.db.Exec("UPDATE "+table.name+" SET slug = ? WHERE id = ?", slug, entityID).Error
+}
+
+func (r *slugRepository) GetMissing(entityType string, limit int) ([]domain.SlugSource, error) {
+	table, err := r.table(entityType)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []domain.SlugSource
+	err = r.db.Raw("SELECT id, "+table.source+" AS source FROM "+table.name+" WHERE slug IS NULL ORDER BY id LIMIT ?", limit).
+		Scan(&sources).Error
+	return sources, err
+}
+
+func (r *slugRepository) CreateRedirect(redirect *domain.SlugRedirect) error {
+	return r.db.Create(redirect).Error
+}
+
+func (r *slugRepository) GetRedirect(entityType, slug string) (*domain.SlugRedirect, error) {
+	var redirect domain.SlugRedirect
+	err := r.db.Where("entity_type = ? AND old_slug = ?", entityType, slug).First(&redirect).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("slug redirect not found")
+		}
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+func (r *slugRepository) DeleteRedirect(entityType, slug string, entityID int) error {
+	return r.db.Where("entity_type = ? AND old_slug = ? AND entity_id = ?", entityType, slug, entityID).
+		Delete(&domain.SlugRedirect{}).Error
+}
+
+func (r *slugRepository) CountSitemapEntries(entityType string) (int, error) {
+	table, err := r.table(entityType)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = r.db.Raw("SELECT COUNT(*) FROM " + table.name + " WHERE slug IS NOT NULL AND " + table.active).
+		Scan(&count).Error
+	return int(count), err
+}
+
+func (r *slugRepository) GetSitemapEntries(entityType string, limit, offset int) ([]domain.SitemapEntry, error) {
+	table, err := r.table(entityType)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []domain.SitemapEntry
+	err = r.db.Raw("SELECT slug, "+table.updatedAt+" AS updated_at FROM "+table.name+
+		" WHERE slug IS NOT NULL AND "+table.active+" ORDER BY id LIMIT ? OFFSET ?", limit, offset).
+		Scan(&entries).Error
+	return entries, err
+}
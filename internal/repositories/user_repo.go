@@ -4,6 +4,7 @@ import (
 	"MicroShopik/internal/domain"
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -58,6 +59,47 @@ func (r *userRepository) LastLoginUpdate(userID int) error {
 		UpdateColumn("last_login", gorm.Expr("NOW()")).Error
 }
 
+func (r *userRepository) UpdateLastSeen(userID int, at time.Time) error {
+	return r.db.Model(&domain.User{}).
+		Where("id = ?", userID).
+		UpdateColumn("last_seen_at", at).Error
+}
+
+func (r *userRepository) SetHideOnlineStatus(userID int, hide bool) error {
+	return r.db.Model(&domain.User{}).
+		Where("id = ?", userID).
+		UpdateColumn("hide_online_status", hide).Error
+}
+
+func (r *userRepository) MarkEmailVerified(userID int, email string, at time.Time) (bool, error) {
+	result := r.db.Model(&domain.User{}).
+		Where("id = ? AND email = ? AND email_verified_at IS NULL", userID, email).
+		UpdateColumn("email_verified_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *userRepository) ClearEmailVerification(userID int) error {
+	return r.db.Model(&domain.User{}).
+		Where("id = ?", userID).
+		UpdateColumns(map[string]interface{}{
+			"email_verified_at":    nil,
+			"verification_sent_at": nil,
+		}).Error
+}
+
+func (r *userRepository) ClaimVerificationSend(userID int, at, cutoff time.Time) (bool, error) {
+	result := r.db.Model(&domain.User{}).
+		Where("id = ? AND (verification_sent_at IS NULL OR verification_sent_at < ?)", userID, cutoff).
+		UpdateColumn("verification_sent_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (r *userRepository) GetByEmail(email string) (*domain.User, error) {
 	var user domain.User
 	err := r.db.Where("email = ?", email).First(&user).Error
@@ -105,6 +147,6 @@ func (r *userRepository) GetAll() ([]domain.User, error) {
 func (r *userRepository) Update(user *domain.User) error {
 	return r.db.Model(&domain.User{}).
 		Where("id = ?", user.ID).
-		Omit("created_at", "last_login").
+		Omit("created_at", "last_login", "email_verified_at", "verification_sent_at").
 		Updates(user).Error
 }
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type userSanctionRepository struct {
+	db *gorm.DB
+}
+
+func NewUserSanctionRepository(db *gorm.DB) domain.UserSanctionRepository {
+	return &userSanctionRepository{db: db}
+}
+
+func (r *userSanctionRepository) Create(sanction *domain.UserSanction) error {
+	return r.db.Create(sanction).Error
+}
+
+func (r *userSanctionRepository) GetByUserID(userID int) ([]*domain.UserSanction, error) {
+	var sanctions []*domain.UserSanction
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&sanctions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sanctions, nil
+}
+
+// GetActiveSuspension returns the suspension that ends last, or nil when the user may send messages.
+// A suspension without an end date is permanent.
+func (r *userSanctionRepository) GetActiveSuspension(userID int) (*domain.UserSanction, error) {
+	var sanction domain.UserSanction
+	err := r.db.Where("user_id = ? AND kind = ?", userID, domain.SanctionSuspension).
+		Where("expires_at IS NULL OR expires_at > NOW()").
+		Order("expires_at DESC NULLS FIRST").
+		First(&sanction).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &sanction, nil
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) domain.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{db: db}
+}
+
+func (r *webhookDeliveryRepository) Create(delivery *domain.WebhookDelivery) (bool, error) {
+	if delivery.RedeliveryOfID != nil {
+		if err := r.db.Create(delivery).Error; err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "endpoint_id"}, {Name: "event_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "redelivery_of_id IS NULL"}}},
+		DoNothing:   true,
+	}).Create(delivery)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *webhookDeliveryRepository) GetByID(id int) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := r.db.Where("id = ?", id).First(&delivery).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("webhook delivery not found")
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// GetByEndpointID returns the delivery log of an endpoint, newest first.
+func (r *webhookDeliveryRepository) GetByEndpointID(endpointID int, limit, offset int) ([]*domain.WebhookDelivery, int64, error) {
+	var total int64
+	query := r.db.Model(&domain.WebhookDelivery{}).Where("endpoint_id = ?", endpointID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []*domain.WebhookDelivery
+	err := query.
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+// ClaimDue loads the endpoint with each delivery; a deleted endpoint comes back as nil.
+func (r *webhookDeliveryRepository) ClaimDue(limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	var deliveries []*domain.WebhookDelivery
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", domain.WebhookDeliveryStatusPending, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]int, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+			delivery.NextAttemptAt = now.Add(lease)
+		}
+		return tx.Model(&domain.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			UpdateColumn("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil || len(deliveries) == 0 {
+		return deliveries, err
+	}
+
+	endpointIDs := make([]int, len(deliveries))
+	for i, delivery := range deliveries {
+		endpointIDs[i] = delivery.EndpointID
+	}
+	var endpoints []*domain.WebhookEndpoint
+	if err := r.db.Where("id IN ?", endpointIDs).Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*domain.WebhookEndpoint, len(endpoints))
+	for _, endpoint := range endpoints {
+		byID[endpoint.ID] = endpoint
+	}
+	for _, delivery := range deliveries {
+		delivery.Endpoint = byID[delivery.EndpointID]
+	}
+	return deliveries, nil
+}
+
+func (r *webhookDeliveryRepository) Update(delivery *domain.WebhookDelivery) error {
+	return r.db.Omit("Endpoint").Save(delivery).Error
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type webhookEndpointRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookEndpointRepository(db *gorm.DB) domain.WebhookEndpointRepository {
+	return &webhookEndpointRepository{db: db}
+}
+
+func (r *webhookEndpointRepository) Create(endpoint *domain.WebhookEndpoint) error {
+	return r.db.Create(endpoint).Error
+}
+
+func (r *webhookEndpointRepository) GetByID(id int) (*domain.WebhookEndpoint, error) {
+	var endpoint domain.WebhookEndpoint
+	err := r.db.Where("id = ?", id).First(&endpoint).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("webhook endpoint not found")
+		}
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+func (r *webhookEndpointRepository) GetBySellerID(sellerID int) ([]*domain.WebhookEndpoint, error) {
+	var endpoints []*domain.WebhookEndpoint
+	err := r.db.Where("seller_id = ?", sellerID).Order("id ASC").Find(&endpoints).Error
+	return endpoints, err
+}
+
+func (r *webhookEndpointRepository) CountBySellerID(sellerID int) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.WebhookEndpoint{}).Where("seller_id = ?", sellerID).Count(&count).Error
+	return count, err
+}
+
+func (r *webhookEndpointRepository) Update(endpoint *domain.WebhookEndpoint) error {
+	return r.db.Save(endpoint).Error
+}
+
+func (r *webhookEndpointRepository) Delete(id int) error {
+	return r.db.Delete(&domain.WebhookEndpoint{}, id).Error
+}
@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"hash/fnv"
+	"log"
+)
+
+// Locker makes sure a job runs on one instance at a time.
+type Locker interface {
+	// TryLock takes the named lock without waiting. It returns ok == false when another holder
+	// has it; otherwise the caller must call unlock when done.
+	TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error)
+}
+
+// PostgresLocker uses session-level advisory locks. Each held lock pins a pooled connection,
+// and Postgres releases the lock by itself if the instance dies.
+type PostgresLocker struct {
+	db *sql.DB
+}
+
+func NewPostgresLocker(db *sql.DB) *PostgresLocker {
+	return &PostgresLocker{db: db}
+}
+
+func (l *PostgresLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	key := lockKey(name)
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var ok bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&ok); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if !ok {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		defer conn.Close()
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key); err != nil {
+			log.Printf("Failed to release job lock %q, dropping the connection: %v", name, err)
+			// A connection that may still hold the lock must not go back to the pool.
+			conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+	}
+	return unlock, true, nil
+}
+
+// lockKey maps a job name onto the bigint key space of advisory locks.
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("scheduler:" + name))
+	return int64(h.Sum64())
+}
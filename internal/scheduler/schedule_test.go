@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func utc(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestCronNext(t *testing.T) {
+	// 2024-01-01 is a Monday.
+	monday := utc(2024, time.January, 1, 10, 17)
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"every minute", "* * * * *", monday, utc(2024, time.January, 1, 10, 18)},
+		{"strictly after a matching slot", "*/15 * * * *", utc(2024, time.January, 1, 10, 30), utc(2024, time.January, 1, 10, 45)},
+		{"seconds are ignored", "*/15 * * * *", monday.Add(30 * time.Second), utc(2024, time.January, 1, 10, 30)},
+		{"step", "*/15 * * * *", monday, utc(2024, time.January, 1, 10, 30)},
+		{"step from a value", "5/20 * * * *", monday, utc(2024, time.January, 1, 10, 25)},
+		{"step over a range", "0 1-10/3 * * *", utc(2024, time.January, 1, 4, 0), utc(2024, time.January, 1, 7, 0)},
+		{"list", "0,45 * * * *", monday, utc(2024, time.January, 1, 10, 45)},
+		{"range of hours and weekdays", "0 9-17 * * 1-5", utc(2024, time.January, 5, 17, 30), utc(2024, time.January, 8, 9, 0)},
+		{"next day", "0 3 * * *", monday, utc(2024, time.January, 2, 3, 0)},
+		{"next year", "0 0 1 1 *", monday, utc(2025, time.January, 1, 0, 0)},
+		{"leap day", "0 0 29 2 *", utc(2024, time.March, 1, 0, 0), utc(2028, time.February, 29, 0, 0)},
+		{"sunday as 0", "30 2 * * 0", monday, utc(2024, time.January, 7, 2, 30)},
+		{"sunday as 7", "30 2 * * 7", monday, utc(2024, time.January, 7, 2, 30)},
+		{"day of month only", "0 0 15 * *", monday, utc(2024, time.January, 15, 0, 0)},
+		{"day of week only", "0 0 * * 5", monday, utc(2024, time.January, 5, 0, 0)},
+		{"both days restricted, weekday first", "0 0 15 * 5", monday, utc(2024, time.January, 5, 0, 0)},
+		{"both days restricted, day of month first", "0 0 15 * 5", utc(2024, time.January, 13, 0, 0), utc(2024, time.January, 15, 0, 0)},
+		{"stepped weekday starting with * needs both days", "0 0 1 * */2", utc(2024, time.January, 30, 0, 0), utc(2024, time.February, 1, 0, 0)},
+		{"hourly", "@hourly", monday, utc(2024, time.January, 1, 11, 0)},
+		{"daily", "@daily", monday, utc(2024, time.January, 2, 0, 0)},
+		{"weekly", "@weekly", monday, utc(2024, time.January, 7, 0, 0)},
+		{"monthly", "@monthly", monday, utc(2024, time.February, 1, 0, 0)},
+		{"input in another zone", "0 * * * *", time.Date(2024, time.January, 1, 13, 17, 0, 0, time.FixedZone("MSK", 3*60*60)), utc(2024, time.January, 1, 11, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.spec, err)
+			}
+			got := schedule.Next(tt.from)
+			if !got.Equal(tt.want) {
+				t.Errorf("Parse(%q).Next(%v) = %v, want %v", tt.spec, tt.from, got, tt.want)
+			}
+			if got.Location() != time.UTC {
+				t.Errorf("Parse(%q).Next(%v) is in %v, want UTC", tt.spec, tt.from, got.Location())
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 0 *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"a * * * *",
+		"1-x * * * *",
+		"0 0 30 2 *",
+		"@yearly",
+		"@every",
+		"@every soon",
+		"@every 500ms",
+		"@every -1h",
+	}
+
+	for _, spec := range specs {
+		if schedule, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", spec, schedule)
+		}
+	}
+}
+
+func TestEvery(t *testing.T) {
+	tests := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"@every 1h", utc(2024, time.January, 1, 10, 17), utc(2024, time.January, 1, 11, 0)},
+		{"@every 1h", utc(2024, time.January, 1, 11, 0), utc(2024, time.January, 1, 12, 0)},
+		{"@every 15m", utc(2024, time.January, 1, 10, 17), utc(2024, time.January, 1, 10, 30)},
+		{"@every 1s", utc(2024, time.January, 1, 10, 17).Add(500 * time.Millisecond), utc(2024, time.January, 1, 10, 17).Add(time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.spec, err)
+			}
+			if got := schedule.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Parse(%q).Next(%v) = %v, want %v", tt.spec, tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEveryAlignsSlots(t *testing.T) {
+	schedule := Every(90 * time.Minute)
+	first := schedule.Next(utc(2024, time.January, 1, 10, 17))
+
+	// Any instant inside the same slot must agree on the next one.
+	if got := schedule.Next(first.Add(-time.Minute)); !got.Equal(first) {
+		t.Errorf("Next(%v) = %v, want %v", first.Add(-time.Minute), got, first)
+	}
+	if got := schedule.Next(first); !got.Equal(first.Add(90 * time.Minute)) {
+		t.Errorf("Next(%v) = %v, want %v", first, got, first.Add(90*time.Minute))
+	}
+}
+
+func TestEveryPanicsOnNonPositiveInterval(t *testing.T) {
+	for _, d := range []time.Duration{0, -time.Second} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Every(%v) did not panic", d)
+				}
+			}()
+			Every(d)
+		}()
+	}
+}
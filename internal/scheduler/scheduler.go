@@ -0,0 +1,330 @@
+package scheduler
+
+import (
+	"MicroShopik/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// stopTimeout is how long Stop waits for running jobs before cancelling their context.
+const stopTimeout = 30 * time.Second
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobRunning  = errors.New("job is already running")
+	ErrStopped     = errors.New("scheduler is stopped")
+)
+
+// Func is the work of a job. Its context is cancelled when the scheduler stops waiting for it
+// on shutdown, so long jobs should check it between batches.
+type Func func(ctx context.Context) error
+
+type job struct {
+	name        string
+	description string
+	schedule    Schedule
+	run         Func
+	atStart     bool
+}
+
+// JobInfo describes a registered job and its most recent run.
+type JobInfo struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Schedule    string         `json:"schedule"`
+	NextRunAt   time.Time      `json:"next_run_at"`
+	LastRun     *domain.JobRun `json:"last_run"`
+}
+
+// Scheduler runs registered jobs on their schedules. Every run takes the job's lock first, so
+// with several instances each slot runs on one of them, and is recorded in job_runs.
+type Scheduler struct {
+	locker  Locker
+	runRepo domain.JobRunRepository
+	host    string
+
+	mu      sync.RWMutex
+	jobs    []*job
+	byName  map[string]*job
+	stopped bool
+
+	ctx        context.Context
+	cancel     context.CancelFunc
+	runCtx     context.Context
+	cancelRuns context.CancelFunc
+	loops      sync.WaitGroup
+	running    sync.WaitGroup
+}
+
+func NewScheduler(locker Locker, runRepo domain.JobRunRepository) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	runCtx, cancelRuns := context.WithCancel(context.Background())
+	host, _ := os.Hostname()
+
+	return &Scheduler{
+		locker:     locker,
+		runRepo:    runRepo,
+		host:       host,
+		byName:     make(map[string]*job),
+		ctx:        ctx,
+		cancel:     cancel,
+		runCtx:     runCtx,
+		cancelRuns: cancelRuns,
+	}
+}
+
+// Register adds a job. Names identify jobs across instances and in run history, so they must
+// be unique and stable. Register jobs before calling Start.
+func (s *Scheduler) Register(name, description string, schedule Schedule, run Func) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.byName[name]; ok {
+		panic(fmt.Sprintf("scheduler: job %q registered twice", name))
+	}
+
+	j := &job{name: name, description: description, schedule: schedule, run: run}
+	s.jobs = append(s.jobs, j)
+	s.byName[name] = j
+}
+
+// RunAtStart makes a registered job also run when the scheduler starts, for jobs whose output
+// is needed before their first slot. Call it before Start.
+func (s *Scheduler) RunAtStart(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.byName[name]
+	if !ok {
+		panic(fmt.Sprintf("scheduler: job %q is not registered", name))
+	}
+	j.atStart = true
+}
+
+func (s *Scheduler) Start() {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	log.Printf("Starting scheduler with %d jobs", len(s.jobs))
+	for _, j := range s.jobs {
+		s.loops.Add(1)
+		go s.loop(j)
+	}
+}
+
+// Stop ends the schedules and waits for running jobs to finish. Jobs still running after
+// stopTimeout have their context cancelled and are left behind; their runs stay "running"
+// until the next run of the job marks them as interrupted.
+func (s *Scheduler) Stop() {
+	log.Println("Stopping scheduler...")
+
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.loops.Wait()
+		s.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Println("Scheduler stopped")
+	case <-time.After(stopTimeout):
+		log.Printf("Scheduler stopped without waiting for jobs still running after %v", stopTimeout)
+	}
+	s.cancelRuns()
+}
+
+// Jobs lists the registered jobs in registration order.
+func (s *Scheduler) Jobs() ([]JobInfo, error) {
+	s.mu.RLock()
+	jobs := s.jobs
+	s.mu.RUnlock()
+
+	names := make([]string, len(jobs))
+	for i, j := range jobs {
+		names[i] = j.name
+	}
+
+	latest, err := s.runRepo.GetLatest(names)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	infos := make([]JobInfo, len(jobs))
+	for i, j := range jobs {
+		infos[i] = JobInfo{
+			Name:        j.name,
+			Description: j.description,
+			Schedule:    j.schedule.String(),
+			NextRunAt:   j.schedule.Next(now),
+			LastRun:     latest[j.name],
+		}
+	}
+	return infos, nil
+}
+
+// Runs returns the job's run history, newest first.
+func (s *Scheduler) Runs(name string, limit, offset int) ([]*domain.JobRun, int64, error) {
+	if _, err := s.job(name); err != nil {
+		return nil, 0, err
+	}
+	return s.runRepo.GetByJobName(name, limit, offset)
+}
+
+// Trigger starts the job now, outside its schedule, and returns the run without waiting for
+// it to finish. It fails with ErrJobRunning if the job is running on any instance.
+func (s *Scheduler) Trigger(name string) (*domain.JobRun, error) {
+	j, err := s.job(name)
+	if err != nil {
+		return nil, err
+	}
+
+	run, unlock, err := s.begin(j, domain.JobTriggerManual, nil)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, ErrJobRunning
+	}
+
+	started := *run
+	go s.execute(j, run, unlock)
+	return &started, nil
+}
+
+func (s *Scheduler) job(name string) (*job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	j, ok := s.byName[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return j, nil
+}
+
+// loop runs the job at each of its slots. A run that overlaps later slots makes this instance
+// skip them instead of queueing them up.
+func (s *Scheduler) loop(j *job) {
+	defer s.loops.Done()
+
+	if j.atStart {
+		run, unlock, err := s.begin(j, domain.JobTriggerStartup, nil)
+		if err != nil && !errors.Is(err, ErrStopped) {
+			log.Printf("Failed to start job %s: %v", j.name, err)
+		}
+		if run != nil {
+			s.execute(j, run, unlock)
+		}
+	}
+
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Printf("Job %s has no more runs scheduled", j.name)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		}
+
+		run, unlock, err := s.begin(j, domain.JobTriggerSchedule, &next)
+		if err != nil {
+			if !errors.Is(err, ErrStopped) {
+				log.Printf("Failed to start job %s: %v", j.name, err)
+			}
+			continue
+		}
+		if run != nil {
+			s.execute(j, run, unlock)
+		}
+	}
+}
+
+// begin takes the job's lock and records the run. It returns a nil run when the job is
+// running elsewhere or, for a scheduled run, when the slot has already run.
+func (s *Scheduler) begin(j *job, trigger string, scheduledAt *time.Time) (*domain.JobRun, func(), error) {
+	s.mu.RLock()
+	if s.stopped {
+		s.mu.RUnlock()
+		return nil, nil, ErrStopped
+	}
+	s.running.Add(1)
+	s.mu.RUnlock()
+
+	unlock, ok, err := s.locker.TryLock(s.runCtx, j.name)
+	if err != nil || !ok {
+		s.running.Done()
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if err := s.runRepo.FailRunning(j.name, "interrupted", now); err != nil {
+		log.Printf("Failed to close interrupted runs of job %s: %v", j.name, err)
+	}
+
+	run := &domain.JobRun{
+		JobName:     j.name,
+		Trigger:     trigger,
+		ScheduledAt: scheduledAt,
+		Status:      domain.JobRunStatusRunning,
+		Host:        s.host,
+		StartedAt:   now,
+	}
+	created, err := s.runRepo.Create(run)
+	if err != nil || !created {
+		unlock()
+		s.running.Done()
+		return nil, nil, err
+	}
+	return run, unlock, nil
+}
+
+func (s *Scheduler) execute(j *job, run *domain.JobRun, unlock func()) {
+	defer s.running.Done()
+	defer unlock()
+
+	err := s.call(j)
+
+	finished := time.Now()
+	run.FinishedAt = &finished
+	run.DurationMs = finished.Sub(run.StartedAt).Milliseconds()
+	run.Status = domain.JobRunStatusSucceeded
+	if err != nil {
+		run.Status = domain.JobRunStatusFailed
+		run.Error = err.Error()
+		log.Printf("Job %s failed after %v: %v", j.name, finished.Sub(run.StartedAt), err)
+	}
+
+	if err := s.runRepo.Update(run); err != nil {
+		log.Printf("Failed to record run %d of job %s: %v", run.ID, j.name, err)
+	}
+}
+
+// call runs the job, turning a panic into an error so it is recorded like any other failure.
+func (s *Scheduler) call(j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Job %s panicked: %v\n%s", j.name, r, debug.Stack())
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return j.run(s.runCtx)
+}
@@ -87,35 +87,20 @@ func (s *ConversationApplicationService) SendMessageWithValidation(conversationI
 	return s.messageService.Create(message)
 }
 
-func (s *ConversationApplicationService) GetConversationWithMessages(conversationID, userID int) (*domain.Conversation, []*domain.Message, error) {
+// GetConversationWithMessages returns the conversation together with the newest page of its history.
+// Older messages are fetched through the cursor-paginated messages endpoint.
+func (s *ConversationApplicationService) GetConversationWithMessages(conversationID, userID int) (*domain.Conversation, *domain.MessagePage, error) {
 	conversation, err := s.conversationService.GetByID(conversationID)
 	if err != nil {
 		return nil, nil, errors.New("conversation not found")
 	}
 
-	participants, err := s.participantService.GetByConversationID(conversationID)
-	if err != nil {
-		return nil, nil, errors.New("failed to get conversation participants")
-	}
-
-	isParticipant := false
-	for _, participant := range participants {
-		if participant.UserID == userID {
-			isParticipant = true
-			break
-		}
-	}
-
-	if !isParticipant {
-		return nil, nil, errors.New("user is not a participant in this conversation")
-	}
-
-	messages, err := s.messageService.GetByConversationID(conversationID)
+	page, err := s.messageService.GetPage(conversationID, userID, domain.MessageCursor{}, 0)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return conversation, messages, nil
+	return conversation, page, nil
 }
 
 func (s *ConversationApplicationService) GetUserConversationsWithLastMessage(userID int) ([]*domain.Conversation, error) {
@@ -128,22 +113,126 @@ func (s *ConversationApplicationService) GetUserConversationsWithLastMessage(use
 		return nil, err
 	}
 
+	unread, err := s.participantService.GetUnreadCounts(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	conversationIDs := make([]int, 0, len(conversations))
 	for _, conversation := range conversations {
-		messages, err := s.messageService.GetByConversationID(conversation.ID)
-		if err != nil {
-			continue
-		}
+		conversationIDs = append(conversationIDs, conversation.ID)
+	}
 
-		if len(messages) > 0 {
-			lastMessage := messages[len(messages)-1]
+	latest, err := s.messageService.GetLatestForConversations(conversationIDs)
+	if err != nil {
+		return nil, err
+	}
+	lastMessages := make(map[int]*domain.Message, len(latest))
+	for _, message := range latest {
+		lastMessages[message.ConversationID] = message
+	}
+
+	orderIDs, err := s.messageService.GetOrderIDsForConversations(conversationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, conversation := range conversations {
+		if lastMessage, ok := lastMessages[conversation.ID]; ok {
 			conversation.LastMessage = lastMessage.Content
 			conversation.LastMessageTime = &lastMessage.CreatedAt
 		}
+		if orderID, ok := orderIDs[conversation.ID]; ok {
+			conversation.OrderID = &orderID
+		}
+		conversation.UnreadCount = unread[conversation.ID]
 	}
 
 	return conversations, nil
 }
 
+// SearchMessages finds messages in the user's conversations and attaches each result's conversation,
+// so the client can show it and jump to the message.
+func (s *ConversationApplicationService) SearchMessages(params domain.MessageSearchParams) ([]*domain.MessageSearchResult, error) {
+	results, err := s.messageService.Search(params)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]bool)
+	var conversationIDs []int
+	for _, result := range results {
+		if !seen[result.ConversationID] {
+			seen[result.ConversationID] = true
+			conversationIDs = append(conversationIDs, result.ConversationID)
+		}
+	}
+
+	conversations, err := s.conversationService.GetByIDs(conversationIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*domain.Conversation, len(conversations))
+	for _, conversation := range conversations {
+		byID[conversation.ID] = conversation
+	}
+
+	for _, result := range results {
+		result.Conversation = byID[result.ConversationID]
+	}
+	return results, nil
+}
+
+// MarkConversationRead moves the user's read marker to messageID, or to the newest message when messageID is 0.
+// It returns the message ID that was marked; a marker that is already further along is left alone.
+func (s *ConversationApplicationService) MarkConversationRead(conversationID, userID, messageID int) (int, error) {
+	isParticipant, err := s.participantService.IsParticipant(conversationID, userID)
+	if err != nil {
+		return 0, err
+	}
+	if !isParticipant {
+		return 0, errors.New("user is not a participant in this conversation")
+	}
+
+	if messageID == 0 {
+		messageID, err = s.messageService.GetLatestID(conversationID)
+		if err != nil {
+			return 0, err
+		}
+		if messageID == 0 {
+			return 0, nil
+		}
+	} else {
+		message, err := s.messageService.GetByID(messageID)
+		if err != nil {
+			return 0, err
+		}
+		if message.ConversationID != conversationID {
+			return 0, errors.New("message does not belong to this conversation")
+		}
+	}
+
+	if err := s.participantService.MarkRead(conversationID, userID, messageID); err != nil {
+		return 0, err
+	}
+	return messageID, nil
+}
+
+// GetUnreadSummary returns the user's unread messages across all conversations
+// and how many conversations they are spread over.
+func (s *ConversationApplicationService) GetUnreadSummary(userID int) (int, int, error) {
+	unread, err := s.participantService.GetUnreadCounts(userID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	total := 0
+	for _, count := range unread {
+		total += count
+	}
+	return total, len(unread), nil
+}
+
 func (s *ConversationApplicationService) AddParticipantToConversation(conversationID, userID int) error {
 	_, err := s.conversationService.GetByID(conversationID)
 	if err != nil {
@@ -184,12 +273,47 @@ func (s *ConversationApplicationService) AddParticipantToConversation(conversati
 	return s.messageService.Create(systemMessage)
 }
 
-func (s *ConversationApplicationService) GetConversationsByProductID(productID int) ([]*domain.Conversation, error) {
-	if err := s.productService.ValidateProductExists(productID); err != nil {
+// StartProductInquiry opens the buyer's pre-purchase conversation with the product's seller, or
+// returns the one already started.
+func (s *ConversationApplicationService) StartProductInquiry(productID, buyerID int) (*domain.Conversation, bool, error) {
+	product, err := s.productService.GetById(productID)
+	if err != nil {
+		return nil, false, errors.New("product not found")
+	}
+	if !product.IsActive || product.ModerationStatus != domain.ProductStatusApproved {
+		return nil, false, errors.New("product is not available")
+	}
+	if product.SellerID == buyerID {
+		return nil, false, errors.New("you cannot ask about your own product")
+	}
+
+	return s.conversationService.FindOrCreateInquiry(productID, buyerID, product.SellerID)
+}
+
+// GetConversationsByProductID lists the conversations about a product, inquiries included. Only the
+// product's seller and admins may see them.
+func (s *ConversationApplicationService) GetConversationsByProductID(productID, userID int, isAdmin bool) ([]*domain.Conversation, error) {
+	product, err := s.productService.GetById(productID)
+	if err != nil {
 		return nil, errors.New("product not found")
 	}
+	if !isAdmin && product.SellerID != userID {
+		return nil, errors.New("unauthorized to view conversations of this product")
+	}
+
+	conversations, err := s.conversationService.GetByProductID(productID)
+	if err != nil {
+		return nil, err
+	}
 
-	return s.conversationService.GetByProductID(productID)
+	unread, err := s.participantService.GetUnreadCounts(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, conversation := range conversations {
+		conversation.UnreadCount = unread[conversation.ID]
+	}
+	return conversations, nil
 }
 
 func (s *ConversationApplicationService) UpdateConversation(conversation *domain.Conversation) error {
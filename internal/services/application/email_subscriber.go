@@ -0,0 +1,77 @@
+package application
+
+import (
+	"MicroShopik/internal/domain"
+	"MicroShopik/internal/events"
+	domain2 "MicroShopik/internal/services/domain"
+)
+
+// EmailSubscriber turns marketplace events into emails for the affected user.
+type EmailSubscriber struct {
+	emailService        domain2.EmailService
+	verificationService domain2.EmailVerificationService
+	orderService        domain2.OrderService
+}
+
+func NewEmailSubscriber(
+	emailService domain2.EmailService,
+	verificationService domain2.EmailVerificationService,
+	orderService domain2.OrderService,
+) *EmailSubscriber {
+	return &EmailSubscriber{
+		emailService:        emailService,
+		verificationService: verificationService,
+		orderService:        orderService,
+	}
+}
+
+// OnUserRegistered sends the verification link; the welcome email follows once it is used.
+func (s *EmailSubscriber) OnUserRegistered(event events.Event) error {
+	registered, ok := event.(events.UserRegistered)
+	if !ok {
+		return nil
+	}
+	return s.verificationService.SendVerification(registered.User)
+}
+
+// OnUserEmailChanged sends a verification link to the new address.
+func (s *EmailSubscriber) OnUserEmailChanged(event events.Event) error {
+	changed, ok := event.(events.UserEmailChanged)
+	if !ok {
+		return nil
+	}
+	return s.verificationService.SendVerification(changed.User)
+}
+
+// OnOrderChanged mails the customer when an order is placed and on every later status change.
+func (s *EmailSubscriber) OnOrderChanged(event events.Event) error {
+	var orderID int
+	var status string
+	switch e := event.(type) {
+	case events.OrderCreated:
+		orderID, status = e.Order.ID, e.Order.Status
+	case events.OrderStatusChanged:
+		orderID, status = e.Order.ID, e.NewStatus
+	default:
+		return nil
+	}
+
+	order, err := s.orderService.GetByID(orderID)
+	if err != nil {
+		return err
+	}
+	if order.Customer == nil {
+		return nil
+	}
+
+	productTitle := ""
+	if order.Product != nil {
+		productTitle = order.Product.Title
+	}
+
+	return s.emailService.Notify(order.Customer, domain.NotificationOrderStatus, "order_status", map[string]interface{}{
+		"OrderID":      order.ID,
+		"ProductTitle": productTitle,
+		"Status":       status,
+	})
+}
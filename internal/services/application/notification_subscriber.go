@@ -0,0 +1,139 @@
+package application
+
+import (
+	"MicroShopik/internal/domain"
+	"MicroShopik/internal/events"
+	domain2 "MicroShopik/internal/services/domain"
+	"fmt"
+)
+
+// NotificationSubscriber fills users' notification centers from marketplace events.
+type NotificationSubscriber struct {
+	notificationService domain2.NotificationService
+	orderService        domain2.OrderService
+	participantService  domain2.ParticipantService
+}
+
+func NewNotificationSubscriber(
+	notificationService domain2.NotificationService,
+	orderService domain2.OrderService,
+	participantService domain2.ParticipantService,
+) *NotificationSubscriber {
+	return &NotificationSubscriber{
+		notificationService: notificationService,
+		orderService:        orderService,
+		participantService:  participantService,
+	}
+}
+
+// OnOrderChanged tells the seller about a new order and the customer about every later status change.
+func (s *NotificationSubscriber) OnOrderChanged(event events.Event) error {
+	var orderID int
+	var oldStatus, status string
+	switch e := event.(type) {
+	case events.OrderCreated:
+		orderID, status = e.Order.ID, e.Order.Status
+	case events.OrderStatusChanged:
+		orderID, oldStatus, status = e.Order.ID, e.OldStatus, e.NewStatus
+	default:
+		return nil
+	}
+
+	order, err := s.orderService.GetByID(orderID)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"order_id":   order.ID,
+		"old_status": oldStatus,
+		"status":     status,
+	}
+	if order.Product != nil {
+		payload["product_title"] = order.Product.Title
+	}
+	link := fmt.Sprintf("/orders/%d", order.ID)
+
+	if oldStatus == "" {
+		if order.Product == nil {
+			return nil
+		}
+		return s.notificationService.Notify(order.Product.SellerID, domain.NotificationOrderStatus, link, payload)
+	}
+	if order.CustomerID == nil {
+		return nil
+	}
+	return s.notificationService.Notify(*order.CustomerID, domain.NotificationOrderStatus, link, payload)
+}
+
+// OnMessageSent keeps one unread notification per conversation for each recipient. System
+// messages are skipped; the events behind them notify on their own.
+func (s *NotificationSubscriber) OnMessageSent(event events.Event) error {
+	sent, ok := event.(events.MessageSent)
+	if !ok || sent.Message.IsSystem || sent.Message.SenderID == nil {
+		return nil
+	}
+	message := sent.Message
+
+	participants, err := s.participantService.GetByConversationID(message.ConversationID)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"conversation_id": message.ConversationID,
+		"message_id":      message.ID,
+	}
+	if message.Sender != nil {
+		payload["sender_name"] = message.Sender.Username
+	}
+	link := fmt.Sprintf("/conversations?conversationId=%d", message.ConversationID)
+
+	for _, participant := range participants {
+		if participant.UserID == *message.SenderID {
+			continue
+		}
+		if err := s.notificationService.NotifyLatest(participant.UserID, domain.NotificationNewMessage, link, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *NotificationSubscriber) OnProductModerated(event events.Event) error {
+	moderated, ok := event.(events.ProductModerated)
+	if !ok {
+		return nil
+	}
+	product := moderated.Product
+
+	return s.notificationService.Notify(product.SellerID, domain.NotificationProductModeration,
+		fmt.Sprintf("/seller/products/%d/edit", product.ID),
+		map[string]interface{}{
+			"product_id":    product.ID,
+			"product_title": product.Title,
+			"action":        moderated.Action,
+			"reason_code":   product.RejectionCode,
+			"reason":        product.RejectionReason,
+			"comment":       moderated.Comment,
+		})
+}
+
+// OnMessageModerated tells the sender when a moderator acted on their message. Dismissed
+// reports are not mentioned; the sender never knew about them.
+func (s *NotificationSubscriber) OnMessageModerated(event events.Event) error {
+	moderated, ok := event.(events.MessageModerated)
+	if !ok || moderated.Resolution == domain.MessageResolutionDismissed || moderated.Message.SenderID == nil {
+		return nil
+	}
+	message := moderated.Message
+
+	return s.notificationService.Notify(*message.SenderID, domain.NotificationMessageModeration,
+		fmt.Sprintf("/conversations?conversationId=%d", message.ConversationID),
+		map[string]interface{}{
+			"conversation_id": message.ConversationID,
+			"message_id":      message.ID,
+			"resolution":      moderated.Resolution,
+			"comment":         moderated.Comment,
+		})
+}
@@ -7,26 +7,20 @@ import (
 )
 
 type OrderApplicationService struct {
-	orderService        domain2.OrderService
-	productService      domain2.ProductService
-	userService         domain2.UserService
-	conversationService domain2.ConversationService
-	messageService      domain2.MessageService
+	orderService   domain2.OrderService
+	productService domain2.ProductService
+	userService    domain2.UserService
 }
 
 func NewOrderApplicationService(
 	orderService domain2.OrderService,
 	productService domain2.ProductService,
 	userService domain2.UserService,
-	conversationService domain2.ConversationService,
-	messageService domain2.MessageService,
 ) *OrderApplicationService {
 	return &OrderApplicationService{
-		orderService:        orderService,
-		productService:      productService,
-		userService:         userService,
-		conversationService: conversationService,
-		messageService:      messageService,
+		orderService:   orderService,
+		productService: productService,
+		userService:    userService,
 	}
 }
 
@@ -35,6 +29,9 @@ func (s *OrderApplicationService) CreateOrder(order *domain.Order) error {
 		if err := s.userService.ValidateUserExists(*order.CustomerID); err != nil {
 			return errors.New("customer not found")
 		}
+		if err := s.userService.ValidateEmailVerified(*order.CustomerID); err != nil {
+			return err
+		}
 	}
 
 	if order.ProductID != nil {
@@ -62,17 +59,7 @@ func (s *OrderApplicationService) ProcessOrder(orderID int) error {
 		}
 	}
 
-	if err := s.orderService.UpdateStatus(orderID, "completed"); err != nil {
-		return err
-	}
-
-	if order.CustomerID != nil && order.ProductID != nil {
-		if err := s.createOrderConversation(order); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return s.orderService.UpdateStatus(orderID, "completed")
 }
 
 func (s *OrderApplicationService) CancelOrder(orderID int, customerID int) error {
@@ -136,31 +123,3 @@ func (s *OrderApplicationService) GetOrderByID(orderID int) (*domain.Order, erro
 func (s *OrderApplicationService) UpdateOrderStatus(orderID int, status string) error {
 	return s.orderService.UpdateStatus(orderID, status)
 }
-
-func (s *OrderApplicationService) createOrderConversation(order *domain.Order) error {
-	if order.ProductID == nil || order.CustomerID == nil {
-		return nil
-	}
-
-	product, err := s.productService.GetById(*order.ProductID)
-	if err != nil {
-		return err
-	}
-
-	conversation := &domain.Conversation{}
-	participantIDs := []int{*order.CustomerID, product.SellerID}
-
-	if err := s.conversationService.Create(conversation, participantIDs); err != nil {
-		return err
-	}
-
-	if err := s.messageService.SendSystemMessage(
-		conversation.ID,
-		"Order has been processed successfully. You can now discuss delivery details.",
-		&order.ID,
-	); err != nil {
-		return err
-	}
-
-	return nil
-}
@@ -0,0 +1,87 @@
+package application
+
+import (
+	"MicroShopik/internal/events"
+	domain2 "MicroShopik/internal/services/domain"
+	"fmt"
+)
+
+// OrderChatSubscriber posts the order card to the buyer–seller chat once an order is processed,
+// reusing an existing conversation where possible, and links the order to it.
+type OrderChatSubscriber struct {
+	orderService        domain2.OrderService
+	productService      domain2.ProductService
+	conversationService domain2.ConversationService
+	messageService      domain2.MessageService
+}
+
+func NewOrderChatSubscriber(
+	orderService domain2.OrderService,
+	productService domain2.ProductService,
+	conversationService domain2.ConversationService,
+	messageService domain2.MessageService,
+) *OrderChatSubscriber {
+	return &OrderChatSubscriber{
+		orderService:        orderService,
+		productService:      productService,
+		conversationService: conversationService,
+		messageService:      messageService,
+	}
+}
+
+// OnOrderStatusChanged runs from the outbox relay and may see the same event more than once,
+// so it reloads the order, skips orders already linked to a chat and does not post the card
+// again when an earlier attempt posted it but failed to link the order.
+func (s *OrderChatSubscriber) OnOrderStatusChanged(event events.Event) error {
+	changed, ok := event.(events.OrderStatusChanged)
+	if !ok || changed.NewStatus != "completed" {
+		return nil
+	}
+
+	order, err := s.orderService.GetByID(changed.Order.ID)
+	if err != nil {
+		return err
+	}
+	if order.ProductID == nil || order.CustomerID == nil || order.ConversationID != nil {
+		return nil
+	}
+
+	product, err := s.productService.GetById(*order.ProductID)
+	if err != nil {
+		return err
+	}
+
+	conversation, err := s.conversationService.FindOrCreateForOrder(product.ID, *order.CustomerID, product.SellerID)
+	if err != nil {
+		return err
+	}
+
+	posted, err := s.cardPosted(order.ID, conversation.ID)
+	if err != nil {
+		return err
+	}
+	if !posted {
+		if err := s.messageService.SendSystemMessage(
+			conversation.ID,
+			fmt.Sprintf("Order #%d for \"%s\" has been processed successfully. You can now discuss delivery details.", order.ID, product.Title),
+			&order.ID,
+		); err != nil {
+			return err
+		}
+	}
+
+	return s.orderService.SetConversationID(order.ID, conversation.ID)
+}
+
+func (s *OrderChatSubscriber) cardPosted(orderID, conversationID int) (bool, error) {
+	messages, err := s.messageService.GetByOrderID(orderID)
+	if err != nil {
+		return false, err
+	}
+	for _, message := range messages {
+		if message.IsSystem && message.ConversationID == conversationID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
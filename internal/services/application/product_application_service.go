@@ -2,15 +2,22 @@ package application
 
 import (
 	"MicroShopik/internal/domain"
+	"MicroShopik/internal/events"
 	domain2 "MicroShopik/internal/services/domain"
+	"encoding/json"
 	"errors"
+	"log"
 )
 
 type ProductApplicationService struct {
-	productService  domain2.ProductService
-	categoryService domain2.CategoryService
-	userService     domain2.UserService
-	orderService    domain2.OrderService
+	productService        domain2.ProductService
+	categoryService       domain2.CategoryService
+	userService           domain2.UserService
+	orderService          domain2.OrderService
+	moderationService     domain2.ProductModerationService
+	recommendationService domain2.RecommendationService
+	slugService           domain2.SlugService
+	events                events.EventBus
 }
 
 func NewProductApplicationService(
@@ -18,19 +25,35 @@ func NewProductApplicationService(
 	categoryService domain2.CategoryService,
 	userService domain2.UserService,
 	orderService domain2.OrderService,
+	moderationService domain2.ProductModerationService,
+	recommendationService domain2.RecommendationService,
+	slugService domain2.SlugService,
+	eventBus events.EventBus,
 ) *ProductApplicationService {
 	return &ProductApplicationService{
-		productService:  productService,
-		categoryService: categoryService,
-		userService:     userService,
-		orderService:    orderService,
+		productService:        productService,
+		categoryService:       categoryService,
+		userService:           userService,
+		orderService:          orderService,
+		moderationService:     moderationService,
+		recommendationService: recommendationService,
+		slugService:           slugService,
+		events:                eventBus,
 	}
 }
 
-func (s *ProductApplicationService) CreateProductWithValidation(product *domain.Product, sellerID int) error {
+// ValidateSeller checks that the seller exists and may list products.
+func (s *ProductApplicationService) ValidateSeller(sellerID int) error {
 	if err := s.userService.ValidateUserExists(sellerID); err != nil {
 		return errors.New("seller not found")
 	}
+	return s.userService.ValidateEmailVerified(sellerID)
+}
+
+func (s *ProductApplicationService) CreateProductWithValidation(product *domain.Product, sellerID int) error {
+	if err := s.ValidateSeller(sellerID); err != nil {
+		return err
+	}
 
 	if err := s.categoryService.ValidateCategoryExists(product.CategoryID); err != nil {
 		return errors.New("category not found")
@@ -39,12 +62,28 @@ func (s *ProductApplicationService) CreateProductWithValidation(product *domain.
 	product.SellerID = sellerID
 	product.IsActive = true
 	product.SoldCount = 0
+	product.ModerationStatus = domain.ProductStatusApproved
+	product.RejectionCode = ""
+	product.RejectionReason = ""
+
+	if s.moderationService.IsEnabled() {
+		product.IsActive = false
+		product.ModerationStatus = domain.ProductStatusPendingReview
+	}
 
 	id, err := s.productService.Create(product, sellerID)
 	if err != nil {
 		return err
 	}
 	product.ID = id
+
+	if s.moderationService.IsEnabled() {
+		if err := s.moderationService.Submit(id, sellerID, domain.ModerationActionSubmitted); err != nil {
+			return err
+		}
+	}
+
+	s.events.Publish(events.ProductCreated{Product: product})
 	return nil
 }
 
@@ -58,12 +97,190 @@ func (s *ProductApplicationService) UpdateProductWithInventoryCheck(product *dom
 		return errors.New("unauthorized to update this product")
 	}
 
+	product.SellerID = sellerID
+	return s.saveProductUpdate(existingProduct, product, sellerID, func() error {
+		return s.productService.Update(product.ID, product, sellerID)
+	})
+}
+
+// saveProductUpdate checks a seller's edit of existingProduct, stores it with write and sends it
+// back to moderation if needed. write must store the fields of product that were edited.
+func (s *ProductApplicationService) saveProductUpdate(existingProduct, product *domain.Product, sellerID int, write func() error) error {
 	if err := s.categoryService.ValidateCategoryExists(product.CategoryID); err != nil {
 		return errors.New("category not found")
 	}
 
-	product.SellerID = sellerID
-	return s.productService.Update(product.ID, product, sellerID)
+	// Only approved products can be switched on by the seller.
+	if existingProduct.ModerationStatus != domain.ProductStatusApproved {
+		product.IsActive = false
+	}
+
+	requiresReview := existingProduct.ModerationStatus == domain.ProductStatusApproved &&
+		s.moderationService.RequiresReview(existingProduct, product)
+
+	if err := write(); err != nil {
+		return err
+	}
+
+	if requiresReview {
+		if err := s.moderationService.Submit(product.ID, sellerID, domain.ModerationActionSubmitted); err != nil {
+			return err
+		}
+	}
+
+	s.events.Publish(events.ProductUpdated{Product: product, Previous: existingProduct})
+	return nil
+}
+
+func (s *ProductApplicationService) ResubmitProductForReview(productID, sellerID int) error {
+	return s.moderationService.Resubmit(productID, sellerID)
+}
+
+func (s *ProductApplicationService) GetModerationQueue(limit, offset int) ([]*domain.Product, int, error) {
+	return s.moderationService.GetQueue(limit, offset)
+}
+
+func (s *ProductApplicationService) ApproveProduct(productID, moderatorID int, comment string) error {
+	if err := s.moderationService.Approve(productID, moderatorID, comment); err != nil {
+		return err
+	}
+
+	s.publishModerated(productID, domain.ModerationActionApproved, comment)
+	return nil
+}
+
+func (s *ProductApplicationService) RejectProduct(productID, moderatorID int, reasonCode, comment string) error {
+	if err := s.moderationService.Reject(productID, moderatorID, reasonCode, comment); err != nil {
+		return err
+	}
+
+	s.publishModerated(productID, domain.ModerationActionRejected, comment)
+	return nil
+}
+
+// publishModerated reloads the product so subscribers see the decision as stored.
+func (s *ProductApplicationService) publishModerated(productID int, action, comment string) {
+	product, err := s.productService.GetById(productID)
+	if err != nil {
+		log.Printf("Failed to load product %d after moderation: %v", productID, err)
+		return
+	}
+	s.events.Publish(events.ProductModerated{Product: product, Action: action, Comment: comment})
+}
+
+func (s *ProductApplicationService) GetModerationHistory(productID, userID int, isAdmin bool) ([]*domain.ProductModerationLog, error) {
+	product, err := s.productService.GetById(productID)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	if !isAdmin && product.SellerID != userID {
+		return nil, errors.New("unauthorized to view moderation history of this product")
+	}
+
+	return s.moderationService.GetHistory(productID)
+}
+
+func (s *ProductApplicationService) GetProductRevisions(productID, userID int, isAdmin bool) ([]*domain.ProductRevision, error) {
+	product, err := s.productService.GetById(productID)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	if !isAdmin && product.SellerID != userID {
+		return nil, errors.New("unauthorized to view revisions of this product")
+	}
+
+	return s.productService.GetRevisions(productID)
+}
+
+// RollbackProductToRevision restores the product to the state right after the given revision was
+// applied. Revisions only store diffs, so every field changed later is reset to the value it had
+// before its first later change. Restored fields are written as stored, so zero values such as
+// max_sales = 0 apply too, and go through the same checks and moderation as a regular update.
+func (s *ProductApplicationService) RollbackProductToRevision(productID, revisionID, sellerID int) error {
+	existingProduct, err := s.productService.GetById(productID)
+	if err != nil {
+		return errors.New("product not found")
+	}
+
+	if existingProduct.SellerID != sellerID {
+		return errors.New("unauthorized to roll back this product")
+	}
+
+	revisions, err := s.productService.GetRevisions(productID)
+	if err != nil {
+		return err
+	}
+
+	product := *existingProduct
+	data, err := revertProduct(&product, revisions, revisionID)
+	if err != nil {
+		return err
+	}
+
+	return s.saveProductUpdate(existingProduct, &product, sellerID, func() error {
+		return s.productService.UpdateFields(productID, data, sellerID)
+	})
+}
+
+// revertProduct undoes on product every change recorded after revisionID, given the product's
+// revisions newest first. The returned data points at the restored fields of product.
+func revertProduct(product *domain.Product, revisions []*domain.ProductRevision, revisionID int) (domain.ProductUpdateData, error) {
+	data := domain.ProductUpdateData{}
+	found := false
+	restored := make(map[string]json.RawMessage)
+	// Revisions are ordered newest first, so the last write for a field wins with the earliest later change.
+	for _, revision := range revisions {
+		if revision.ID == revisionID {
+			found = true
+			break
+		}
+
+		var changes map[string]struct {
+			Old json.RawMessage `json:"old"`
+		}
+		if err := json.Unmarshal(revision.Changes, &changes); err != nil {
+			return data, errors.New("failed to read revision history")
+		}
+		for field, change := range changes {
+			restored[field] = change.Old
+		}
+	}
+
+	if !found {
+		return data, errors.New("revision not found")
+	}
+	if len(restored) == 0 {
+		return data, errors.New("product already matches this revision")
+	}
+
+	for field, value := range restored {
+		var target interface{}
+		switch field {
+		case "title":
+			target, data.Title = &product.Title, &product.Title
+		case "description":
+			target, data.Description = &product.Description, &product.Description
+		case "price":
+			target, data.Price = &product.Price, &product.Price
+		case "category_id":
+			target, data.CategoryID = &product.CategoryID, &product.CategoryID
+		case "is_active":
+			target, data.IsActive = &product.IsActive, &product.IsActive
+		case "disposable":
+			target, data.Disposable = &product.Disposable, &product.Disposable
+		case "max_sales":
+			target, data.MaxSales = &product.MaxSales, &product.MaxSales
+		default:
+			continue
+		}
+		if err := json.Unmarshal(value, target); err != nil {
+			return data, errors.New("failed to read revision history")
+		}
+	}
+
+	return data, nil
 }
 
 func (s *ProductApplicationService) DeactivateProductWithOrderCheck(productID, sellerID int) error {
@@ -133,6 +350,39 @@ func (s *ProductApplicationService) GetProductWithFullDetails(productID int) (*d
 	return product, nil
 }
 
+// GetProductBySlug looks up a product by its current slug. When the slug is an old one,
+// the product's current slug is returned instead so the caller can redirect.
+func (s *ProductApplicationService) GetProductBySlug(slug string) (*domain.Product, string, error) {
+	product, err := s.productService.GetBySlug(slug)
+	if err == nil {
+		category, err := s.categoryService.GetByID(product.CategoryID)
+		if err != nil {
+			return nil, "", err
+		}
+
+		product.Category = *category
+		return product, "", nil
+	}
+
+	redirect, redirectErr := s.slugService.GetRedirect(domain.SlugEntityProduct, slug)
+	if redirectErr != nil {
+		return nil, "", err
+	}
+
+	product, err = s.productService.GetById(redirect.EntityID)
+	if err != nil {
+		return nil, "", err
+	}
+	if product.Slug == nil {
+		return nil, "", errors.New("product not found")
+	}
+	return nil, *product.Slug, nil
+}
+
+func (s *ProductApplicationService) GetRelatedProducts(productID, limit int) ([]*domain.Product, error) {
+	return s.recommendationService.GetRelated(productID, limit)
+}
+
 func (s *ProductApplicationService) ValidateProductForPurchase(productID, customerID int) error {
 	product, err := s.productService.GetById(productID)
 	if err != nil {
@@ -0,0 +1,100 @@
+package application
+
+import (
+	"MicroShopik/internal/domain"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func revision(id int, changes string) *domain.ProductRevision {
+	return &domain.ProductRevision{ID: id, ProductID: 1, Changes: json.RawMessage(changes)}
+}
+
+// updatedFields lists the fields set in data, in declaration order.
+func updatedFields(data domain.ProductUpdateData) []string {
+	var fields []string
+	v := reflect.ValueOf(data)
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Field(i).IsNil() {
+			fields = append(fields, v.Type().Field(i).Name)
+		}
+	}
+	return fields
+}
+
+func TestRevertProduct(t *testing.T) {
+	current := domain.Product{ID: 1, Title: "C", Description: "desc", Price: 300, CategoryID: 2, IsActive: true}
+	// Newest first, as the repository returns them.
+	history := []*domain.ProductRevision{
+		revision(4, `{"title":{"old":"B","new":"C"},"price":{"old":200,"new":300}}`),
+		revision(3, `{"is_active":{"old":false,"new":true},"sku":{"old":null,"new":"X-1"}}`),
+		revision(2, `{"title":{"old":"A","new":"B"},"category_id":{"old":1,"new":2}}`),
+		revision(1, `{"description":{"old":"","new":"desc"}}`),
+	}
+
+	tests := []struct {
+		name       string
+		revisionID int
+		want       domain.Product
+		fields     []string
+	}{
+		{
+			name:       "one revision back",
+			revisionID: 3,
+			want:       domain.Product{ID: 1, Title: "B", Description: "desc", Price: 200, CategoryID: 2, IsActive: true},
+			fields:     []string{"Title", "Price"},
+		},
+		{
+			name:       "unknown fields are skipped",
+			revisionID: 2,
+			want:       domain.Product{ID: 1, Title: "B", Description: "desc", Price: 200, CategoryID: 2, IsActive: false},
+			fields:     []string{"Title", "Price", "IsActive"},
+		},
+		{
+			name:       "earliest later change wins",
+			revisionID: 1,
+			want:       domain.Product{ID: 1, Title: "A", Description: "desc", Price: 200, CategoryID: 1, IsActive: false},
+			fields:     []string{"Title", "Price", "CategoryID", "IsActive"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := current
+			data, err := revertProduct(&product, history, tt.revisionID)
+			if err != nil {
+				t.Fatalf("revertProduct(%d) returned error: %v", tt.revisionID, err)
+			}
+			if !reflect.DeepEqual(product, tt.want) {
+				t.Errorf("revertProduct(%d) product = %+v, want %+v", tt.revisionID, product, tt.want)
+			}
+			if got := updatedFields(data); !reflect.DeepEqual(got, tt.fields) {
+				t.Errorf("revertProduct(%d) updates %v, want %v", tt.revisionID, got, tt.fields)
+			}
+		})
+	}
+}
+
+func TestRevertProductErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		history    []*domain.ProductRevision
+		revisionID int
+		want       string
+	}{
+		{"latest revision", []*domain.ProductRevision{revision(2, `{"title":{"old":"A","new":"B"}}`)}, 2, "product already matches this revision"},
+		{"unknown revision", []*domain.ProductRevision{revision(2, `{"title":{"old":"A","new":"B"}}`)}, 7, "revision not found"},
+		{"broken changes", []*domain.ProductRevision{revision(2, `[1, 2]`), revision(1, `{}`)}, 1, "failed to read revision history"},
+		{"mistyped value", []*domain.ProductRevision{revision(2, `{"price":{"old":"cheap","new":300}}`), revision(1, `{}`)}, 1, "failed to read revision history"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := domain.Product{ID: 1, Title: "B", Price: 300}
+			if _, err := revertProduct(&product, tt.history, tt.revisionID); err == nil || err.Error() != tt.want {
+				t.Errorf("revertProduct(%d) error = %v, want %q", tt.revisionID, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,537 @@
+package application
+
+import (
+	"MicroShopik/internal/domain"
+	"MicroShopik/internal/jobqueue"
+	domain2 "MicroShopik/internal/services/domain"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatJSON = "json"
+)
+
+// ProductImportJobType is the queued job that applies a large import.
+const ProductImportJobType = "product_import"
+
+// ProductImportPayload carries the uploaded file to the worker, which parses it again.
+type ProductImportPayload struct {
+	ImportJobID int    `json:"import_job_id"`
+	Format      string `json:"format"`
+	Data        []byte `json:"data"`
+}
+
+var productImportColumns = []string{"sku", "title", "description", "price", "category_id", "disposable", "max_sales", "is_active"}
+
+type ProductImportResult struct {
+	DryRun  bool                            `json:"dry_run"`
+	Total   int                             `json:"total"`
+	Created int                             `json:"created"`
+	Updated int                             `json:"updated"`
+	Failed  int                             `json:"failed"`
+	Rows    []domain.ProductImportRowResult `json:"rows"`
+}
+
+type productExportRow struct {
+	ID               int    `json:"id"`
+	SKU              string `json:"sku"`
+	Title            string `json:"title"`
+	Description      string `json:"description"`
+	Price            int64  `json:"price"`
+	CategoryID       int    `json:"category_id"`
+	Disposable       bool   `json:"disposable"`
+	MaxSales         int    `json:"max_sales"`
+	IsActive         bool   `json:"is_active"`
+	SoldCount        int    `json:"sold_count"`
+	ModerationStatus string `json:"moderation_status"`
+}
+
+// importRow keeps the parse errors of a row so they can be reported next to validation errors.
+type importRow struct {
+	domain.ProductImportRow
+	parseErrors []string
+}
+
+type ProductImportApplicationService struct {
+	productAppService *ProductApplicationService
+	productService    domain2.ProductService
+	categoryService   domain2.CategoryService
+	jobRepo           domain.ProductImportJobRepository
+	queue             *jobqueue.Queue
+	asyncThreshold    int
+}
+
+func NewProductImportApplicationService(
+	productAppService *ProductApplicationService,
+	productService domain2.ProductService,
+	categoryService domain2.CategoryService,
+	jobRepo domain.ProductImportJobRepository,
+	queue *jobqueue.Queue,
+	asyncThreshold int,
+) *ProductImportApplicationService {
+	return &ProductImportApplicationService{
+		productAppService: productAppService,
+		productService:    productService,
+		categoryService:   categoryService,
+		jobRepo:           jobRepo,
+		queue:             queue,
+		asyncThreshold:    asyncThreshold,
+	}
+}
+
+// ImportProducts validates and applies an import file. Imports larger than the async threshold
+// are not applied inline; a job is returned instead and its progress can be polled.
+func (s *ProductImportApplicationService) ImportProducts(sellerID int, format string, data []byte, dryRun bool) (*ProductImportResult, *domain.ProductImportJob, error) {
+	rows, err := parseProductImport(format, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil, errors.New("import file contains no rows")
+	}
+	if !dryRun {
+		if err := s.productAppService.ValidateSeller(sellerID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if !dryRun && s.asyncThreshold > 0 && len(rows) > s.asyncThreshold {
+		job := &domain.ProductImportJob{
+			SellerID:  sellerID,
+			Format:    format,
+			Status:    domain.ImportJobStatusQueued,
+			TotalRows: len(rows),
+		}
+		if err := s.jobRepo.Create(job); err != nil {
+			return nil, nil, err
+		}
+
+		// Imports are not idempotent for rows without a SKU, so the job gets a single attempt.
+		// If it dies, ImportJobDied fails the import.
+		payload := ProductImportPayload{ImportJobID: job.ID, Format: format, Data: data}
+		_, _, err := s.queue.Enqueue(ProductImportJobType, payload, jobqueue.Options{
+			MaxAttempts: 1,
+			UniqueKey:   fmt.Sprintf("product_import:%d", job.ID),
+		})
+		if err != nil {
+			s.finishJob(job, domain.ImportJobStatusFailed, nil)
+			return nil, nil, err
+		}
+		return nil, job, nil
+	}
+
+	return s.processRows(sellerID, rows, dryRun, nil), nil, nil
+}
+
+func (s *ProductImportApplicationService) GetImportJob(jobID, sellerID int) (*domain.ProductImportJob, error) {
+	job, err := s.jobRepo.GetByID(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.SellerID != sellerID {
+		return nil, errors.New("import job not found")
+	}
+	return job, nil
+}
+
+func (s *ProductImportApplicationService) ExportProducts(sellerID int, format string, w io.Writer) error {
+	products, err := s.productService.Find(domain.ProductQueryParams{SellerId: &sellerID})
+	if err != nil {
+		return err
+	}
+
+	rows := make([]productExportRow, 0, len(products))
+	for _, p := range products {
+		row := productExportRow{
+			ID:               p.ID,
+			Title:            p.Title,
+			Description:      p.Description,
+			Price:            p.Price,
+			CategoryID:       p.CategoryID,
+			Disposable:       p.Disposable,
+			MaxSales:         p.MaxSales,
+			IsActive:         p.IsActive,
+			SoldCount:        p.SoldCount,
+			ModerationStatus: p.ModerationStatus,
+		}
+		if p.SKU != nil {
+			row.SKU = *p.SKU
+		}
+		rows = append(rows, row)
+	}
+
+	switch format {
+	case ImportFormatJSON:
+		return json.NewEncoder(w).Encode(rows)
+	case ImportFormatCSV:
+		writer := csv.NewWriter(w)
+		header := append([]string{"id"}, productImportColumns...)
+		header = append(header, "sold_count", "moderation_status")
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			record := []string{
+				strconv.Itoa(row.ID),
+				row.SKU,
+				row.Title,
+				row.Description,
+				strconv.FormatInt(row.Price, 10),
+				strconv.Itoa(row.CategoryID),
+				strconv.FormatBool(row.Disposable),
+				strconv.Itoa(row.MaxSales),
+				strconv.FormatBool(row.IsActive),
+				strconv.Itoa(row.SoldCount),
+				row.ModerationStatus,
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return errors.New("unsupported export format")
+	}
+}
+
+// RunImportJob is the queue handler of ProductImportJobType.
+func (s *ProductImportApplicationService) RunImportJob(ctx context.Context, payload ProductImportPayload) error {
+	job, err := s.jobRepo.GetByID(payload.ImportJobID)
+	if err != nil {
+		return err
+	}
+	if job.Status != domain.ImportJobStatusQueued {
+		log.Printf("Skipping product import job %d in status %s", job.ID, job.Status)
+		return nil
+	}
+
+	rows, err := parseProductImport(payload.Format, payload.Data)
+	if err != nil {
+		s.finishJob(job, domain.ImportJobStatusFailed, nil)
+		return err
+	}
+
+	s.runImportJob(job, rows)
+	return nil
+}
+
+// ImportJobDied is the dead handler of ProductImportJobType. It fails the import when its queued
+// job can't run any more, e.g. because the worker died halfway, so pollers see it settle.
+func (s *ProductImportApplicationService) ImportJobDied(payload ProductImportPayload, queued *domain.QueuedJob) {
+	job, err := s.jobRepo.GetByID(payload.ImportJobID)
+	if err != nil {
+		log.Printf("Failed to load product import job %d of dead queued job %d: %v", payload.ImportJobID, queued.ID, err)
+		return
+	}
+	if job.Status == domain.ImportJobStatusCompleted || job.Status == domain.ImportJobStatusFailed {
+		return
+	}
+
+	log.Printf("Product import job %d failed: queued job %d is %s: %s", job.ID, queued.ID, queued.Status, queued.LastError)
+	s.finishJob(job, domain.ImportJobStatusFailed, nil)
+}
+
+func (s *ProductImportApplicationService) runImportJob(job *domain.ProductImportJob, rows []importRow) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Product import job %d panicked: %v", job.ID, r)
+			s.finishJob(job, domain.ImportJobStatusFailed, nil)
+		}
+	}()
+
+	job.Status = domain.ImportJobStatusRunning
+	if err := s.jobRepo.Update(job); err != nil {
+		log.Printf("Failed to start product import job %d: %v", job.ID, err)
+	}
+
+	result := s.processRows(job.SellerID, rows, false, func(partial *ProductImportResult) {
+		job.ProcessedRows = len(partial.Rows)
+		job.CreatedCount = partial.Created
+		job.UpdatedCount = partial.Updated
+		job.FailedCount = partial.Failed
+		if err := s.jobRepo.Update(job); err != nil {
+			log.Printf("Failed to update progress of product import job %d: %v", job.ID, err)
+		}
+	})
+
+	s.finishJob(job, domain.ImportJobStatusCompleted, result)
+}
+
+func (s *ProductImportApplicationService) finishJob(job *domain.ProductImportJob, status string, result *ProductImportResult) {
+	now := time.Now()
+	job.Status = status
+	job.FinishedAt = &now
+
+	if result != nil {
+		job.ProcessedRows = len(result.Rows)
+		job.CreatedCount = result.Created
+		job.UpdatedCount = result.Updated
+		job.FailedCount = result.Failed
+
+		var failedRows []domain.ProductImportRowResult
+		for _, row := range result.Rows {
+			if row.Action == domain.ImportRowActionError {
+				failedRows = append(failedRows, row)
+			}
+		}
+		if len(failedRows) > 0 {
+			if encoded, err := json.Marshal(failedRows); err == nil {
+				job.Errors = encoded
+			}
+		}
+	}
+
+	if err := s.jobRepo.Update(job); err != nil {
+		log.Printf("Failed to finish product import job %d: %v", job.ID, err)
+	}
+}
+
+func (s *ProductImportApplicationService) processRows(sellerID int, rows []importRow, dryRun bool, progress func(*ProductImportResult)) *ProductImportResult {
+	result := &ProductImportResult{DryRun: dryRun, Total: len(rows)}
+	categories := make(map[int]bool)
+	seenSKUs := make(map[string]int)
+
+	for i, row := range rows {
+		rowResult := s.processRow(sellerID, row, dryRun, categories, seenSKUs)
+		switch rowResult.Action {
+		case domain.ImportRowActionCreate:
+			result.Created++
+		case domain.ImportRowActionUpdate:
+			result.Updated++
+		default:
+			result.Failed++
+		}
+		result.Rows = append(result.Rows, rowResult)
+
+		if progress != nil && (i+1)%25 == 0 {
+			progress(result)
+		}
+	}
+
+	return result
+}
+
+func (s *ProductImportApplicationService) processRow(sellerID int, row importRow, dryRun bool, categories map[int]bool, seenSKUs map[string]int) domain.ProductImportRowResult {
+	rowResult := domain.ProductImportRowResult{Row: row.Row, SKU: row.SKU}
+	errs := append([]string{}, row.parseErrors...)
+
+	if strings.TrimSpace(row.Title) == "" {
+		errs = append(errs, "title is required")
+	} else if len(row.Title) > 255 {
+		errs = append(errs, "title must be at most 255 characters")
+	}
+	if strings.TrimSpace(row.Description) == "" {
+		errs = append(errs, "description is required")
+	}
+	if row.Price <= 0 {
+		errs = append(errs, "price must be greater than zero")
+	}
+	if row.MaxSales < 0 {
+		errs = append(errs, "max_sales cannot be negative")
+	}
+	if len(row.SKU) > 100 {
+		errs = append(errs, "sku must be at most 100 characters")
+	}
+
+	if row.CategoryID <= 0 {
+		errs = append(errs, "category_id is required")
+	} else {
+		exists, checked := categories[row.CategoryID]
+		if !checked {
+			exists = s.categoryService.ValidateCategoryExists(row.CategoryID) == nil
+			categories[row.CategoryID] = exists
+		}
+		if !exists {
+			errs = append(errs, fmt.Sprintf("category %d does not exist", row.CategoryID))
+		}
+	}
+
+	var existing *domain.Product
+	if row.SKU != "" {
+		if firstRow, duplicate := seenSKUs[row.SKU]; duplicate {
+			errs = append(errs, fmt.Sprintf("sku is already used on row %d", firstRow))
+		} else {
+			seenSKUs[row.SKU] = row.Row
+		}
+		existing, _ = s.productService.GetBySellerAndSKU(sellerID, row.SKU)
+	}
+
+	if len(errs) > 0 {
+		rowResult.Action = domain.ImportRowActionError
+		rowResult.Errors = errs
+		return rowResult
+	}
+
+	rowResult.Action = domain.ImportRowActionCreate
+	if existing != nil {
+		rowResult.Action = domain.ImportRowActionUpdate
+		rowResult.ProductID = existing.ID
+	}
+	if dryRun {
+		return rowResult
+	}
+
+	product := &domain.Product{
+		Title:       row.Title,
+		Description: row.Description,
+		Price:       row.Price,
+		CategoryID:  row.CategoryID,
+		Disposable:  row.Disposable,
+		MaxSales:    row.MaxSales,
+	}
+	if row.SKU != "" {
+		sku := row.SKU
+		product.SKU = &sku
+	}
+
+	var err error
+	if existing != nil {
+		product.ID = existing.ID
+		product.IsActive = existing.IsActive
+		if row.IsActive != nil {
+			product.IsActive = *row.IsActive
+		}
+		err = s.productAppService.UpdateProductWithInventoryCheck(product, sellerID)
+	} else {
+		err = s.productAppService.CreateProductWithValidation(product, sellerID)
+		rowResult.ProductID = product.ID
+	}
+
+	if err != nil {
+		rowResult.Action = domain.ImportRowActionError
+		rowResult.Errors = []string{err.Error()}
+	}
+	return rowResult
+}
+
+func parseProductImport(format string, data []byte) ([]importRow, error) {
+	switch format {
+	case ImportFormatCSV:
+		return parseProductImportCSV(data)
+	case ImportFormatJSON:
+		return parseProductImportJSON(data)
+	default:
+		return nil, errors.New("unsupported import format, use csv or json")
+	}
+}
+
+func parseProductImportCSV(data []byte) ([]importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"title", "description", "price", "category_id"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv header is missing required column %q", required)
+		}
+	}
+
+	var rows []importRow
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv line %d: %w", line, err)
+		}
+
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := importRow{ProductImportRow: domain.ProductImportRow{
+			Row:         line,
+			SKU:         value("sku"),
+			Title:       value("title"),
+			Description: value("description"),
+		}}
+
+		if v := value("price"); v != "" {
+			price, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				row.parseErrors = append(row.parseErrors, "price must be an integer amount in cents")
+			}
+			row.Price = price
+		}
+		if v := value("category_id"); v != "" {
+			categoryID, err := strconv.Atoi(v)
+			if err != nil {
+				row.parseErrors = append(row.parseErrors, "category_id must be an integer")
+			}
+			row.CategoryID = categoryID
+		}
+		if v := value("max_sales"); v != "" {
+			maxSales, err := strconv.Atoi(v)
+			if err != nil {
+				row.parseErrors = append(row.parseErrors, "max_sales must be an integer")
+			}
+			row.MaxSales = maxSales
+		}
+		if v := value("disposable"); v != "" {
+			disposable, err := strconv.ParseBool(v)
+			if err != nil {
+				row.parseErrors = append(row.parseErrors, "disposable must be true or false")
+			}
+			row.Disposable = disposable
+		}
+		if v := value("is_active"); v != "" {
+			isActive, err := strconv.ParseBool(v)
+			if err != nil {
+				row.parseErrors = append(row.parseErrors, "is_active must be true or false")
+			} else {
+				row.IsActive = &isActive
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseProductImportJSON(data []byte) ([]importRow, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("import file must be a JSON array of products: %w", err)
+	}
+
+	rows := make([]importRow, 0, len(items))
+	for i, item := range items {
+		row := importRow{}
+		if err := json.Unmarshal(item, &row.ProductImportRow); err != nil {
+			row.parseErrors = append(row.parseErrors, "invalid product object: "+err.Error())
+		}
+		row.Row = i + 1
+		row.SKU = strings.TrimSpace(row.SKU)
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
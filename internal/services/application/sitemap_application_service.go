@@ -0,0 +1,142 @@
+package application
+
+import (
+	"MicroShopik/internal/domain"
+	domain2 "MicroShopik/internal/services/domain"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type SitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type SitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+type SitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Sitemaps []SitemapURL `xml:"sitemap"`
+}
+
+// sitemapSections lists what goes into the sitemap, in order, with the storefront path prefix.
+var sitemapSections = []struct {
+	entityType string
+	name       string
+	path       string
+}{
+	{domain.SlugEntityProduct, "products", "/products/"},
+	{domain.SlugEntityCategory, "categories", "/categories/"},
+}
+
+// SitemapApplicationService builds the sitemap. Page entries point at the storefront (siteURL);
+// the sitemap files themselves are served by this API, so the index points at apiURL.
+type SitemapApplicationService struct {
+	slugService domain2.SlugService
+	baseURL     string
+	apiURL      string
+	pageSize    int
+}
+
+func NewSitemapApplicationService(slugService domain2.SlugService, siteURL, apiURL string, pageSize int) *SitemapApplicationService {
+	if pageSize <= 0 || pageSize > 50000 {
+		pageSize = 50000 // limit from the sitemap protocol
+	}
+
+	return &SitemapApplicationService{
+		slugService: slugService,
+		baseURL:     strings.TrimRight(siteURL, "/"),
+		apiURL:      strings.TrimRight(apiURL, "/"),
+		pageSize:    pageSize,
+	}
+}
+
+// Root returns the /sitemap.xml document: a plain URL set while the catalog fits on one page,
+// otherwise an index pointing at the per-section pages served by Page.
+func (s *SitemapApplicationService) Root() (interface{}, error) {
+	counts := make([]int, len(sitemapSections))
+	total := 0
+	for i, section := range sitemapSections {
+		count, err := s.slugService.CountSitemapEntries(section.entityType)
+		if err != nil {
+			return nil, err
+		}
+		counts[i] = count
+		total += count
+	}
+
+	if total <= s.pageSize {
+		set := &SitemapURLSet{Xmlns: sitemapNamespace}
+		for _, section := range sitemapSections {
+			urls, err := s.urls(section.entityType, section.path, s.pageSize, 0)
+			if err != nil {
+				return nil, err
+			}
+			set.URLs = append(set.URLs, urls...)
+		}
+		return set, nil
+	}
+
+	index := &SitemapIndex{Xmlns: sitemapNamespace}
+	for i, section := range sitemapSections {
+		pages := (counts[i] + s.pageSize - 1) / s.pageSize
+		for page := 1; page <= pages; page++ {
+			index.Sitemaps = append(index.Sitemaps, SitemapURL{
+				Loc: fmt.Sprintf("%s/sitemaps/%s-%d.xml", s.apiURL, section.name, page),
+			})
+		}
+	}
+	return index, nil
+}
+
+// Page returns one page of a sitemap section, e.g. name "products" and page 2.
+func (s *SitemapApplicationService) Page(name string, page int) (*SitemapURLSet, error) {
+	if page < 1 {
+		return nil, errors.New("sitemap page not found")
+	}
+
+	for _, section := range sitemapSections {
+		if section.name != name {
+			continue
+		}
+
+		urls, err := s.urls(section.entityType, section.path, s.pageSize, (page-1)*s.pageSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(urls) == 0 {
+			return nil, errors.New("sitemap page not found")
+		}
+		return &SitemapURLSet{Xmlns: sitemapNamespace, URLs: urls}, nil
+	}
+
+	return nil, errors.New("sitemap page not found")
+}
+
+func (s *SitemapApplicationService) urls(entityType, path string, limit, offset int) ([]SitemapURL, error) {
+	entries, err := s.slugService.GetSitemapEntries(entityType, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]SitemapURL, 0, len(entries))
+	for _, entry := range entries {
+		u := SitemapURL{Loc: s.baseURL + path + url.PathEscape(entry.Slug)}
+		if !entry.UpdatedAt.IsZero() {
+			u.LastMod = entry.UpdatedAt.UTC().Format(time.RFC3339)
+		}
+		urls = append(urls, u)
+	}
+	return urls, nil
+}
@@ -2,22 +2,28 @@ package application
 
 import (
 	"MicroShopik/internal/domain"
+	"MicroShopik/internal/events"
 	domain2 "MicroShopik/internal/services/domain"
 	"errors"
+	"strings"
+	"time"
 )
 
 type UserApplicationService struct {
 	userService domain2.UserService
 	roleService domain2.RoleService
+	events      events.EventBus
 }
 
 func NewUserApplicationService(
 	userService domain2.UserService,
 	roleService domain2.RoleService,
+	eventBus events.EventBus,
 ) *UserApplicationService {
 	return &UserApplicationService{
 		userService: userService,
 		roleService: roleService,
+		events:      eventBus,
 	}
 }
 
@@ -25,18 +31,23 @@ func (s *UserApplicationService) RegisterUser(user *domain.User) error {
 	if err := s.userService.Register(user); err != nil {
 		return err
 	}
+
+	s.events.Publish(events.UserRegistered{User: user})
 	return nil
 }
 
-func (s *UserApplicationService) LoginUser(email, password string) (string, *domain.User, error) {
-	token, user, err := s.userService.Login(email, password)
+func (s *UserApplicationService) LoginUser(email, password string, client domain.SessionClient) (*domain.AuthTokens, *domain.User, error) {
+	tokens, user, err := s.userService.Login(email, password, client)
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
-	return token, user, nil
+	return tokens, user, nil
 }
 
+// CreateUserWithRoles is for accounts an admin sets up; their email counts as verified.
 func (s *UserApplicationService) CreateUserWithRoles(user *domain.User, roles []string) error {
+	now := time.Now()
+	user.EmailVerifiedAt = &now
 	if err := s.userService.Create(user); err != nil {
 		return err
 	}
@@ -62,8 +73,10 @@ func (s *UserApplicationService) DeleteUserAccount(userID int) error {
 	return nil
 }
 
+// UpdateUserProfile sends a verification link when the email address changes, since the new
+// address has to be confirmed before the user can order or sell again.
 func (s *UserApplicationService) UpdateUserProfile(user *domain.User) error {
-	_, err := s.userService.GetByID(user.ID)
+	existing, err := s.userService.GetByID(user.ID)
 	if err != nil {
 		return errors.New("user not found")
 	}
@@ -72,6 +85,13 @@ func (s *UserApplicationService) UpdateUserProfile(user *domain.User) error {
 		return err
 	}
 
+	if user.Email != "" && !strings.EqualFold(existing.Email, user.Email) {
+		updated, err := s.userService.GetByID(user.ID)
+		if err != nil {
+			return err
+		}
+		s.events.Publish(events.UserEmailChanged{User: updated})
+	}
 	return nil
 }
 
@@ -0,0 +1,113 @@
+package application
+
+import (
+	"MicroShopik/internal/domain"
+	"MicroShopik/internal/events"
+	domain2 "MicroShopik/internal/services/domain"
+	"fmt"
+)
+
+// WebhookSubscriber forwards order and chat events to the webhook endpoints of the sellers involved.
+type WebhookSubscriber struct {
+	webhookService     domain2.WebhookService
+	orderService       domain2.OrderService
+	participantService domain2.ParticipantService
+}
+
+func NewWebhookSubscriber(
+	webhookService domain2.WebhookService,
+	orderService domain2.OrderService,
+	participantService domain2.ParticipantService,
+) *WebhookSubscriber {
+	return &WebhookSubscriber{
+		webhookService:     webhookService,
+		orderService:       orderService,
+		participantService: participantService,
+	}
+}
+
+// OnOrderChanged tells the seller about new orders and about orders they gain or lose through
+// confirmation or cancellation. Other status changes are not forwarded.
+func (s *WebhookSubscriber) OnOrderChanged(event events.Event) error {
+	var orderID int
+	var eventType string
+	switch e := event.(type) {
+	case events.OrderCreated:
+		orderID, eventType = e.Order.ID, domain.WebhookEventOrderCreated
+	case events.OrderStatusChanged:
+		switch e.NewStatus {
+		case "confirmed":
+			eventType = domain.WebhookEventOrderConfirmed
+		case "cancelled":
+			eventType = domain.WebhookEventOrderCancelled
+		default:
+			return nil
+		}
+		orderID = e.Order.ID
+	default:
+		return nil
+	}
+
+	order, err := s.orderService.GetByID(orderID)
+	if err != nil {
+		return err
+	}
+	if order.Product == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"order": map[string]interface{}{
+			"id":            order.ID,
+			"status":        order.Status,
+			"customer_id":   order.CustomerID,
+			"product_id":    order.ProductID,
+			"product_title": order.Product.Title,
+			"price":         order.Product.Price,
+			"created_at":    order.CreatedAt,
+			"updated_at":    order.UpdatedAt,
+		},
+	}
+	eventID := fmt.Sprintf("%s:%d", eventType, order.ID)
+
+	return s.webhookService.Dispatch(order.Product.SellerID, eventType, eventID, data)
+}
+
+// OnMessageSent forwards chat messages to every other participant's endpoints. System messages
+// are skipped; the order events cover them.
+func (s *WebhookSubscriber) OnMessageSent(event events.Event) error {
+	sent, ok := event.(events.MessageSent)
+	if !ok || sent.Message.IsSystem || sent.Message.SenderID == nil {
+		return nil
+	}
+	message := sent.Message
+
+	participants, err := s.participantService.GetByConversationID(message.ConversationID)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"id":              message.ID,
+		"conversation_id": message.ConversationID,
+		"sender_id":       message.SenderID,
+		"order_id":        message.OrderID,
+		"content":         message.Content,
+		"created_at":      message.CreatedAt,
+	}
+	if message.Sender != nil {
+		payload["sender_name"] = message.Sender.Username
+	}
+	data := map[string]interface{}{"message": payload}
+	eventID := fmt.Sprintf("%s:%d", domain.WebhookEventMessageReceived, message.ID)
+
+	for _, participant := range participants {
+		if participant.UserID == *message.SenderID {
+			continue
+		}
+		if err := s.webhookService.Dispatch(participant.UserID, domain.WebhookEventMessageReceived, eventID, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
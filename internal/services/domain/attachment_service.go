@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"MicroShopik/internal/storage"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// allowedAttachmentTypes are the sniffed content types accepted for upload:
+// screenshots, documents such as payment receipts, and archives.
+var allowedAttachmentTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+	"text/plain":      true,
+	"application/zip": true,
+}
+
+var ErrAttachmentTooLarge = errors.New("attachment is too large")
+
+type AttachmentService interface {
+	Upload(conversationID, uploaderID int, fileName string, r io.Reader) (*domain.Attachment, error)
+	Open(attachmentID, userID int) (*domain.Attachment, io.ReadCloser, error)
+	MaxSize() int64
+	RemoveFiles(attachments []domain.Attachment)
+}
+
+type attachmentService struct {
+	attachmentRepo  domain.AttachmentRepository
+	participantRepo domain.ParticipantRepository
+	storage         storage.Storage
+	maxSize         int64
+}
+
+func NewAttachmentService(aRepo domain.AttachmentRepository, pRepo domain.ParticipantRepository, store storage.Storage, maxSize int64) AttachmentService {
+	return &attachmentService{
+		attachmentRepo:  aRepo,
+		participantRepo: pRepo,
+		storage:         store,
+		maxSize:         maxSize,
+	}
+}
+
+func (s *attachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Upload stores a file for a conversation the uploader takes part in. The type is detected from
+// the content rather than trusted from the client.
+func (s *attachmentService) Upload(conversationID, uploaderID int, fileName string, r io.Reader) (*domain.Attachment, error) {
+	isParticipant, err := s.participantRepo.IsParticipant(conversationID, uploaderID)
+	if err != nil {
+		return nil, err
+	}
+	if !isParticipant {
+		return nil, errors.New("user is not a participant in this conversation")
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("attachment is empty")
+		}
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !allowedAttachmentTypes[contentType] {
+		return nil, fmt.Errorf("file type %s is not allowed", contentType)
+	}
+
+	key, err := attachmentKey(conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	counter := &limitedCounter{r: io.MultiReader(bytes.NewReader(head), r), max: s.maxSize}
+	if err := s.storage.Save(key, counter); err != nil {
+		_ = s.storage.Delete(key)
+		if errors.Is(err, ErrAttachmentTooLarge) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	attachment := &domain.Attachment{
+		ConversationID: conversationID,
+		UploaderID:     uploaderID,
+		FileName:       sanitizeFileName(fileName),
+		ContentType:    contentType,
+		Size:           counter.n,
+		StorageKey:     key,
+	}
+	if err := s.attachmentRepo.Create(attachment); err != nil {
+		_ = s.storage.Delete(key)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// Open returns the attachment and its content if the user takes part in its conversation.
+func (s *attachmentService) Open(attachmentID, userID int) (*domain.Attachment, io.ReadCloser, error) {
+	attachment, err := s.attachmentRepo.GetByID(attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	isParticipant, err := s.participantRepo.IsParticipant(attachment.ConversationID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isParticipant {
+		return nil, nil, errors.New("user is not a participant in this conversation")
+	}
+
+	content, err := s.storage.Open(attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// RemoveFiles deletes the stored content of attachments whose rows are already gone. Failures are
+// only logged: the rows no longer point at the files, so nothing can serve them anyway.
+func (s *attachmentService) RemoveFiles(attachments []domain.Attachment) {
+	for _, attachment := range attachments {
+		if err := s.storage.Delete(attachment.StorageKey); err != nil {
+			log.Printf("Failed to delete file of attachment %d: %v", attachment.ID, err)
+		}
+	}
+}
+
+func attachmentKey(conversationID int) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("conversations/%d/%s", conversationID, hex.EncodeToString(b)), nil
+}
+
+// sanitizeFileName keeps only the base name and drops control characters; it is only ever shown to users.
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[:255])
+	}
+	return name
+}
+
+// limitedCounter counts bytes read and fails once more than max bytes come through.
+type limitedCounter struct {
+	r   io.Reader
+	max int64
+	n   int64
+}
+
+func (l *limitedCounter) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.n > l.max {
+		return n, ErrAttachmentTooLarge
+	}
+	return n, err
+}
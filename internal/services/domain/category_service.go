@@ -13,15 +13,17 @@ type CategoryService interface {
 	GetCategoryById(id int) (*domain.Category, error)
 	GetByID(id int) (*domain.Category, error)
 	GetAllCategories() (*[]domain.Category, error)
+	GetBySlug(slug string) (*domain.Category, error)
 	ValidateCategoryExists(categoryID int) error
 }
 
 type categoryService struct {
 	categoryRepo domain.CategoryRepository
+	slugService  SlugService
 }
 
-func NewCategoryService(c domain.CategoryRepository) CategoryService {
-	return &categoryService{categoryRepo: c}
+func NewCategoryService(c domain.CategoryRepository, slugService SlugService) CategoryService {
+	return &categoryService{categoryRepo: c, slugService: slugService}
 }
 
 func (c *categoryService) Create(category *domain.Category) error {
@@ -32,6 +34,12 @@ func (c *categoryService) Create(category *domain.Category) error {
 		return errors.New("category name is empty")
 	}
 
+	slug, err := c.slugService.Generate(domain.SlugEntityCategory, category.Name, 0)
+	if err != nil {
+		return err
+	}
+
+	category.Slug = &slug
 	category.CreatedAt = time.Now()
 	return c.categoryRepo.Create(category)
 }
@@ -43,8 +51,36 @@ func (c *categoryService) Update(category *domain.Category) error {
 	if len(category.Name) <= 0 {
 		return errors.New("category name is empty")
 	}
-	_, err := c.categoryRepo.Update(category)
-	return err
+
+	existing, err := c.categoryRepo.GetCategoryById(category.ID)
+	if err != nil {
+		return err
+	}
+
+	// The slug follows the name and is never taken from the request.
+	category.Slug = existing.Slug
+	category.CreatedAt = existing.CreatedAt
+	oldSlug := ""
+	if existing.Slug != nil {
+		oldSlug = *existing.Slug
+	}
+
+	if category.Name != existing.Name || existing.Slug == nil {
+		slug, err := c.slugService.Generate(domain.SlugEntityCategory, category.Name, category.ID)
+		if err != nil {
+			return err
+		}
+		category.Slug = &slug
+	}
+
+	if _, err := c.categoryRepo.Update(category); err != nil {
+		return err
+	}
+
+	if category.Slug != nil && *category.Slug != oldSlug {
+		return c.slugService.RecordChange(domain.SlugEntityCategory, category.ID, oldSlug, *category.Slug)
+	}
+	return nil
 }
 
 func (c *categoryService) Delete(category *domain.Category) error {
@@ -61,6 +97,10 @@ func (c *categoryService) GetAllCategories() (*[]domain.Category, error) {
 	return c.categoryRepo.GetAllCategories()
 }
 
+func (c *categoryService) GetBySlug(slug string) (*domain.Category, error) {
+	return c.categoryRepo.GetBySlug(slug)
+}
+
 func (c *categoryService) GetByID(id int) (*domain.Category, error) {
 	return c.categoryRepo.GetCategoryById(id)
 }
@@ -0,0 +1,13 @@
+package domain
+
+import "MicroShopik/internal/domain"
+
+// ConversationNotifier is told about conversation changes after they are saved,
+// so connected participants can be updated in real time.
+type ConversationNotifier interface {
+	MessageCreated(message *domain.Message)
+	MessageUpdated(message *domain.Message)
+	ParticipantJoined(conversationID, userID int)
+	ParticipantLeft(conversationID, userID int)
+	ConversationRead(conversationID, userID, messageID int)
+}
@@ -11,6 +11,9 @@ type ConversationService interface {
 	GetByID(id int) (*domain.Conversation, error)
 	GetByUserID(userID int) ([]*domain.Conversation, error)
 	GetByProductID(productID int) ([]*domain.Conversation, error)
+	FindOrCreateInquiry(productID, buyerID, sellerID int) (*domain.Conversation, bool, error)
+	FindOrCreateForOrder(productID, buyerID, sellerID int) (*domain.Conversation, error)
+	GetByIDs(ids []int) ([]*domain.Conversation, error)
 	Update(conversation *domain.Conversation) error
 	Delete(id int) error
 	AddParticipant(conversationID, userID int) error
@@ -22,13 +25,15 @@ type conversationService struct {
 	conversationRepo domain.ConversationRepository
 	participantRepo  domain.ParticipantRepository
 	userRepo         domain.UserRepository
+	notifier         ConversationNotifier
 }
 
-func NewConversationService(cRepo domain.ConversationRepository, pRepo domain.ParticipantRepository, uRepo domain.UserRepository) ConversationService {
+func NewConversationService(cRepo domain.ConversationRepository, pRepo domain.ParticipantRepository, uRepo domain.UserRepository, notifier ConversationNotifier) ConversationService {
 	return &conversationService{
 		conversationRepo: cRepo,
 		participantRepo:  pRepo,
 		userRepo:         uRepo,
+		notifier:         notifier,
 	}
 }
 
@@ -56,7 +61,14 @@ func (s *conversationService) Create(conversation *domain.Conversation, particip
 		}
 	}
 
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	for _, userID := range participantIDs {
+		s.notifier.ParticipantJoined(conversation.ID, userID)
+	}
+	return nil
 }
 
 func (s *conversationService) GetByID(id int) (*domain.Conversation, error) {
@@ -71,6 +83,51 @@ func (s *conversationService) GetByProductID(productID int) ([]*domain.Conversat
 	return s.conversationRepo.GetByProductID(productID)
 }
 
+// FindOrCreateInquiry returns the buyer's inquiry conversation about the product, creating it with the
+// buyer and the seller as participants on first use. The bool reports whether it was created.
+func (s *conversationService) FindOrCreateInquiry(productID, buyerID, sellerID int) (*domain.Conversation, bool, error) {
+	conversation := &domain.Conversation{ProductID: &productID, BuyerID: &buyerID}
+	created, err := s.conversationRepo.FindOrCreateInquiry(conversation, []int{buyerID, sellerID})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if created {
+		s.notifier.ParticipantJoined(conversation.ID, buyerID)
+		s.notifier.ParticipantJoined(conversation.ID, sellerID)
+	}
+
+	conversation, err = s.conversationRepo.GetByID(conversation.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	return conversation, created, nil
+}
+
+// FindOrCreateForOrder picks the chat an order belongs in: the buyer's inquiry about the product,
+// then the latest one-to-one chat between buyer and seller, and only then a new inquiry conversation.
+func (s *conversationService) FindOrCreateForOrder(productID, buyerID, sellerID int) (*domain.Conversation, error) {
+	conversation, err := s.conversationRepo.GetInquiry(productID, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation == nil {
+		if conversation, err = s.conversationRepo.GetLatestBetween(buyerID, sellerID); err != nil {
+			return nil, err
+		}
+	}
+	if conversation != nil {
+		return conversation, nil
+	}
+
+	conversation, _, err = s.FindOrCreateInquiry(productID, buyerID, sellerID)
+	return conversation, err
+}
+
+func (s *conversationService) GetByIDs(ids []int) ([]*domain.Conversation, error) {
+	return s.conversationRepo.GetByIDs(ids)
+}
+
 func (s *conversationService) Update(conversation *domain.Conversation) error {
 	return s.conversationRepo.Update(conversation)
 }
@@ -88,7 +145,12 @@ func (s *conversationService) AddParticipant(conversationID, userID int) error {
 		return errors.New("user is already a participant")
 	}
 
-	return s.conversationRepo.AddParticipant(conversationID, userID)
+	if err := s.conversationRepo.AddParticipant(conversationID, userID); err != nil {
+		return err
+	}
+
+	s.notifier.ParticipantJoined(conversationID, userID)
+	return nil
 }
 
 func (s *conversationService) RemoveParticipant(conversationID, userID int) error {
@@ -100,7 +162,12 @@ func (s *conversationService) RemoveParticipant(conversationID, userID int) erro
 		return errors.New("user is not a participant")
 	}
 
-	return s.conversationRepo.RemoveParticipant(conversationID, userID)
+	if err := s.conversationRepo.RemoveParticipant(conversationID, userID); err != nil {
+		return err
+	}
+
+	s.notifier.ParticipantLeft(conversationID, userID)
+	return nil
 }
 
 func (s *conversationService) IsParticipant(conversationID, userID int) (bool, error) {
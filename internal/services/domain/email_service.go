@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"MicroShopik/internal/events"
+	"MicroShopik/internal/mailer"
+	"errors"
+	"net/mail"
+	"time"
+)
+
+// EmailService renders transactional email in the recipient's language and queues it on the
+// outbox. The relay calls Deliver, so a failing mail server only delays the email.
+type EmailService interface {
+	// Queue sends an email the user cannot opt out of, such as the welcome email.
+	Queue(recipient *domain.User, template string, data map[string]interface{}) error
+	// Notify sends a notification email for eventType if the recipient's preferences allow it.
+	// It is held back during quiet hours and skipped when the user gets a digest instead.
+	Notify(recipient *domain.User, eventType, template string, data map[string]interface{}) error
+	Deliver(event events.Event) error
+}
+
+type emailService struct {
+	renderer    *mailer.Renderer
+	mailer      mailer.Mailer
+	outbox      OutboxService
+	preferences NotificationPreferenceService
+	siteURL     string
+}
+
+func NewEmailService(renderer *mailer.Renderer, m mailer.Mailer, outbox OutboxService, preferences NotificationPreferenceService, siteURL string) EmailService {
+	return &emailService{
+		renderer:    renderer,
+		mailer:      m,
+		outbox:      outbox,
+		preferences: preferences,
+		siteURL:     siteURL,
+	}
+}
+
+func (s *emailService) Queue(recipient *domain.User, template string, data map[string]interface{}) error {
+	return s.queue(recipient, template, data, nil)
+}
+
+func (s *emailService) Notify(recipient *domain.User, eventType, template string, data map[string]interface{}) error {
+	if recipient == nil {
+		return errors.New("recipient has no email address")
+	}
+	decision, err := s.preferences.Decide(recipient.ID, eventType, domain.NotificationChannelEmail)
+	if err != nil {
+		return err
+	}
+	if !decision.Allowed || decision.Digest {
+		return nil
+	}
+	return s.queue(recipient, template, data, decision.QuietUntil)
+}
+
+func (s *emailService) queue(recipient *domain.User, template string, data map[string]interface{}, notBefore *time.Time) error {
+	if recipient == nil || recipient.Email == "" {
+		return errors.New("recipient has no email address")
+	}
+
+	vars := map[string]interface{}{
+		"Username": recipient.Username,
+		"SiteURL":  s.siteURL,
+	}
+	for k, v := range data {
+		vars[k] = v
+	}
+
+	msg, err := s.renderer.Render(template, recipient.Locale, vars)
+	if err != nil {
+		return err
+	}
+	msg.To = (&mail.Address{Name: recipient.Username, Address: recipient.Email}).String()
+
+	if notBefore != nil {
+		return s.outbox.EnqueueAt(events.EmailQueued{Email: *msg}, *notBefore)
+	}
+	return s.outbox.Enqueue(events.EmailQueued{Email: *msg})
+}
+
+func (s *emailService) Deliver(event events.Event) error {
+	queued, ok := event.(events.EmailQueued)
+	if !ok {
+		return nil
+	}
+	return s.mailer.Send(&queued.Email)
+}
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// verificationResendCooldown is the least time between two verification emails to one user.
+const verificationResendCooldown = time.Minute
+
+var (
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification link")
+	ErrEmailAlreadyVerified     = errors.New("email address is already verified")
+	ErrVerificationRateLimited  = errors.New("a verification email was sent recently, please wait a minute")
+)
+
+// EmailVerificationService confirms that users own the email address they signed up with.
+// Tokens are HMAC-signed over the user ID, expiry and current email address, so nothing is
+// stored for them: a token stops working once the address is verified or changed.
+type EmailVerificationService interface {
+	// SendVerification emails the user a verification link.
+	SendVerification(user *domain.User) error
+	// Resend emails a new link to the account with that address. Unknown and already verified
+	// addresses, and accounts mailed within the cooldown, are skipped silently so the endpoint
+	// does not reveal which accounts exist.
+	Resend(email string) error
+	// Verify marks the email of the token's user as verified and sends the welcome email.
+	Verify(token string) (*domain.User, error)
+}
+
+type emailVerificationService struct {
+	userRepo     domain.UserRepository
+	emailService EmailService
+	secret       []byte
+	ttl          time.Duration
+}
+
+func NewEmailVerificationService(
+	uRepo domain.UserRepository,
+	emailService EmailService,
+	secret string,
+	ttl time.Duration,
+) EmailVerificationService {
+	return &emailVerificationService{
+		userRepo:     uRepo,
+		emailService: emailService,
+		secret:       []byte(secret),
+		ttl:          ttl,
+	}
+}
+
+func (s *emailVerificationService) SendVerification(user *domain.User) error {
+	now := time.Now()
+	claimed, err := s.userRepo.ClaimVerificationSend(user.ID, now, now.Add(-verificationResendCooldown))
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrVerificationRateLimited
+	}
+
+	token := s.sign(user.ID, user.Email, now.Add(s.ttl))
+	return s.emailService.Queue(user, "verify_email", map[string]interface{}{
+		"Token":          token,
+		"ExpiresInHours": int(s.ttl.Hours()),
+	})
+}
+
+func (s *emailVerificationService) Resend(email string) error {
+	user, err := s.userRepo.GetByEmail(strings.TrimSpace(email))
+	if err != nil || user.EmailVerifiedAt != nil {
+		return nil
+	}
+	if err := s.SendVerification(user); err != nil && !errors.Is(err, ErrVerificationRateLimited) {
+		return err
+	}
+	return nil
+}
+
+func (s *emailVerificationService) Verify(token string) (*domain.User, error) {
+	userID, expiresAt, ok := parseVerificationToken(token)
+	if !ok || time.Now().After(expiresAt) {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+	if !hmac.Equal([]byte(token), []byte(s.sign(user.ID, user.Email, expiresAt))) {
+		return nil, ErrInvalidVerificationToken
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil, ErrEmailAlreadyVerified
+	}
+
+	now := time.Now()
+	verified, err := s.userRepo.MarkEmailVerified(user.ID, user.Email, now)
+	if err != nil {
+		return nil, err
+	}
+	if !verified {
+		return nil, ErrEmailAlreadyVerified
+	}
+	user.EmailVerifiedAt = &now
+
+	if err := s.emailService.Queue(user, "welcome", nil); err != nil {
+		log.Printf("Failed to queue welcome email for user %d: %v", user.ID, err)
+	}
+	return user, nil
+}
+
+// sign builds a token of the form "<user id>.<expiry unix time>.<hex signature>".
+func (s *emailVerificationService) sign(userID int, email string, expiresAt time.Time) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "email-verification\n%d\n%d\n%s", userID, expiresAt.Unix(), strings.ToLower(email))
+	return fmt.Sprintf("%d.%d.%s", userID, expiresAt.Unix(), hex.EncodeToString(mac.Sum(nil)))
+}
+
+func parseVerificationToken(token string) (int, time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, time.Time{}, false
+	}
+	userID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	return userID, time.Unix(expires, 0), true
+}
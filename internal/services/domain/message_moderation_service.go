@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"MicroShopik/internal/events"
+	"errors"
+	"strings"
+	"time"
+)
+
+// maxSuspensionDays caps a single suspension; longer bans are issued without an end date.
+const maxSuspensionDays = 365
+
+type MessageModerationService interface {
+	Report(conversationID, messageID, reporterID int, reason, comment string) (*domain.MessageReport, error)
+	GetQueue(limit, offset int) ([]*domain.MessageReport, int64, error)
+	Dismiss(messageID, moderatorID int, comment string) error
+	DeleteMessage(messageID, moderatorID int, comment string) error
+	WarnSender(messageID, moderatorID int, comment string) error
+	SuspendSender(messageID, moderatorID, days int, comment string) error
+	GetSanctions(userID int) ([]*domain.UserSanction, error)
+}
+
+type messageModerationService struct {
+	messageRepo     domain.MessageRepository
+	participantRepo domain.ParticipantRepository
+	reportRepo      domain.MessageReportRepository
+	sanctionRepo    domain.UserSanctionRepository
+	attachments     AttachmentService
+	notifier        ConversationNotifier
+	events          events.EventBus
+}
+
+func NewMessageModerationService(mRepo domain.MessageRepository, pRepo domain.ParticipantRepository, rRepo domain.MessageReportRepository, sRepo domain.UserSanctionRepository, attachments AttachmentService, notifier ConversationNotifier, eventBus events.EventBus) MessageModerationService {
+	return &messageModerationService{
+		messageRepo:     mRepo,
+		participantRepo: pRepo,
+		reportRepo:      rRepo,
+		sanctionRepo:    sRepo,
+		attachments:     attachments,
+		notifier:        notifier,
+		events:          eventBus,
+	}
+}
+
+func (s *messageModerationService) Report(conversationID, messageID, reporterID int, reason, comment string) (*domain.MessageReport, error) {
+	if _, ok := domain.MessageReportReasons[reason]; !ok {
+		return nil, errors.New("invalid reason code")
+	}
+	comment = strings.TrimSpace(comment)
+	if reason == "other" && comment == "" {
+		return nil, errors.New("comment is required when the reason is other")
+	}
+
+	message, err := s.messageRepo.GetByID(messageID)
+	if err != nil || message.ConversationID != conversationID {
+		return nil, errors.New("message not found")
+	}
+	isParticipant, err := s.participantRepo.IsParticipant(conversationID, reporterID)
+	if err != nil {
+		return nil, err
+	}
+	if !isParticipant {
+		return nil, errors.New("user is not a participant in this conversation")
+	}
+	if message.IsSystem || message.SenderID == nil {
+		return nil, errors.New("system messages cannot be reported")
+	}
+	if *message.SenderID == reporterID {
+		return nil, errors.New("you cannot report your own message")
+	}
+	if message.IsDeleted {
+		return nil, errors.New("message is deleted")
+	}
+
+	report := &domain.MessageReport{
+		MessageID:  messageID,
+		ReporterID: &reporterID,
+		Reason:     reason,
+		Comment:    comment,
+		Status:     domain.MessageReportStatusOpen,
+	}
+	if err := s.reportRepo.Create(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *messageModerationService) GetQueue(limit, offset int) ([]*domain.MessageReport, int64, error) {
+	return s.reportRepo.GetOpen(limit, offset)
+}
+
+func (s *messageModerationService) Dismiss(messageID, moderatorID int, comment string) error {
+	message, err := s.reportedMessage(messageID)
+	if err != nil {
+		return err
+	}
+	return s.resolve(message, domain.MessageResolutionDismissed, comment, moderatorID)
+}
+
+// DeleteMessage tombstones the message like a sender's own delete, keeping the text as a revision for admins.
+func (s *messageModerationService) DeleteMessage(messageID, moderatorID int, comment string) error {
+	message, err := s.reportedMessage(messageID)
+	if err != nil {
+		return err
+	}
+
+	if !message.IsDeleted {
+		revision := &domain.MessageRevision{MessageID: messageID, EditorID: moderatorID, Content: message.Content}
+		removed, err := s.messageRepo.Tombstone(messageID, revision)
+		if err != nil {
+			return err
+		}
+		s.attachments.RemoveFiles(removed)
+		if message, err = s.messageRepo.GetByID(messageID); err != nil {
+			return err
+		}
+		s.notifier.MessageUpdated(message)
+	}
+
+	return s.resolve(message, domain.MessageResolutionDeleted, comment, moderatorID)
+}
+
+func (s *messageModerationService) WarnSender(messageID, moderatorID int, comment string) error {
+	message, err := s.reportedMessage(messageID)
+	if err != nil {
+		return err
+	}
+
+	sanction := &domain.UserSanction{
+		UserID:     *message.SenderID,
+		Kind:       domain.SanctionWarning,
+		Reason:     comment,
+		MessageID:  &messageID,
+		IssuedByID: moderatorID,
+	}
+	if err := s.sanctionRepo.Create(sanction); err != nil {
+		return err
+	}
+	return s.resolve(message, domain.MessageResolutionWarned, comment, moderatorID)
+}
+
+// SuspendSender stops the sender from writing messages for the given number of days, or for good when days is 0.
+func (s *messageModerationService) SuspendSender(messageID, moderatorID, days int, comment string) error {
+	if days < 0 || days > maxSuspensionDays {
+		return errors.New("days must be between 0 and 365")
+	}
+	message, err := s.reportedMessage(messageID)
+	if err != nil {
+		return err
+	}
+
+	sanction := &domain.UserSanction{
+		UserID:     *message.SenderID,
+		Kind:       domain.SanctionSuspension,
+		Reason:     comment,
+		MessageID:  &messageID,
+		IssuedByID: moderatorID,
+	}
+	if days > 0 {
+		expiresAt := time.Now().AddDate(0, 0, days)
+		sanction.ExpiresAt = &expiresAt
+	}
+	if err := s.sanctionRepo.Create(sanction); err != nil {
+		return err
+	}
+	return s.resolve(message, domain.MessageResolutionSuspended, comment, moderatorID)
+}
+
+func (s *messageModerationService) GetSanctions(userID int) ([]*domain.UserSanction, error) {
+	return s.sanctionRepo.GetByUserID(userID)
+}
+
+// resolve closes the open reports of the message and tells subscribers about the decision.
+func (s *messageModerationService) resolve(message *domain.Message, resolution, comment string, moderatorID int) error {
+	if err := s.reportRepo.ResolveOpen(message.ID, resolution, comment, moderatorID); err != nil {
+		return err
+	}
+
+	s.events.Publish(events.MessageModerated{Message: message, Resolution: resolution, Comment: comment})
+	return nil
+}
+
+// reportedMessage loads a message that has open reports; moderators act only on queued messages.
+func (s *messageModerationService) reportedMessage(messageID int) (*domain.Message, error) {
+	count, err := s.reportRepo.CountOpenByMessageID(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.New("message has no open reports")
+	}
+
+	message, err := s.messageRepo.GetByID(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.SenderID == nil {
+		return nil, errors.New("system messages cannot be moderated")
+	}
+	return message, nil
+}
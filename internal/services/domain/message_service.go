@@ -1,34 +1,75 @@
 package domain
 
 import (
+	"MicroShopik/internal/contentfilter"
 	"MicroShopik/internal/domain"
+	"MicroShopik/internal/events"
 	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMessageBlocked  = errors.New("message not sent because it contains")
+	ErrSenderSuspended = errors.New("you are suspended from sending messages")
 )
 
 type MessageService interface {
 	Create(message *domain.Message) error
 	GetByID(id int) (*domain.Message, error)
-	GetByConversationID(conversationID int) ([]*domain.Message, error)
+	GetPage(conversationID, userID int, cursor domain.MessageCursor, limit int) (*domain.MessagePage, error)
+	GetLatestForConversations(conversationIDs []int) ([]*domain.Message, error)
+	GetOrderIDsForConversations(conversationIDs []int) (map[int]int, error)
+	Search(params domain.MessageSearchParams) ([]*domain.MessageSearchResult, error)
 	GetByOrderID(orderID int) ([]*domain.Message, error)
-	Update(message *domain.Message) error
-	Delete(id int) error
+	Edit(id, userID int, content string) (*domain.Message, error)
+	Delete(id, userID int) (*domain.Message, error)
+	GetRevisions(id, userID int, isAdmin bool) ([]*domain.MessageRevision, error)
 	GetSystemMessages(conversationID int) ([]*domain.Message, error)
 	SendSystemMessage(conversationID int, text string, orderID *int) error
+	GetLatestID(conversationID int) (int, error)
 }
 
+const (
+	defaultMessagePageSize = 50
+	maxMessagePageSize     = 100
+
+	defaultMessageSearchLimit = 20
+	maxMessageSearchLimit     = 50
+	minMessageSearchLength    = 2
+)
+
 type messageService struct {
 	messageRepo      domain.MessageRepository
 	conversationRepo domain.ConversationRepository
 	participantRepo  domain.ParticipantRepository
 	orderRepo        domain.OrderRepository
+	attachmentRepo   domain.AttachmentRepository
+	attachments      AttachmentService
+	reportRepo       domain.MessageReportRepository
+	sanctionRepo     domain.UserSanctionRepository
+	contentFilter    contentfilter.Filter
+	notifier         ConversationNotifier
+	events           events.EventBus
+	editWindow       time.Duration
 }
 
-func NewMessageService(mRepo domain.MessageRepository, cRepo domain.ConversationRepository, pRepo domain.ParticipantRepository, oRepo domain.OrderRepository) MessageService {
+func NewMessageService(mRepo domain.MessageRepository, cRepo domain.ConversationRepository, pRepo domain.ParticipantRepository, oRepo domain.OrderRepository, aRepo domain.AttachmentRepository, attachments AttachmentService, rRepo domain.MessageReportRepository, sRepo domain.UserSanctionRepository, filter contentfilter.Filter, notifier ConversationNotifier, eventBus events.EventBus, editWindow time.Duration) MessageService {
 	return &messageService{
 		messageRepo:      mRepo,
 		conversationRepo: cRepo,
 		participantRepo:  pRepo,
 		orderRepo:        oRepo,
+		attachmentRepo:   aRepo,
+		attachments:      attachments,
+		reportRepo:       rRepo,
+		sanctionRepo:     sRepo,
+		contentFilter:    filter,
+		notifier:         notifier,
+		events:           eventBus,
+		editWindow:       editWindow,
 	}
 }
 
@@ -47,6 +88,9 @@ func (s *messageService) Create(message *domain.Message) error {
 		if !isParticipant {
 			return errors.New("sender is not a participant in this conversation")
 		}
+		if err := s.checkNotSuspended(*message.SenderID); err != nil {
+			return err
+		}
 	}
 
 	if message.OrderID != nil {
@@ -56,33 +100,357 @@ func (s *messageService) Create(message *domain.Message) error {
 		}
 	}
 
-	return s.messageRepo.Create(message)
+	attachmentIDs := uniqueIDs(message.AttachmentIDs)
+	if len(attachmentIDs) > 0 {
+		if message.SenderID == nil {
+			return errors.New("system messages cannot have attachments")
+		}
+		count, err := s.attachmentRepo.CountAttachable(attachmentIDs, message.ConversationID, *message.SenderID)
+		if err != nil {
+			return err
+		}
+		if count != len(attachmentIDs) {
+			return errors.New("invalid attachment")
+		}
+	}
+	if strings.TrimSpace(message.Content) == "" && len(attachmentIDs) == 0 {
+		return errors.New("message content is empty")
+	}
+
+	var flagged []string
+	if message.SenderID != nil && !message.IsSystem {
+		content, matched, err := s.screen(message.Content)
+		if err != nil {
+			return err
+		}
+		message.Content, flagged = content, matched
+	}
+
+	if err := s.messageRepo.Create(message); err != nil {
+		return err
+	}
+	s.flag(message.ID, flagged)
+
+	if len(attachmentIDs) > 0 {
+		if err := s.attachmentRepo.AttachToMessage(attachmentIDs, message.ID); err != nil {
+			return err
+		}
+		attachments, err := s.attachmentRepo.GetByMessageID(message.ID)
+		if err != nil {
+			return err
+		}
+		message.Attachments = attachments
+	}
+
+	// Whoever writes has seen the conversation. The message is already saved, so a failure here
+	// only leaves the sender's own counter stale and is not worth failing the request for.
+	if message.SenderID != nil {
+		_, _ = s.participantRepo.MarkRead(message.ConversationID, *message.SenderID, message.ID)
+	}
+
+	s.notifier.MessageCreated(message)
+	s.events.Publish(events.MessageSent{Message: message})
+	return nil
+}
+
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
 }
 
 func (s *messageService) GetByID(id int) (*domain.Message, error) {
 	return s.messageRepo.GetByID(id)
 }
 
-func (s *messageService) GetByConversationID(conversationID int) ([]*domain.Message, error) {
-	return s.messageRepo.GetByConversationID(conversationID, 0, 0)
+// GetPage returns one page of the conversation's history to a participant. Each query asks for one
+// extra row so the page can tell whether more messages lie beyond it.
+func (s *messageService) GetPage(conversationID, userID int, cursor domain.MessageCursor, limit int) (*domain.MessagePage, error) {
+	isParticipant, err := s.participantRepo.IsParticipant(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isParticipant {
+		return nil, errors.New("user is not a participant in this conversation")
+	}
+
+	if limit <= 0 {
+		limit = defaultMessagePageSize
+	}
+	if limit > maxMessagePageSize {
+		limit = maxMessagePageSize
+	}
+
+	set := 0
+	for _, id := range []int{cursor.Before, cursor.After, cursor.Around} {
+		if id < 0 {
+			return nil, errors.New("cursor must be a positive message id")
+		}
+		if id > 0 {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, errors.New("use only one of before, after or around")
+	}
+
+	page := &domain.MessagePage{}
+	switch {
+	case cursor.After > 0:
+		newer, err := s.messageRepo.GetAfter(conversationID, cursor.After, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		page.HasNewer = len(newer) > limit
+		page.Messages = trimMessages(newer, limit)
+		page.HasOlder = true
+
+	case cursor.Around > 0:
+		// The target message itself counts towards the older half.
+		olderLimit := limit - limit/2
+		newerLimit := limit / 2
+
+		older, err := s.messageRepo.GetBefore(conversationID, cursor.Around+1, olderLimit+1)
+		if err != nil {
+			return nil, err
+		}
+		newer, err := s.messageRepo.GetAfter(conversationID, cursor.Around, newerLimit+1)
+		if err != nil {
+			return nil, err
+		}
+		page.HasOlder = len(older) > olderLimit
+		page.HasNewer = len(newer) > newerLimit
+		page.Messages = append(reverseMessages(trimMessages(older, olderLimit)), trimMessages(newer, newerLimit)...)
+
+	default:
+		// Before == 0 reads from the newest message backwards.
+		older, err := s.messageRepo.GetBefore(conversationID, cursor.Before, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		page.HasOlder = len(older) > limit
+		page.Messages = reverseMessages(trimMessages(older, limit))
+		page.HasNewer = cursor.Before > 0
+	}
+
+	if page.Messages == nil {
+		page.Messages = []*domain.Message{}
+	}
+	return page, nil
+}
+
+func (s *messageService) GetLatestForConversations(conversationIDs []int) ([]*domain.Message, error) {
+	return s.messageRepo.GetLatestForConversations(conversationIDs)
+}
+
+func (s *messageService) GetOrderIDsForConversations(conversationIDs []int) (map[int]int, error) {
+	return s.messageRepo.GetOrderIDsForConversations(conversationIDs)
+}
+
+// Search runs a full-text search over the messages of the user's conversations.
+func (s *messageService) Search(params domain.MessageSearchParams) ([]*domain.MessageSearchResult, error) {
+	params.Query = strings.TrimSpace(params.Query)
+	if len([]rune(params.Query)) < minMessageSearchLength {
+		return nil, fmt.Errorf("search query must be at least %d characters", minMessageSearchLength)
+	}
+	if params.From != nil && params.To != nil && !params.From.Before(*params.To) {
+		return nil, errors.New("from must be before to")
+	}
+
+	if params.Limit <= 0 {
+		params.Limit = defaultMessageSearchLimit
+	}
+	if params.Limit > maxMessageSearchLimit {
+		params.Limit = maxMessageSearchLimit
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+
+	return s.messageRepo.Search(params)
+}
+
+func trimMessages(messages []*domain.Message, limit int) []*domain.Message {
+	if len(messages) > limit {
+		return messages[:limit]
+	}
+	return messages
+}
+
+func reverseMessages(messages []*domain.Message) []*domain.Message {
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages
 }
 
 func (s *messageService) GetByOrderID(orderID int) ([]*domain.Message, error) {
 	return s.messageRepo.GetByOrderID(orderID)
 }
 
-func (s *messageService) Update(message *domain.Message) error {
-	return s.messageRepo.Update(message)
+// Edit changes the text of the user's own message while it is still inside the edit window.
+// The previous text is kept as a revision.
+func (s *messageService) Edit(id, userID int, content string) (*domain.Message, error) {
+	message, err := s.ownMutableMessage(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNotSuspended(userID); err != nil {
+		return nil, err
+	}
+
+	content = strings.TrimSpace(content)
+	if content == "" && len(message.Attachments) == 0 {
+		return nil, errors.New("message content is empty")
+	}
+	content, flagged, err := s.screen(content)
+	if err != nil {
+		return nil, err
+	}
+	if content == message.Content {
+		return message, nil
+	}
+
+	revision := &domain.MessageRevision{MessageID: id, EditorID: userID, Content: message.Content}
+	if err := s.messageRepo.Edit(id, content, revision); err != nil {
+		return nil, err
+	}
+
+	s.flag(id, flagged)
+
+	message, err = s.messageRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	s.notifier.MessageUpdated(message)
+	return message, nil
+}
+
+// screen runs a participant's text through the content filter. It returns the text to store, possibly
+// masked, and the matched rules when the message must go to the moderation queue.
+func (s *messageService) screen(content string) (string, []string, error) {
+	result := s.contentFilter.Check(content)
+	switch result.Action {
+	case contentfilter.Block:
+		return "", nil, fmt.Errorf("%w %s", ErrMessageBlocked, strings.Join(result.Matched, ", "))
+	case contentfilter.Flag:
+		return result.Content, result.Matched, nil
+	default:
+		return result.Content, nil, nil
+	}
 }
 
-func (s *messageService) Delete(id int) error {
-	return s.messageRepo.Delete(id)
+// flag queues a saved message for review. The message is already delivered, so a failure is only logged.
+func (s *messageService) flag(messageID int, matched []string) {
+	if len(matched) == 0 {
+		return
+	}
+	report := &domain.MessageReport{
+		MessageID: messageID,
+		Reason:    domain.MessageReportReasonContentFilter,
+		Comment:   "Matched: " + strings.Join(matched, ", "),
+		Status:    domain.MessageReportStatusOpen,
+	}
+	if err := s.reportRepo.Create(report); err != nil {
+		log.Printf("Failed to flag message %d for moderation: %v", messageID, err)
+	}
+}
+
+func (s *messageService) checkNotSuspended(userID int) error {
+	suspension, err := s.sanctionRepo.GetActiveSuspension(userID)
+	if err != nil {
+		return err
+	}
+	if suspension == nil {
+		return nil
+	}
+	if suspension.ExpiresAt == nil {
+		return ErrSenderSuspended
+	}
+	return fmt.Errorf("%w until %s", ErrSenderSuspended, suspension.ExpiresAt.UTC().Format(time.RFC1123))
+}
+
+// Delete turns the user's own message into a tombstone while it is still inside the edit window.
+func (s *messageService) Delete(id, userID int) (*domain.Message, error) {
+	message, err := s.ownMutableMessage(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := &domain.MessageRevision{MessageID: id, EditorID: userID, Content: message.Content}
+	removed, err := s.messageRepo.Tombstone(id, revision)
+	if err != nil {
+		return nil, err
+	}
+	s.attachments.RemoveFiles(removed)
+
+	message, err = s.messageRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	s.notifier.MessageUpdated(message)
+	return message, nil
+}
+
+// GetRevisions returns earlier versions of a message to participants. What a deleted message said
+// stays hidden from everyone but admins.
+func (s *messageService) GetRevisions(id, userID int, isAdmin bool) ([]*domain.MessageRevision, error) {
+	message, err := s.messageRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isAdmin {
+		isParticipant, err := s.participantRepo.IsParticipant(message.ConversationID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isParticipant {
+			return nil, errors.New("user is not a participant in this conversation")
+		}
+		if message.IsDeleted {
+			return nil, errors.New("message is deleted")
+		}
+	}
+
+	return s.messageRepo.GetRevisions(id)
+}
+
+func (s *messageService) ownMutableMessage(id, userID int) (*domain.Message, error) {
+	message, err := s.messageRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if message.IsSystem {
+		return nil, errors.New("system messages cannot be changed")
+	}
+	if message.SenderID == nil || *message.SenderID != userID {
+		return nil, errors.New("unauthorized: you can only change your own messages")
+	}
+	if message.IsDeleted {
+		return nil, errors.New("message is deleted")
+	}
+	if time.Since(message.CreatedAt) > s.editWindow {
+		return nil, fmt.Errorf("messages can only be changed within %s of sending", s.editWindow)
+	}
+	return message, nil
 }
 
 func (s *messageService) GetSystemMessages(conversationID int) ([]*domain.Message, error) {
 	return s.messageRepo.GetSystemMessages(conversationID)
 }
 
+func (s *messageService) GetLatestID(conversationID int) (int, error) {
+	return s.messageRepo.GetLatestID(conversationID)
+}
+
 func (s *messageService) SendSystemMessage(conversationID int, text string, orderID *int) error {
 	message := &domain.Message{
 		ConversationID: conversationID,
@@ -91,5 +459,11 @@ func (s *messageService) SendSystemMessage(conversationID int, text string, orde
 		OrderID:        orderID,
 	}
 
-	return s.messageRepo.Create(message)
+	if err := s.messageRepo.Create(message); err != nil {
+		return err
+	}
+
+	s.notifier.MessageCreated(message)
+	s.events.Publish(events.MessageSent{Message: message})
+	return nil
 }
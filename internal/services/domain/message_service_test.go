@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"reflect"
+	"testing"
+)
+
+// pagedMessages is a MessageRepository over messages of conversation 1 with IDs 1..n; only the
+// paging methods are implemented.
+type pagedMessages struct {
+	domain.MessageRepository
+	n int
+}
+
+func (r pagedMessages) GetBefore(conversationID, beforeID, limit int) ([]*domain.Message, error) {
+	var messages []*domain.Message
+	if conversationID != 1 {
+		return messages, nil
+	}
+	if beforeID == 0 {
+		beforeID = r.n + 1
+	}
+	for id := beforeID - 1; id >= 1 && len(messages) < limit; id-- {
+		messages = append(messages, &domain.Message{ID: id, ConversationID: conversationID})
+	}
+	return messages, nil
+}
+
+func (r pagedMessages) GetAfter(conversationID, afterID, limit int) ([]*domain.Message, error) {
+	var messages []*domain.Message
+	if conversationID != 1 {
+		return messages, nil
+	}
+	for id := afterID + 1; id <= r.n && len(messages) < limit; id++ {
+		messages = append(messages, &domain.Message{ID: id, ConversationID: conversationID})
+	}
+	return messages, nil
+}
+
+// onlyParticipant is a ParticipantRepository in which user 1 takes part in every conversation.
+type onlyParticipant struct {
+	domain.ParticipantRepository
+}
+
+func (onlyParticipant) IsParticipant(conversationID, userID int) (bool, error) {
+	return userID == 1, nil
+}
+
+func TestMessageGetPage(t *testing.T) {
+	tests := []struct {
+		name           string
+		conversationID int
+		cursor         domain.MessageCursor
+		limit          int
+		want           []int
+		hasOlder       bool
+		hasNewer       bool
+	}{
+		{"newest", 1, domain.MessageCursor{}, 4, []int{7, 8, 9, 10}, true, false},
+		{"default limit", 1, domain.MessageCursor{}, 0, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, false, false},
+		{"before", 1, domain.MessageCursor{Before: 7}, 4, []int{3, 4, 5, 6}, true, true},
+		{"before reaching the start", 1, domain.MessageCursor{Before: 5}, 4, []int{1, 2, 3, 4}, false, true},
+		{"after", 1, domain.MessageCursor{After: 2}, 4, []int{3, 4, 5, 6}, true, true},
+		{"after reaching the end", 1, domain.MessageCursor{After: 7}, 4, []int{8, 9, 10}, true, false},
+		{"after the newest", 1, domain.MessageCursor{After: 10}, 4, []int{}, true, false},
+		{"around", 1, domain.MessageCursor{Around: 5}, 4, []int{4, 5, 6, 7}, true, true},
+		{"around odd limit favours older", 1, domain.MessageCursor{Around: 5}, 3, []int{4, 5, 6}, true, true},
+		{"around near the start", 1, domain.MessageCursor{Around: 2}, 4, []int{1, 2, 3, 4}, false, true},
+		{"around the newest", 1, domain.MessageCursor{Around: 10}, 5, []int{8, 9, 10}, true, false},
+		{"empty conversation", 2, domain.MessageCursor{}, 4, []int{}, false, false},
+	}
+
+	service := &messageService{messageRepo: pagedMessages{n: 10}, participantRepo: onlyParticipant{}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := service.GetPage(tt.conversationID, 1, tt.cursor, tt.limit)
+			if err != nil {
+				t.Fatalf("GetPage(%+v, %d) returned error: %v", tt.cursor, tt.limit, err)
+			}
+
+			got := make([]int, 0, len(page.Messages))
+			for _, message := range page.Messages {
+				got = append(got, message.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPage(%+v, %d) messages = %v, want %v", tt.cursor, tt.limit, got, tt.want)
+			}
+			if page.HasOlder != tt.hasOlder || page.HasNewer != tt.hasNewer {
+				t.Errorf("GetPage(%+v, %d) has older/newer = %v/%v, want %v/%v",
+					tt.cursor, tt.limit, page.HasOlder, page.HasNewer, tt.hasOlder, tt.hasNewer)
+			}
+		})
+	}
+}
+
+func TestMessageGetPageRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID int
+		cursor domain.MessageCursor
+	}{
+		{"not a participant", 2, domain.MessageCursor{}},
+		{"negative cursor", 1, domain.MessageCursor{Before: -1}},
+		{"two cursors", 1, domain.MessageCursor{Before: 5, After: 2}},
+		{"all cursors", 1, domain.MessageCursor{Before: 5, After: 2, Around: 3}},
+	}
+
+	service := &messageService{messageRepo: pagedMessages{n: 10}, participantRepo: onlyParticipant{}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if page, err := service.GetPage(1, tt.userID, tt.cursor, 4); err == nil {
+				t.Errorf("GetPage(%+v) = %+v, want an error", tt.cursor, page)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	digestBatchSize = 100
+	digestMaxItems  = 20
+)
+
+// digestItem is one line of the digest email.
+type digestItem struct {
+	Type    string
+	URL     string
+	Payload map[string]interface{}
+}
+
+// NotificationDigestJob emails users who chose a daily or weekly digest a summary of the
+// notifications they have not read since the previous one. Users in quiet hours are picked up
+// on a later run. It is run by the scheduler.
+type NotificationDigestJob struct {
+	preferenceService   NotificationPreferenceService
+	notificationService NotificationService
+	userRepo            domain.UserRepository
+	emailService        EmailService
+	siteURL             string
+}
+
+func NewNotificationDigestJob(
+	preferenceService NotificationPreferenceService,
+	notificationService NotificationService,
+	uRepo domain.UserRepository,
+	emailService EmailService,
+	siteURL string,
+) *NotificationDigestJob {
+	return &NotificationDigestJob{
+		preferenceService:   preferenceService,
+		notificationService: notificationService,
+		userRepo:            uRepo,
+		emailService:        emailService,
+		siteURL:             siteURL,
+	}
+}
+
+func (j *NotificationDigestJob) Run(ctx context.Context) error {
+	due, err := j.preferenceService.GetDigestDue(digestBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to find digests to send: %w", err)
+	}
+
+	now := time.Now()
+	failed := 0
+	for _, preference := range due {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, quiet := preference.QuietUntil(now); quiet {
+			continue
+		}
+		if err := j.send(preference, now); err != nil {
+			log.Printf("Failed to send notification digest to user %d: %v", preference.UserID, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to send %d of %d digests", failed, len(due))
+	}
+	return nil
+}
+
+func (j *NotificationDigestJob) send(preference *domain.NotificationPreference, now time.Time) error {
+	since := now.Add(-domain.DigestPeriods[preference.DigestFrequency])
+	if preference.LastDigestAt != nil {
+		since = *preference.LastDigestAt
+	}
+
+	notifications, total, err := j.notificationService.GetUnreadSince(preference.UserID, since, digestMaxItems)
+	if err != nil {
+		return err
+	}
+
+	if total > 0 {
+		user, err := j.userRepo.GetByID(preference.UserID)
+		if err != nil {
+			return err
+		}
+
+		items := make([]digestItem, 0, len(notifications))
+		for _, notification := range notifications {
+			item := digestItem{Type: notification.Type, URL: j.siteURL + notification.Link}
+			if err := json.Unmarshal(notification.Payload, &item.Payload); err != nil {
+				return err
+			}
+			items = append(items, item)
+		}
+
+		err = j.emailService.Queue(user, "digest", map[string]interface{}{
+			"Count": int(total),
+			"Items": items,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return j.preferenceService.MarkDigestSent(preference.UserID, now)
+}
@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	_ "time/tzdata" // user timezones must resolve even where the OS has no zoneinfo
+)
+
+// NotificationDecision tells a dispatcher what to do with one notification.
+type NotificationDecision struct {
+	// Allowed is false when the user turned this event off for the channel.
+	Allowed bool
+	// Digest is set for email when the user prefers a periodic digest over single emails.
+	Digest bool
+	// QuietUntil is set during the user's quiet hours to the time they end.
+	QuietUntil *time.Time
+}
+
+// NotificationPreferenceUpdate lists the settings to change. Nil fields keep their current values;
+// Channels may list only the switches that change. Empty quiet hours turn them off.
+type NotificationPreferenceUpdate struct {
+	Channels        map[string]map[string]bool
+	Timezone        *string
+	QuietHoursStart *string
+	QuietHoursEnd   *string
+	DigestFrequency *string
+}
+
+type NotificationPreferenceService interface {
+	Get(userID int) (*domain.NotificationPreference, error)
+	Update(userID int, update NotificationPreferenceUpdate) (*domain.NotificationPreference, error)
+	Decide(userID int, eventType, channel string) (NotificationDecision, error)
+	GetDigestDue(limit int) ([]*domain.NotificationPreference, error)
+	MarkDigestSent(userID int, at time.Time) error
+}
+
+type notificationPreferenceService struct {
+	preferenceRepo domain.NotificationPreferenceRepository
+}
+
+func NewNotificationPreferenceService(pRepo domain.NotificationPreferenceRepository) NotificationPreferenceService {
+	return &notificationPreferenceService{preferenceRepo: pRepo}
+}
+
+// Get fills in every supported event type and channel, so clients can render the full matrix.
+func (s *notificationPreferenceService) Get(userID int) (*domain.NotificationPreference, error) {
+	preference, err := s.preferenceRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if preference == nil {
+		preference = domain.DefaultNotificationPreference(userID)
+	}
+
+	for eventType, channels := range domain.NotificationEventChannels {
+		if preference.Channels[eventType] == nil {
+			preference.Channels[eventType] = map[string]bool{}
+		}
+		for _, channel := range channels {
+			preference.Channels[eventType][channel] = preference.Allows(eventType, channel)
+		}
+	}
+	return preference, nil
+}
+
+// Update applies the settings given in update on top of the stored ones.
+func (s *notificationPreferenceService) Update(userID int, update NotificationPreferenceUpdate) (*domain.NotificationPreference, error) {
+	current, err := s.Get(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	timezone := current.Timezone
+	if update.Timezone != nil {
+		timezone = *update.Timezone
+	}
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", timezone)
+	}
+
+	quietStart, quietEnd := current.QuietHoursStart, current.QuietHoursEnd
+	if update.QuietHoursStart != nil {
+		quietStart = *update.QuietHoursStart
+	}
+	if update.QuietHoursEnd != nil {
+		quietEnd = *update.QuietHoursEnd
+	}
+	if (quietStart == "") != (quietEnd == "") {
+		return nil, errors.New("quiet hours need both a start and an end")
+	}
+	if quietStart != "" {
+		if _, err := domain.ParseClock(quietStart); err != nil {
+			return nil, err
+		}
+		if _, err := domain.ParseClock(quietEnd); err != nil {
+			return nil, err
+		}
+	}
+
+	digestFrequency := current.DigestFrequency
+	if update.DigestFrequency != nil {
+		digestFrequency = *update.DigestFrequency
+	}
+	if digestFrequency == "" {
+		digestFrequency = domain.DigestOff
+	}
+	if _, ok := domain.DigestPeriods[digestFrequency]; !ok && digestFrequency != domain.DigestOff {
+		return nil, fmt.Errorf("unknown digest frequency %q", digestFrequency)
+	}
+
+	for eventType, channels := range update.Channels {
+		supported, ok := domain.NotificationEventChannels[eventType]
+		if !ok {
+			return nil, fmt.Errorf("unknown event type %q", eventType)
+		}
+		for channel, enabled := range channels {
+			if !slices.Contains(supported, channel) {
+				return nil, fmt.Errorf("event type %q cannot be sent by %s", eventType, channel)
+			}
+			if current.Channels[eventType] == nil {
+				current.Channels[eventType] = map[string]bool{}
+			}
+			current.Channels[eventType][channel] = enabled
+		}
+	}
+
+	// The digest is built from in-app notifications, so an event that is emailed only as part of
+	// the digest must also be kept in the app.
+	if digestFrequency != domain.DigestOff {
+		for eventType := range domain.NotificationEventChannels {
+			if current.Allows(eventType, domain.NotificationChannelEmail) && !current.Allows(eventType, domain.NotificationChannelInApp) {
+				return nil, fmt.Errorf("event type %q needs in-app notifications on to be included in the email digest", eventType)
+			}
+		}
+	}
+
+	// The first digest covers what happened since it was turned on.
+	if digestFrequency != domain.DigestOff && current.DigestFrequency != digestFrequency {
+		now := time.Now()
+		current.LastDigestAt = &now
+	}
+
+	current.Timezone = timezone
+	current.QuietHoursStart = quietStart
+	current.QuietHoursEnd = quietEnd
+	current.DigestFrequency = digestFrequency
+	if err := s.preferenceRepo.Save(current); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+func (s *notificationPreferenceService) Decide(userID int, eventType, channel string) (NotificationDecision, error) {
+	preference, err := s.Get(userID)
+	if err != nil {
+		return NotificationDecision{}, err
+	}
+
+	decision := NotificationDecision{Allowed: preference.Allows(eventType, channel)}
+	if channel == domain.NotificationChannelEmail && preference.DigestFrequency != domain.DigestOff {
+		decision.Digest = true
+	}
+	// Webhooks feed the seller's own systems, which have no bedtime.
+	if channel != domain.NotificationChannelWebhook {
+		if until, quiet := preference.QuietUntil(time.Now()); quiet {
+			decision.QuietUntil = &until
+		}
+	}
+	return decision, nil
+}
+
+func (s *notificationPreferenceService) GetDigestDue(limit int) ([]*domain.NotificationPreference, error) {
+	return s.preferenceRepo.GetDigestDue(time.Now(), limit)
+}
+
+func (s *notificationPreferenceService) MarkDigestSent(userID int, at time.Time) error {
+	return s.preferenceRepo.SetLastDigestAt(userID, at)
+}
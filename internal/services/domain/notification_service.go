@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// NotificationNotifier pushes new notifications to the user's open tabs.
+type NotificationNotifier interface {
+	NotificationCreated(notification *domain.Notification)
+}
+
+type NotificationService interface {
+	Notify(userID int, kind, link string, payload interface{}) error
+	// NotifyLatest replaces the user's unread notification of the same kind and link, if any.
+	NotifyLatest(userID int, kind, link string, payload interface{}) error
+	GetNotifications(userID, limit, offset int) ([]*domain.Notification, int64, error)
+	CountUnread(userID int) (int64, error)
+	GetUnreadSince(userID int, since time.Time, limit int) ([]*domain.Notification, int64, error)
+	MarkRead(id, userID int) error
+	MarkAllRead(userID int) error
+}
+
+type notificationService struct {
+	notificationRepo domain.NotificationRepository
+	preferences      NotificationPreferenceService
+	notifier         NotificationNotifier
+}
+
+func NewNotificationService(nRepo domain.NotificationRepository, preferences NotificationPreferenceService, notifier NotificationNotifier) NotificationService {
+	return &notificationService{
+		notificationRepo: nRepo,
+		preferences:      preferences,
+		notifier:         notifier,
+	}
+}
+
+func (s *notificationService) Notify(userID int, kind, link string, payload interface{}) error {
+	return s.store(userID, kind, link, payload, s.notificationRepo.Create)
+}
+
+func (s *notificationService) NotifyLatest(userID int, kind, link string, payload interface{}) error {
+	return s.store(userID, kind, link, payload, s.notificationRepo.ReplaceUnread)
+}
+
+// store skips notifications the user turned off. During quiet hours the notification is kept
+// but not pushed, so it waits in the notification center without lighting up open tabs.
+func (s *notificationService) store(userID int, kind, link string, payload interface{}, save func(*domain.Notification) error) error {
+	decision, err := s.preferences.Decide(userID, kind, domain.NotificationChannelInApp)
+	if err != nil {
+		return err
+	}
+	if !decision.Allowed {
+		return nil
+	}
+
+	notification, err := newNotification(userID, kind, link, payload)
+	if err != nil {
+		return err
+	}
+	if err := save(notification); err != nil {
+		return err
+	}
+
+	if decision.QuietUntil == nil {
+		s.notifier.NotificationCreated(notification)
+	}
+	return nil
+}
+
+func (s *notificationService) GetNotifications(userID, limit, offset int) ([]*domain.Notification, int64, error) {
+	return s.notificationRepo.GetByUserID(userID, limit, offset)
+}
+
+func (s *notificationService) CountUnread(userID int) (int64, error) {
+	return s.notificationRepo.CountUnread(userID)
+}
+
+func (s *notificationService) GetUnreadSince(userID int, since time.Time, limit int) ([]*domain.Notification, int64, error) {
+	return s.notificationRepo.GetUnreadSince(userID, since, limit)
+}
+
+func (s *notificationService) MarkRead(id, userID int) error {
+	return s.notificationRepo.MarkRead(id, userID)
+}
+
+func (s *notificationService) MarkAllRead(userID int) error {
+	return s.notificationRepo.MarkAllRead(userID)
+}
+
+func newNotification(userID int, kind, link string, payload interface{}) (*domain.Notification, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Failed to encode %s notification for user %d: %v", kind, userID, err)
+		return nil, err
+	}
+
+	return &domain.Notification{
+		UserID:  userID,
+		Type:    kind,
+		Link:    link,
+		Payload: data,
+	}, nil
+}
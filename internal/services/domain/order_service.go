@@ -2,6 +2,7 @@ package domain
 
 import (
 	"MicroShopik/internal/domain"
+	"MicroShopik/internal/events"
 	"errors"
 )
 
@@ -15,15 +16,20 @@ type OrderService interface {
 	Delete(id int) error
 	UpdateStatus(id int, status string) error
 	GetByProductID(productID int) ([]*domain.Order, error)
+	SetConversationID(id, conversationID int) error
 }
 
+// orderService records OrderCreated and OrderStatusChanged in the outbox within the same
+// transaction as the order change.
 type orderService struct {
 	orderRepo domain.OrderRepository
+	outbox    OutboxService
 }
 
-func NewOrderService(oRepo domain.OrderRepository) OrderService {
+func NewOrderService(oRepo domain.OrderRepository, outbox OutboxService) OrderService {
 	return &orderService{
 		orderRepo: oRepo,
+		outbox:    outbox,
 	}
 }
 
@@ -52,7 +58,20 @@ func (s *orderService) Create(order *domain.Order) error {
 		return errors.New("product ID is required")
 	}
 
-	return s.orderRepo.Create(order)
+	tx, err := s.orderRepo.BeginTx()
+	if err != nil {
+		return err
+	}
+	defer s.orderRepo.Rollback(tx)
+
+	if err := s.orderRepo.CreateTx(tx, order); err != nil {
+		return err
+	}
+	if err := s.outbox.EnqueueTx(tx, events.OrderCreated{Order: order}); err != nil {
+		return err
+	}
+
+	return s.orderRepo.Commit(tx)
 }
 
 func (s *orderService) GetByID(id int) (*domain.Order, error) {
@@ -92,7 +111,35 @@ func (s *orderService) UpdateStatus(id int, status string) error {
 		return errors.New("invalid status")
 	}
 
-	return s.orderRepo.UpdateStatus(id, status)
+	order, err := s.orderRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.orderRepo.BeginTx()
+	if err != nil {
+		return err
+	}
+	defer s.orderRepo.Rollback(tx)
+
+	if err := s.orderRepo.UpdateStatusTx(tx, id, status); err != nil {
+		return err
+	}
+	if order.Status != status {
+		oldStatus := order.Status
+		order.Status = status
+		order.Messages = nil // keep the payload small; handlers reload what they need
+		changed := events.OrderStatusChanged{Order: order, OldStatus: oldStatus, NewStatus: status}
+		if err := s.outbox.EnqueueTx(tx, changed); err != nil {
+			return err
+		}
+	}
+
+	return s.orderRepo.Commit(tx)
+}
+
+func (s *orderService) SetConversationID(id, conversationID int) error {
+	return s.orderRepo.SetConversationID(id, conversationID)
 }
 
 func (s *orderService) GetByProductID(productID int) ([]*domain.Order, error) {
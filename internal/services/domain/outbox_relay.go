@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"MicroShopik/internal/events"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	outboxBatchSize   = 50
+	outboxBaseBackoff = 30 * time.Second
+	outboxMaxBackoff  = time.Hour
+	// outboxLease is how long a claimed batch is hidden from other relays. It must outlast
+	// the slowest handler run for a whole batch, or two relays may deliver the same event.
+	outboxLease = 5 * time.Minute
+)
+
+type outboxHandler struct {
+	subscriber string
+	handle     events.Handler
+}
+
+// OutboxRelay delivers outbox events to their handlers. Delivery is at least once: when some
+// handlers fail, the event is retried after a growing delay with only the handlers that have
+// not succeeded yet. A handler may still see an event twice if the relay dies right after it
+// ran, so handlers should tolerate that. After maxAttempts failures the event is marked dead
+// and waits for an admin.
+type OutboxRelay struct {
+	outboxRepo  domain.OutboxRepository
+	handlers    map[string][]outboxHandler
+	interval    time.Duration
+	maxAttempts int
+	ctx         context.Context
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+func NewOutboxRelay(oRepo domain.OutboxRepository, interval time.Duration, maxAttempts int) *OutboxRelay {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &OutboxRelay{
+		outboxRepo:  oRepo,
+		handlers:    make(map[string][]outboxHandler),
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		ctx:         ctx,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+}
+
+// Handle registers a handler for an event name. Register everything before Start. Subscriber
+// names must be unique per event and stable across releases, since delivery is recorded by name.
+func (r *OutboxRelay) Handle(eventName, subscriber string, handler events.Handler) {
+	r.handlers[eventName] = append(r.handlers[eventName], outboxHandler{subscriber: subscriber, handle: handler})
+}
+
+func (r *OutboxRelay) Start() {
+	log.Printf("Starting outbox relay with interval %v", r.interval)
+
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.drain()
+			case <-r.ctx.Done():
+				log.Println("Outbox relay stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop waits for the batch in progress so its results are committed.
+func (r *OutboxRelay) Stop() {
+	log.Println("Stopping outbox relay...")
+	r.cancel()
+	<-r.done
+}
+
+func (r *OutboxRelay) drain() {
+	for r.ctx.Err() == nil {
+		claimed, err := r.relayBatch()
+		if err != nil {
+			log.Printf("Failed to relay outbox events: %v", err)
+			return
+		}
+		if claimed < outboxBatchSize {
+			return
+		}
+	}
+}
+
+// relayBatch leases a batch instead of holding row locks while slow handlers such as SMTP run.
+// If the process dies midway, the events come due again when the lease runs out.
+func (r *OutboxRelay) relayBatch() (int, error) {
+	batch, err := r.outboxRepo.ClaimDue(outboxBatchSize, outboxLease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range batch {
+		event.Attempts++
+		if err := r.dispatch(event); err != nil {
+			event.LastError = err.Error()
+			if event.Attempts >= r.maxAttempts {
+				event.Status = domain.OutboxStatusDead
+				log.Printf("Outbox event %d (%s) is dead after %d attempts: %v", event.ID, event.EventName, event.Attempts, err)
+			} else {
+				event.NextAttemptAt = time.Now().Add(outboxBackoff(event.Attempts))
+			}
+		} else {
+			now := time.Now()
+			event.Status = domain.OutboxStatusDelivered
+			event.DeliveredAt = &now
+			event.LastError = ""
+		}
+
+		if err := r.outboxRepo.Update(event); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(batch), nil
+}
+
+// dispatch calls the handlers that have not handled the event yet and adds each one that
+// succeeds to stored.DeliveredTo.
+func (r *OutboxRelay) dispatch(stored *domain.OutboxEvent) error {
+	event, err := events.Decode(stored.EventName, stored.Payload)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, handler := range r.handlers[stored.EventName] {
+		if slices.Contains(stored.DeliveredTo, handler.subscriber) {
+			continue
+		}
+		if err := r.call(handler, event); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", handler.subscriber, err))
+			continue
+		}
+		stored.DeliveredTo = append(stored.DeliveredTo, handler.subscriber)
+	}
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+func (r *OutboxRelay) call(handler outboxHandler, event events.Event) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return handler.handle(event)
+}
+
+// outboxBackoff doubles the delay after every failed attempt: 30s, 1m, 2m, ... up to an hour.
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxBaseBackoff
+	for i := 1; i < attempts && delay < outboxMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > outboxMaxBackoff {
+		delay = outboxMaxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"MicroShopik/internal/events"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OutboxService interface {
+	// EnqueueTx stores the event in the caller's transaction, so it exists exactly when the change does.
+	EnqueueTx(tx *gorm.DB, event events.Event) error
+	// Enqueue stores an event that doesn't belong to a larger change.
+	Enqueue(event events.Event) error
+	// EnqueueAt stores an event that the relay must not deliver before at.
+	EnqueueAt(event events.Event, at time.Time) error
+	GetEvents(status string, limit, offset int) ([]*domain.OutboxEvent, int64, error)
+	CountByStatus() (map[string]int64, error)
+	Retry(id int) error
+}
+
+type outboxService struct {
+	outboxRepo domain.OutboxRepository
+}
+
+func NewOutboxService(oRepo domain.OutboxRepository) OutboxService {
+	return &outboxService{outboxRepo: oRepo}
+}
+
+func (s *outboxService) EnqueueTx(tx *gorm.DB, event events.Event) error {
+	stored, err := newOutboxEvent(event)
+	if err != nil {
+		return err
+	}
+	return s.outboxRepo.CreateTx(tx, stored)
+}
+
+func (s *outboxService) Enqueue(event events.Event) error {
+	stored, err := newOutboxEvent(event)
+	if err != nil {
+		return err
+	}
+	return s.outboxRepo.Create(stored)
+}
+
+func (s *outboxService) EnqueueAt(event events.Event, at time.Time) error {
+	stored, err := newOutboxEvent(event)
+	if err != nil {
+		return err
+	}
+	stored.NextAttemptAt = at
+	return s.outboxRepo.Create(stored)
+}
+
+func newOutboxEvent(event events.Event) (*domain.OutboxEvent, error) {
+	payload, err := events.Encode(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.OutboxEvent{
+		EventName:     event.Name(),
+		Payload:       payload,
+		Status:        domain.OutboxStatusPending,
+		NextAttemptAt: time.Now(),
+	}, nil
+}
+
+func (s *outboxService) GetEvents(status string, limit, offset int) ([]*domain.OutboxEvent, int64, error) {
+	return s.outboxRepo.GetByStatus(status, limit, offset)
+}
+
+func (s *outboxService) CountByStatus() (map[string]int64, error) {
+	return s.outboxRepo.CountByStatus()
+}
+
+func (s *outboxService) Retry(id int) error {
+	return s.outboxRepo.Requeue(id)
+}
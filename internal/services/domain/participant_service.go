@@ -13,19 +13,23 @@ type ParticipantService interface {
 	DeleteByConversationAndUser(conversationID, userID int) error
 	IsParticipant(conversationID, userID int) (bool, error)
 	GetConversationParticipants(conversationID int) ([]*domain.Participant, error)
+	MarkRead(conversationID, userID, messageID int) error
+	GetUnreadCounts(userID int) (map[int]int, error)
 }
 
 type participantService struct {
 	participantRepo  domain.ParticipantRepository
 	conversationRepo domain.ConversationRepository
 	userRepo         domain.UserRepository
+	notifier         ConversationNotifier
 }
 
-func NewParticipantService(pRepo domain.ParticipantRepository, cRepo domain.ConversationRepository, uRepo domain.UserRepository) ParticipantService {
+func NewParticipantService(pRepo domain.ParticipantRepository, cRepo domain.ConversationRepository, uRepo domain.UserRepository, notifier ConversationNotifier) ParticipantService {
 	return &participantService{
 		participantRepo:  pRepo,
 		conversationRepo: cRepo,
 		userRepo:         uRepo,
+		notifier:         notifier,
 	}
 }
 
@@ -43,7 +47,12 @@ func (s *participantService) Create(participant *domain.Participant) error {
 		return errors.New("user is already a participant")
 	}
 
-	return s.participantRepo.Create(participant)
+	if err := s.participantRepo.Create(participant); err != nil {
+		return err
+	}
+
+	s.notifier.ParticipantJoined(participant.ConversationID, participant.UserID)
+	return nil
 }
 
 func (s *participantService) GetByConversationID(conversationID int) ([]*domain.Participant, error) {
@@ -63,7 +72,7 @@ func (s *participantService) Delete(conversationID, userID int) error {
 		return errors.New("user is not a participant")
 	}
 
-	return s.participantRepo.Delete(conversationID, userID)
+	return s.DeleteByConversationAndUser(conversationID, userID)
 }
 
 func (s *participantService) IsParticipant(conversationID, userID int) (bool, error) {
@@ -74,6 +83,29 @@ func (s *participantService) GetConversationParticipants(conversationID int) ([]
 	return s.participantRepo.GetByConversationID(conversationID)
 }
 
+// MarkRead records that the user has read the conversation up to messageID and tells the
+// other participants. Marking an older message than the current marker is a no-op.
+func (s *participantService) MarkRead(conversationID, userID, messageID int) error {
+	changed, err := s.participantRepo.MarkRead(conversationID, userID, messageID)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		s.notifier.ConversationRead(conversationID, userID, messageID)
+	}
+	return nil
+}
+
+func (s *participantService) GetUnreadCounts(userID int) (map[int]int, error) {
+	return s.participantRepo.GetUnreadCounts(userID)
+}
+
 func (s *participantService) DeleteByConversationAndUser(conversationID, userID int) error {
-	return s.participantRepo.Delete(conversationID, userID)
+	if err := s.participantRepo.Delete(conversationID, userID); err != nil {
+		return err
+	}
+
+	s.notifier.ParticipantLeft(conversationID, userID)
+	return nil
 }
@@ -0,0 +1,47 @@
+package domain
+
+import "MicroShopik/internal/domain"
+
+// PresenceTracker knows which users currently have a live connection.
+type PresenceTracker interface {
+	IsOnline(userID int) bool
+}
+
+type PresenceService interface {
+	Get(userID, viewerID int) (*domain.Presence, error)
+	SetHidden(userID int, hidden bool) error
+}
+
+type presenceService struct {
+	userRepo domain.UserRepository
+	tracker  PresenceTracker
+}
+
+func NewPresenceService(userRepo domain.UserRepository, tracker PresenceTracker) PresenceService {
+	return &presenceService{userRepo: userRepo, tracker: tracker}
+}
+
+// Get returns the user's presence as viewerID may see it. Users who hide their status
+// appear offline to everyone else; they still see their own state.
+func (s *presenceService) Get(userID, viewerID int) (*domain.Presence, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	presence := &domain.Presence{UserID: user.ID, Hidden: user.HideOnlineStatus}
+	if user.HideOnlineStatus && userID != viewerID {
+		return presence, nil
+	}
+
+	presence.Online = s.tracker.IsOnline(user.ID)
+	presence.LastSeenAt = user.LastSeenAt
+	return presence, nil
+}
+
+func (s *presenceService) SetHidden(userID int, hidden bool) error {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return err
+	}
+	return s.userRepo.SetHideOnlineStatus(userID, hidden)
+}
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+	"log"
+)
+
+type ProductModerationService interface {
+	IsEnabled() bool
+	RequiresReview(existing *domain.Product, updated *domain.Product) bool
+	Submit(productID, sellerID int, action string) error
+	Resubmit(productID, sellerID int) error
+	Approve(productID, moderatorID int, comment string) error
+	Reject(productID, moderatorID int, reasonCode, comment string) error
+	GetQueue(limit, offset int) ([]*domain.Product, int, error)
+	GetHistory(productID int) ([]*domain.ProductModerationLog, error)
+}
+
+type productModerationService struct {
+	productRepo domain.ProductRepository
+	logRepo     domain.ProductModerationLogRepository
+	enabled     bool
+}
+
+func NewProductModerationService(pRepo domain.ProductRepository, lRepo domain.ProductModerationLogRepository, enabled bool) ProductModerationService {
+	return &productModerationService{
+		productRepo: pRepo,
+		logRepo:     lRepo,
+		enabled:     enabled,
+	}
+}
+
+func (s *productModerationService) IsEnabled() bool {
+	return s.enabled
+}
+
+// RequiresReview reports whether an edit changes what the buyer sees enough to need another review.
+// Price, stock and activation changes are left to the seller.
+func (s *productModerationService) RequiresReview(existing *domain.Product, updated *domain.Product) bool {
+	if !s.enabled || existing == nil || updated == nil {
+		return false
+	}
+	if updated.Title != "" && updated.Title != existing.Title {
+		return true
+	}
+	if updated.Description != "" && updated.Description != existing.Description {
+		return true
+	}
+	if updated.CategoryID > 0 && updated.CategoryID != existing.CategoryID {
+		return true
+	}
+	return false
+}
+
+func (s *productModerationService) Submit(productID, sellerID int, action string) error {
+	if err := s.productRepo.UpdateModerationStatus(productID, domain.ProductStatusPendingReview, false, "", ""); err != nil {
+		return err
+	}
+	return s.record(productID, sellerID, action, "", "")
+}
+
+func (s *productModerationService) Resubmit(productID, sellerID int) error {
+	product, err := s.productRepo.GetById(productID)
+	if err != nil {
+		return err
+	}
+	if product.SellerID != sellerID {
+		return errors.New("unauthorized: you can only resubmit your own products")
+	}
+	if product.ModerationStatus != domain.ProductStatusRejected {
+		return errors.New("only rejected products can be resubmitted")
+	}
+
+	return s.Submit(productID, sellerID, domain.ModerationActionResubmitted)
+}
+
+func (s *productModerationService) Approve(productID, moderatorID int, comment string) error {
+	product, err := s.productRepo.GetById(productID)
+	if err != nil {
+		return err
+	}
+	if product.ModerationStatus != domain.ProductStatusPendingReview {
+		return errors.New("product is not pending review")
+	}
+
+	if err := s.productRepo.UpdateModerationStatus(productID, domain.ProductStatusApproved, true, "", ""); err != nil {
+		return err
+	}
+	return s.record(productID, moderatorID, domain.ModerationActionApproved, "", comment)
+}
+
+func (s *productModerationService) Reject(productID, moderatorID int, reasonCode, comment string) error {
+	description, ok := domain.ProductRejectionReasons[reasonCode]
+	if !ok {
+		return errors.New("invalid rejection reason code")
+	}
+	if reasonCode == "other" && comment == "" {
+		return errors.New("comment is required for reason code 'other'")
+	}
+
+	product, err := s.productRepo.GetById(productID)
+	if err != nil {
+		return err
+	}
+	if product.ModerationStatus == domain.ProductStatusRejected {
+		return errors.New("product is already rejected")
+	}
+
+	reason := description
+	if comment != "" {
+		reason = comment
+	}
+
+	if err := s.productRepo.UpdateModerationStatus(productID, domain.ProductStatusRejected, false, reasonCode, reason); err != nil {
+		return err
+	}
+	return s.record(productID, moderatorID, domain.ModerationActionRejected, reasonCode, comment)
+}
+
+func (s *productModerationService) GetQueue(limit, offset int) ([]*domain.Product, int, error) {
+	status := domain.ProductStatusPendingReview
+
+	products, err := s.productRepo.GetByModerationStatus(status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.productRepo.Count(domain.ProductQueryParams{ModerationStatus: &status})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return products, total, nil
+}
+
+func (s *productModerationService) GetHistory(productID int) ([]*domain.ProductModerationLog, error) {
+	return s.logRepo.GetByProductID(productID)
+}
+
+func (s *productModerationService) record(productID, actorID int, action, reasonCode, comment string) error {
+	log.Printf("Product moderation: product=%d action=%s actor=%d reason=%q", productID, action, actorID, reasonCode)
+
+	return s.logRepo.Create(&domain.ProductModerationLog{
+		ProductID:  productID,
+		ActorID:    actorID,
+		Action:     action,
+		ReasonCode: reasonCode,
+		Comment:    comment,
+	})
+}
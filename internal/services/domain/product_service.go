@@ -2,6 +2,7 @@ package domain
 
 import (
 	"MicroShopik/internal/domain"
+	"encoding/json"
 	"errors"
 	"time"
 )
@@ -9,7 +10,10 @@ import (
 type ProductService interface {
 	Create(p *domain.Product, userID int) (int, error)
 	GetById(id int) (*domain.Product, error)
+	GetBySellerAndSKU(sellerID int, sku string) (*domain.Product, error)
+	GetBySlug(slug string) (*domain.Product, error)
 	Update(id int, product *domain.Product, userID int) error
+	UpdateFields(id int, data domain.ProductUpdateData, userID int) error
 	Delete(id int, userID int) error
 	Find(params domain.ProductQueryParams) ([]*domain.Product, error)
 	Count(params domain.ProductQueryParams) (int, error)
@@ -19,31 +23,34 @@ type ProductService interface {
 	ValidateProductExists(productID int) error
 	ReserveProduct(productID int) error
 	ReleaseProduct(productID int) error
+	GetRevisions(productID int) ([]*domain.ProductRevision, error)
 }
 
 type productService struct {
-	productRepo domain.ProductRepository
+	productRepo  domain.ProductRepository
+	revisionRepo domain.ProductRevisionRepository
+	slugService  SlugService
 }
 
-func NewProductService(r domain.ProductRepository) ProductService {
-	return &productService{productRepo: r}
+func NewProductService(r domain.ProductRepository, revRepo domain.ProductRevisionRepository, slugService SlugService) ProductService {
+	return &productService{productRepo: r, revisionRepo: revRepo, slugService: slugService}
 }
 
 func (s *productService) Create(p *domain.Product, userID int) (int, error) {
 	if p == nil {
 		return 0, errors.New("product is nil")
 	}
-	if len(p.Title) <= 0 {
-		return 0, errors.New("product title is empty")
+	if err := validateProductFields(domain.ProductUpdateData{Title: &p.Title, Description: &p.Description, Price: &p.Price}); err != nil {
+		return 0, err
 	}
-	if len(p.Description) <= 0 {
-		return 0, errors.New("product description is empty")
-	}
-	if p.Price <= 0 {
-		return 0, errors.New("product price is zero")
+
+	slug, err := s.slugService.Generate(domain.SlugEntityProduct, p.Title, 0)
+	if err != nil {
+		return 0, err
 	}
 
 	p.SellerID = userID
+	p.Slug = &slug
 	p.CreatedAt = time.Now()
 
 	return s.productRepo.Create(p)
@@ -54,20 +61,20 @@ func (s *productService) GetById(id int) (*domain.Product, error) {
 	return s.productRepo.GetById(id)
 }
 
+func (s *productService) GetBySellerAndSKU(sellerID int, sku string) (*domain.Product, error) {
+	return s.productRepo.GetBySellerAndSKU(sellerID, sku)
+}
+
+func (s *productService) GetBySlug(slug string) (*domain.Product, error) {
+	return s.productRepo.GetBySlug(slug)
+}
+
 func (s *productService) Update(id int, product *domain.Product, userID int) error {
 
 	if product == nil {
 		return errors.New("product is nil")
 	}
 
-	existingProduct, err := s.productRepo.GetById(id)
-	if err != nil {
-		return err
-	}
-	if existingProduct.SellerID != userID {
-		return errors.New("unauthorized: you can only update your own products")
-	}
-
 	updateData := domain.ProductUpdateData{}
 	if product.Title != "" {
 		updateData.Title = &product.Title
@@ -87,8 +94,113 @@ func (s *productService) Update(id int, product *domain.Product, userID int) err
 	if product.MaxSales > 0 {
 		updateData.MaxSales = &product.MaxSales
 	}
-	return s.productRepo.Update(id, updateData)
 
+	return s.UpdateFields(id, updateData, userID)
+}
+
+// UpdateFields writes every non-nil field of data as given, zero values included, and records
+// the difference as a revision.
+func (s *productService) UpdateFields(id int, updateData domain.ProductUpdateData, userID int) error {
+	if err := validateProductFields(updateData); err != nil {
+		return err
+	}
+
+	existingProduct, err := s.productRepo.GetById(id)
+	if err != nil {
+		return err
+	}
+	if existingProduct.SellerID != userID {
+		return errors.New("unauthorized: you can only update your own products")
+	}
+
+	changes := productChanges(existingProduct, updateData)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	oldSlug := ""
+	if existingProduct.Slug != nil {
+		oldSlug = *existingProduct.Slug
+	}
+	if _, titleChanged := changes["title"]; titleChanged {
+		slug, err := s.slugService.Generate(domain.SlugEntityProduct, *updateData.Title, id)
+		if err != nil {
+			return err
+		}
+		if slug != oldSlug {
+			updateData.Slug = &slug
+		}
+	}
+
+	revision := &domain.ProductRevision{
+		ProductID: id,
+		ActorID:   userID,
+		Changes:   encoded,
+	}
+	if err := s.productRepo.UpdateWithRevision(id, updateData, revision); err != nil {
+		return err
+	}
+
+	if updateData.Slug != nil {
+		return s.slugService.RecordChange(domain.SlugEntityProduct, id, oldSlug, *updateData.Slug)
+	}
+	return nil
+}
+
+func (s *productService) GetRevisions(productID int) ([]*domain.ProductRevision, error) {
+	return s.revisionRepo.GetByProductID(productID)
+}
+
+// validateProductFields checks the fields that are set, so Create and every kind of update
+// reject the same values.
+func validateProductFields(data domain.ProductUpdateData) error {
+	if data.Title != nil && len(*data.Title) <= 0 {
+		return errors.New("product title is empty")
+	}
+	if data.Description != nil && len(*data.Description) <= 0 {
+		return errors.New("product description is empty")
+	}
+	if data.Price != nil && *data.Price <= 0 {
+		return errors.New("product price is zero")
+	}
+	if data.MaxSales != nil && *data.MaxSales < 0 {
+		return errors.New("product max sales is negative")
+	}
+	return nil
+}
+
+// productChanges returns the fields of the update that differ from the stored product, keyed by column name.
+func productChanges(existing *domain.Product, data domain.ProductUpdateData) map[string]domain.ProductFieldChange {
+	changes := make(map[string]domain.ProductFieldChange)
+
+	if data.Title != nil && *data.Title != existing.Title {
+		changes["title"] = domain.ProductFieldChange{Old: existing.Title, New: *data.Title}
+	}
+	if data.Description != nil && *data.Description != existing.Description {
+		changes["description"] = domain.ProductFieldChange{Old: existing.Description, New: *data.Description}
+	}
+	if data.Price != nil && *data.Price != existing.Price {
+		changes["price"] = domain.ProductFieldChange{Old: existing.Price, New: *data.Price}
+	}
+	if data.CategoryID != nil && *data.CategoryID != existing.CategoryID {
+		changes["category_id"] = domain.ProductFieldChange{Old: existing.CategoryID, New: *data.CategoryID}
+	}
+	if data.IsActive != nil && *data.IsActive != existing.IsActive {
+		changes["is_active"] = domain.ProductFieldChange{Old: existing.IsActive, New: *data.IsActive}
+	}
+	if data.Disposable != nil && *data.Disposable != existing.Disposable {
+		changes["disposable"] = domain.ProductFieldChange{Old: existing.Disposable, New: *data.Disposable}
+	}
+	if data.MaxSales != nil && *data.MaxSales != existing.MaxSales {
+		changes["max_sales"] = domain.ProductFieldChange{Old: existing.MaxSales, New: *data.MaxSales}
+	}
+
+	return changes
 }
 
 func (s *productService) Delete(id int, userID int) error {
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"log"
+)
+
+// minCoPurchaseScore is the number of distinct buyers a pair needs before it is trusted as a recommendation.
+const minCoPurchaseScore = 2
+
+type RecommendationService interface {
+	RebuildCoPurchaseMatrix() error
+	GetRelated(productID, limit int) ([]*domain.Product, error)
+}
+
+type recommendationService struct {
+	recommendationRepo domain.ProductRecommendationRepository
+	productRepo        domain.ProductRepository
+}
+
+func NewRecommendationService(rRepo domain.ProductRecommendationRepository, pRepo domain.ProductRepository) RecommendationService {
+	return &recommendationService{
+		recommendationRepo: rRepo,
+		productRepo:        pRepo,
+	}
+}
+
+func (s *recommendationService) RebuildCoPurchaseMatrix() error {
+	pairs, err := s.recommendationRepo.RebuildCoPurchases([]string{"confirmed", "completed"})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Co-purchase matrix rebuilt with %d product pairs", pairs)
+	return nil
+}
+
+// GetRelated returns products bought by the same customers, topped up with popular products
+// from the same category when there is not enough order history.
+func (s *recommendationService) GetRelated(productID, limit int) ([]*domain.Product, error) {
+	product, err := s.productRepo.GetById(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	related, err := s.recommendationRepo.GetCoPurchased(productID, minCoPurchaseScore, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(related) >= limit {
+		return related, nil
+	}
+
+	excludeIDs := []int{productID}
+	for _, p := range related {
+		excludeIDs = append(excludeIDs, p.ID)
+	}
+
+	popular, err := s.recommendationRepo.GetPopularInCategory(product.CategoryID, excludeIDs, limit-len(related))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(related, popular...), nil
+}
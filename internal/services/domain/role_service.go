@@ -17,14 +17,16 @@ type RoleService interface {
 }
 
 type roleService struct {
-	roleRepo domain.RoleRepository
-	userRepo domain.UserRepository
+	roleRepo       domain.RoleRepository
+	userRepo       domain.UserRepository
+	sessionService SessionService
 }
 
-func NewRoleService(roleRepo domain.RoleRepository, userRepo domain.UserRepository) RoleService {
+func NewRoleService(roleRepo domain.RoleRepository, userRepo domain.UserRepository, sessionService SessionService) RoleService {
 	return &roleService{
-		roleRepo: roleRepo,
-		userRepo: userRepo,
+		roleRepo:       roleRepo,
+		userRepo:       userRepo,
+		sessionService: sessionService,
 	}
 }
 
@@ -58,8 +60,12 @@ func (s *roleService) AssignRoleToUser(userID int, roleName string) error {
 	return s.userRepo.AssignRole(userID, roleName)
 }
 
+// RemoveRoleFromUser also ends the user's sessions, since their tokens still carry the role.
 func (s *roleService) RemoveRoleFromUser(userID int, roleName string) error {
-	return s.userRepo.RemoveRole(userID, roleName)
+	if err := s.userRepo.RemoveRole(userID, roleName); err != nil {
+		return err
+	}
+	return s.sessionService.RevokeAll(userID, domain.SessionRevokeRoleChange)
 }
 
 func (s *roleService) GetUserRoles(userID int) ([]string, error) {
@@ -0,0 +1,348 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrSessionNotFound     = errors.New("session not found")
+)
+
+// sessionCheckTTL is how long a session check is cached. Sessions revoked on another instance
+// keep their access tokens working for at most this long.
+const sessionCheckTTL = 30 * time.Second
+
+// sessionCheckCacheSize is how many cached checks trigger a sweep of the stale ones.
+const sessionCheckCacheSize = 10000
+
+// SessionNotifier is told when sessions end, so connections opened with their access tokens
+// can be closed.
+type SessionNotifier interface {
+	// SessionsRevoked covers one session of the user, or all of them when sessionID is empty.
+	SessionsRevoked(userID int, sessionID string)
+}
+
+type SessionService interface {
+	// Start opens a session for a user loaded with roles.
+	Start(user *domain.User, client domain.SessionClient) (*domain.AuthTokens, error)
+	// Refresh trades a refresh token for a new pair. The access token carries the user's
+	// current roles.
+	Refresh(refreshToken string, client domain.SessionClient) (*domain.AuthTokens, *domain.User, error)
+	// Logout ends the session of the refresh token. Unknown or already revoked tokens are not
+	// an error.
+	Logout(refreshToken string) error
+	// GetActive lists the user's live sessions, marking the one with currentID.
+	GetActive(userID int, currentID string) ([]*domain.Session, error)
+	Revoke(userID int, sessionID string) error
+	// RevokeAll ends every session of the user, so new roles or a new password take effect as
+	// soon as the session checks see it.
+	RevokeAll(userID int, reason string) error
+	// IsRevoked reports whether the session an access token was issued for has ended.
+	IsRevoked(userID int, sessionID string) (bool, error)
+	DeleteExpired() (int64, error)
+}
+
+type sessionCheck struct {
+	userID    int
+	revoked   bool
+	checkedAt time.Time
+}
+
+type sessionService struct {
+	sessionRepo domain.SessionRepository
+	userRepo    domain.UserRepository
+	jwtSecret   string
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
+	notifier    SessionNotifier
+
+	mu     sync.Mutex
+	checks map[string]sessionCheck
+}
+
+func NewSessionService(
+	sRepo domain.SessionRepository,
+	uRepo domain.UserRepository,
+	jwtSecret string,
+	accessTTL time.Duration,
+	refreshTTL time.Duration,
+	notifier SessionNotifier,
+) SessionService {
+	return &sessionService{
+		sessionRepo: sRepo,
+		userRepo:    uRepo,
+		jwtSecret:   jwtSecret,
+		accessTTL:   accessTTL,
+		refreshTTL:  refreshTTL,
+		notifier:    notifier,
+		checks:      make(map[string]sessionCheck),
+	}
+}
+
+func (s *sessionService) Start(user *domain.User, client domain.SessionClient) (*domain.AuthTokens, error) {
+	familyID, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.sessionRepo.BeginTx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	refreshToken, err := s.issueRefreshToken(tx, user.ID, familyID, now, client, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return s.tokens(user, familyID, refreshToken, now)
+}
+
+func (s *sessionService) Refresh(refreshToken string, client domain.SessionClient) (*domain.AuthTokens, *domain.User, error) {
+	tx, err := s.sessionRepo.BeginTx()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	session, err := s.sessionRepo.GetByTokenHashTx(tx, hashToken(refreshToken))
+	if err != nil {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	now := time.Now()
+	switch {
+	case session.RevokedAt != nil, !session.ExpiresAt.After(now):
+		return nil, nil, ErrInvalidRefreshToken
+	case session.RotatedAt != nil:
+		// The token was already traded in, so someone else holds a copy. Whoever is using the
+		// family now, legitimately or not, has to log in again.
+		if err := s.sessionRepo.RevokeFamilyTx(tx, session.FamilyID, domain.SessionRevokeReuse, now); err != nil {
+			return nil, nil, err
+		}
+		if err := tx.Commit().Error; err != nil {
+			return nil, nil, err
+		}
+		log.Printf("Refresh token reuse detected for session %s of user %d, session revoked", session.FamilyID, session.UserID)
+		s.revoked(session.UserID, session.FamilyID)
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.userRepo.GetByID(session.UserID)
+	if err != nil {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	session.RotatedAt = &now
+	if err := s.sessionRepo.UpdateTx(tx, session); err != nil {
+		return nil, nil, err
+	}
+
+	newToken, err := s.issueRefreshToken(tx, user.ID, session.FamilyID, session.StartedAt, client, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.tokens(user, session.FamilyID, newToken, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokens, user, nil
+}
+
+func (s *sessionService) Logout(refreshToken string) error {
+	tx, err := s.sessionRepo.BeginTx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	session, err := s.sessionRepo.GetByTokenHashTx(tx, hashToken(refreshToken))
+	if err != nil {
+		return nil
+	}
+	if err := s.sessionRepo.RevokeFamilyTx(tx, session.FamilyID, domain.SessionRevokeLogout, time.Now()); err != nil {
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	s.revoked(session.UserID, session.FamilyID)
+	return nil
+}
+
+func (s *sessionService) GetActive(userID int, currentID string) ([]*domain.Session, error) {
+	sessions, err := s.sessionRepo.GetActiveByUserID(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		session.Current = session.FamilyID == currentID
+	}
+	return sessions, nil
+}
+
+func (s *sessionService) Revoke(userID int, sessionID string) error {
+	revoked, err := s.sessionRepo.RevokeUserFamily(userID, sessionID, domain.SessionRevokeUser, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+	s.revoked(userID, sessionID)
+	return nil
+}
+
+func (s *sessionService) RevokeAll(userID int, reason string) error {
+	if err := s.sessionRepo.RevokeAllByUserID(userID, reason, time.Now()); err != nil {
+		return err
+	}
+	s.revoked(userID, "")
+	return nil
+}
+
+func (s *sessionService) IsRevoked(userID int, sessionID string) (bool, error) {
+	now := time.Now()
+	s.mu.Lock()
+	check, ok := s.checks[sessionID]
+	s.mu.Unlock()
+	if ok && check.userID == userID && now.Sub(check.checkedAt) < sessionCheckTTL {
+		return check.revoked, nil
+	}
+
+	revoked, err := s.sessionRepo.IsFamilyRevoked(sessionID)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.checks) >= sessionCheckCacheSize {
+		for id, check := range s.checks {
+			if now.Sub(check.checkedAt) >= sessionCheckTTL {
+				delete(s.checks, id)
+			}
+		}
+	}
+	s.checks[sessionID] = sessionCheck{userID: userID, revoked: revoked, checkedAt: now}
+	return revoked, nil
+}
+
+// revoked marks one session of the user, or all of them when sessionID is empty, as ended on
+// this instance right away and closes their connections.
+func (s *sessionService) revoked(userID int, sessionID string) {
+	s.mu.Lock()
+	now := time.Now()
+	if sessionID != "" {
+		s.checks[sessionID] = sessionCheck{userID: userID, revoked: true, checkedAt: now}
+	} else {
+		for id, check := range s.checks {
+			if check.userID == userID {
+				s.checks[id] = sessionCheck{userID: userID, revoked: true, checkedAt: now}
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	s.notifier.SessionsRevoked(userID, sessionID)
+}
+
+func (s *sessionService) DeleteExpired() (int64, error) {
+	return s.sessionRepo.DeleteExpired(time.Now())
+}
+
+// issueRefreshToken adds a token to the family and returns it; only its hash is stored.
+func (s *sessionService) issueRefreshToken(tx *gorm.DB, userID int, familyID string, startedAt time.Time, client domain.SessionClient, now time.Time) (string, error) {
+	token, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+
+	session := &domain.Session{
+		FamilyID:  familyID,
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		UserAgent: truncate(client.UserAgent, 255),
+		IP:        truncate(client.IP, 64),
+		StartedAt: startedAt,
+		ExpiresAt: now.Add(s.refreshTTL),
+	}
+	if err := s.sessionRepo.CreateTx(tx, session); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *sessionService) tokens(user *domain.User, familyID, refreshToken string, now time.Time) (*domain.AuthTokens, error) {
+	roleNames := make([]string, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		roleNames = append(roleNames, role.Name)
+	}
+
+	claims := domain.JWTClaims{
+		UserID:    user.ID,
+		Roles:     roleNames,
+		SessionID: familyID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTTL)),
+		},
+	}
+
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.jwtSecret))
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.accessTTL.Seconds()),
+	}, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// truncate cuts s to at most max bytes without splitting a character.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	s = s[:max]
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,242 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// memTx holds the writes of one transaction until it commits.
+type memTx struct {
+	gorm.ConnPool
+	pending []func()
+}
+
+func (t *memTx) Commit() error {
+	for _, write := range t.pending {
+		write()
+	}
+	t.pending = nil
+	return nil
+}
+
+func (t *memTx) Rollback() error {
+	t.pending = nil
+	return nil
+}
+
+// memSessions is an in-memory SessionRepository for the refresh flow.
+type memSessions struct {
+	domain.SessionRepository
+	sessions []*domain.Session
+	nextID   int
+}
+
+func (r *memSessions) BeginTx() (*gorm.DB, error) {
+	return &gorm.DB{Config: &gorm.Config{}, Statement: &gorm.Statement{ConnPool: &memTx{}}}, nil
+}
+
+func (r *memSessions) later(tx *gorm.DB, write func()) {
+	t := tx.Statement.ConnPool.(*memTx)
+	t.pending = append(t.pending, write)
+}
+
+func (r *memSessions) CreateTx(tx *gorm.DB, session *domain.Session) error {
+	r.nextID++
+	session.ID = r.nextID
+	stored := *session
+	r.later(tx, func() { r.sessions = append(r.sessions, &stored) })
+	return nil
+}
+
+func (r *memSessions) GetByTokenHashTx(tx *gorm.DB, tokenHash string) (*domain.Session, error) {
+	for _, session := range r.sessions {
+		if session.TokenHash == tokenHash {
+			found := *session
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memSessions) UpdateTx(tx *gorm.DB, session *domain.Session) error {
+	stored := *session
+	r.later(tx, func() {
+		for i, s := range r.sessions {
+			if s.ID == stored.ID {
+				r.sessions[i] = &stored
+			}
+		}
+	})
+	return nil
+}
+
+func (r *memSessions) RevokeFamilyTx(tx *gorm.DB, familyID, reason string, at time.Time) error {
+	r.later(tx, func() {
+		for _, session := range r.sessions {
+			if session.FamilyID == familyID && session.RevokedAt == nil {
+				session.RevokedAt = &at
+				session.RevokeReason = reason
+			}
+		}
+	})
+	return nil
+}
+
+func (r *memSessions) IsFamilyRevoked(familyID string) (bool, error) {
+	for _, session := range r.sessions {
+		if session.FamilyID == familyID && session.RevokedAt == nil {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+type anyUser struct {
+	domain.UserRepository
+}
+
+func (anyUser) GetByID(id int) (*domain.User, error) {
+	return &domain.User{ID: id}, nil
+}
+
+type revokedSessions []string
+
+func (r *revokedSessions) SessionsRevoked(userID int, sessionID string) {
+	*r = append(*r, sessionID)
+}
+
+func newTestSessionService(refreshTTL time.Duration) (SessionService, *memSessions, *revokedSessions) {
+	repo := &memSessions{}
+	notified := &revokedSessions{}
+	return NewSessionService(repo, anyUser{}, "secret", 15*time.Minute, refreshTTL, notified), repo, notified
+}
+
+func TestSessionRefreshRotates(t *testing.T) {
+	service, repo, _ := newTestSessionService(time.Hour)
+	first, err := service.Start(&domain.User{ID: 1}, domain.SessionClient{})
+	if err != nil {
+		t.Fatalf("Start() returned error: %v", err)
+	}
+
+	token := first.RefreshToken
+	for i := 0; i < 3; i++ {
+		next, user, err := service.Refresh(token, domain.SessionClient{})
+		if err != nil {
+			t.Fatalf("refresh %d returned error: %v", i+1, err)
+		}
+		if user.ID != 1 {
+			t.Errorf("refresh %d returned user %d, want 1", i+1, user.ID)
+		}
+		if next.RefreshToken == token {
+			t.Errorf("refresh %d returned the same refresh token", i+1)
+		}
+		token = next.RefreshToken
+	}
+
+	if len(repo.sessions) != 4 {
+		t.Fatalf("stored %d tokens, want 4", len(repo.sessions))
+	}
+	for i, session := range repo.sessions {
+		if session.FamilyID != repo.sessions[0].FamilyID {
+			t.Errorf("token %d belongs to family %s, want %s", i, session.FamilyID, repo.sessions[0].FamilyID)
+		}
+		if rotated := session.RotatedAt != nil; rotated != (i < 3) {
+			t.Errorf("token %d rotated = %v, want %v", i, rotated, i < 3)
+		}
+	}
+	if repo.sessions[3].TokenHash != hashToken(token) {
+		t.Errorf("the last stored token is not the hash of the one handed out")
+	}
+}
+
+func TestSessionRefreshRejects(t *testing.T) {
+	tests := []struct {
+		name       string
+		refreshTTL time.Duration
+		// token returns the refresh token to present, given the one Start handed out.
+		token func(t *testing.T, service SessionService, issued string) string
+	}{
+		{
+			name:       "unknown token",
+			refreshTTL: time.Hour,
+			token:      func(*testing.T, SessionService, string) string { return "not-a-token" },
+		},
+		{
+			name:       "expired token",
+			refreshTTL: -time.Minute,
+			token:      func(_ *testing.T, _ SessionService, issued string) string { return issued },
+		},
+		{
+			name:       "logged out",
+			refreshTTL: time.Hour,
+			token: func(t *testing.T, service SessionService, issued string) string {
+				if err := service.Logout(issued); err != nil {
+					t.Fatalf("Logout() returned error: %v", err)
+				}
+				return issued
+			},
+		},
+		{
+			name:       "already rotated",
+			refreshTTL: time.Hour,
+			token: func(t *testing.T, service SessionService, issued string) string {
+				if _, _, err := service.Refresh(issued, domain.SessionClient{}); err != nil {
+					t.Fatalf("first Refresh() returned error: %v", err)
+				}
+				return issued
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, _ := newTestSessionService(tt.refreshTTL)
+			tokens, err := service.Start(&domain.User{ID: 1}, domain.SessionClient{})
+			if err != nil {
+				t.Fatalf("Start() returned error: %v", err)
+			}
+
+			token := tt.token(t, service, tokens.RefreshToken)
+			if _, _, err := service.Refresh(token, domain.SessionClient{}); !errors.Is(err, ErrInvalidRefreshToken) {
+				t.Errorf("Refresh() error = %v, want %v", err, ErrInvalidRefreshToken)
+			}
+		})
+	}
+}
+
+func TestSessionRefreshReuseRevokesFamily(t *testing.T) {
+	service, repo, notified := newTestSessionService(time.Hour)
+	first, err := service.Start(&domain.User{ID: 1}, domain.SessionClient{})
+	if err != nil {
+		t.Fatalf("Start() returned error: %v", err)
+	}
+	second, _, err := service.Refresh(first.RefreshToken, domain.SessionClient{})
+	if err != nil {
+		t.Fatalf("Refresh() returned error: %v", err)
+	}
+	familyID := repo.sessions[0].FamilyID
+
+	// Someone replays the token that was already traded in.
+	if _, _, err := service.Refresh(first.RefreshToken, domain.SessionClient{}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("replayed Refresh() error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+
+	for i, session := range repo.sessions {
+		if session.RevokedAt == nil || session.RevokeReason != domain.SessionRevokeReuse {
+			t.Errorf("token %d revoked at %v for %q, want revoked for %q", i, session.RevokedAt, session.RevokeReason, domain.SessionRevokeReuse)
+		}
+	}
+	if _, _, err := service.Refresh(second.RefreshToken, domain.SessionClient{}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Refresh() with the latest token error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+	if revoked, err := service.IsRevoked(1, familyID); err != nil || !revoked {
+		t.Errorf("IsRevoked() = %v, %v, want true", revoked, err)
+	}
+	if len(*notified) != 1 || (*notified)[0] != familyID {
+		t.Errorf("notified revocations %v, want [%s]", *notified, familyID)
+	}
+}
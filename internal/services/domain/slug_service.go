@@ -0,0 +1,187 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+)
+
+const (
+	maxSlugLength     = 80
+	maxSlugAttempts   = 1000
+	slugBackfillBatch = 200
+)
+
+// cyrillicTranslit follows the common Russian/Ukrainian web transliteration used in URLs.
+var cyrillicTranslit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g",
+}
+
+// latinFold drops diacritics from the Latin letters sellers use most often.
+var latinFold = map[rune]string{
+	'á': "a", 'à': "a", 'â': "a", 'ä': "a", 'ã': "a", 'å': "a", 'ç': "c", 'é': "e",
+	'è': "e", 'ê': "e", 'ë': "e", 'í': "i", 'ì': "i", 'î': "i", 'ï': "i", 'ñ': "n",
+	'ó': "o", 'ò': "o", 'ô': "o", 'ö': "o", 'õ': "o", 'ø': "o", 'ú': "u", 'ù': "u",
+	'û': "u", 'ü': "u", 'ý': "y", 'ÿ': "y", 'ß': "ss",
+}
+
+type SlugService interface {
+	Generate(entityType, source string, entityID int) (string, error)
+	RecordChange(entityType string, entityID int, oldSlug, newSlug string) error
+	GetRedirect(entityType, slug string) (*domain.SlugRedirect, error)
+	Backfill() error
+	CountSitemapEntries(entityType string) (int, error)
+	GetSitemapEntries(entityType string, limit, offset int) ([]domain.SitemapEntry, error)
+}
+
+type slugService struct {
+	slugRepo domain.SlugRepository
+}
+
+func NewSlugService(r domain.SlugRepository) SlugService {
+	return &slugService{slugRepo: r}
+}
+
+// Generate builds a slug from source that no other entity of the same type uses,
+// appending -2, -3, ... on collisions. entityID is 0 for entities not saved yet.
+// All-digit slugs get the entity type appended, since storefront URLs also accept IDs.
+func (s *slugService) Generate(entityType, source string, entityID int) (string, error) {
+	base := slugify(source)
+	if base == "" {
+		base = entityType
+	} else if strings.Trim(base, "0123456789") == "" {
+		base = truncateSlug(base, maxSlugLength-len(entityType)-1) + "-" + entityType
+	}
+
+	for i := 1; i <= maxSlugAttempts; i++ {
+		candidate := base
+		if i > 1 {
+			suffix := fmt.Sprintf("-%d", i)
+			candidate = strings.TrimRight(truncateSlug(base, maxSlugLength-len(suffix)), "-") + suffix
+		}
+
+		taken, err := s.slugRepo.IsTaken(entityType, candidate, entityID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+
+	return "", errors.New("could not generate a unique slug")
+}
+
+// RecordChange keeps the old slug resolvable after an entity is renamed. If the entity
+// takes back one of its own earlier slugs, that redirect is dropped.
+func (s *slugService) RecordChange(entityType string, entityID int, oldSlug, newSlug string) error {
+	if err := s.slugRepo.DeleteRedirect(entityType, newSlug, entityID); err != nil {
+		return err
+	}
+	if oldSlug == "" || oldSlug == newSlug {
+		return nil
+	}
+
+	return s.slugRepo.CreateRedirect(&domain.SlugRedirect{
+		EntityType: entityType,
+		OldSlug:    oldSlug,
+		EntityID:   entityID,
+	})
+}
+
+func (s *slugService) GetRedirect(entityType, slug string) (*domain.SlugRedirect, error) {
+	return s.slugRepo.GetRedirect(entityType, slug)
+}
+
+func (s *slugService) CountSitemapEntries(entityType string) (int, error) {
+	return s.slugRepo.CountSitemapEntries(entityType)
+}
+
+func (s *slugService) GetSitemapEntries(entityType string, limit, offset int) ([]domain.SitemapEntry, error) {
+	return s.slugRepo.GetSitemapEntries(entityType, limit, offset)
+}
+
+// Backfill assigns slugs to products and categories created before slugs existed.
+func (s *slugService) Backfill() error {
+	for _, entityType := range []string{domain.SlugEntityCategory, domain.SlugEntityProduct} {
+		total := 0
+		for {
+			sources, err := s.slugRepo.GetMissing(entityType, slugBackfillBatch)
+			if err != nil {
+				return err
+			}
+			if len(sources) == 0 {
+				break
+			}
+
+			for _, source := range sources {
+				slug, err := s.Generate(entityType, source.Source, source.ID)
+				if err != nil {
+					return err
+				}
+				if err := s.slugRepo.SetSlug(entityType, source.ID, slug); err != nil {
+					return err
+				}
+			}
+			total += len(sources)
+		}
+
+		if total > 0 {
+			log.Printf("Slug backfill: assigned %d %s slugs", total, entityType)
+		}
+	}
+	return nil
+}
+
+// slugify lowercases s, transliterates Cyrillic and joins the remaining ASCII letters
+// and digits with single hyphens.
+func slugify(s string) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	write := func(part string) {
+		if part == "" {
+			return
+		}
+		if pendingHyphen && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pendingHyphen = false
+		b.WriteString(part)
+	}
+
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			write(string(r))
+		case cyrillicTranslit[r] != "":
+			write(cyrillicTranslit[r])
+		case latinFold[r] != "":
+			write(latinFold[r])
+		case r == 'ъ' || r == 'ь' || r == '\'' || r == '’':
+			// silent signs and apostrophes don't split words
+		default:
+			pendingHyphen = true
+		}
+	}
+
+	return strings.TrimRight(truncateSlug(b.String(), maxSlugLength), "-")
+}
+
+func truncateSlug(slug string, max int) string {
+	if len(slug) <= max {
+		return slug
+	}
+	slug = slug[:max]
+	if i := strings.LastIndexByte(slug, '-'); i > max/2 {
+		slug = slug[:i]
+	}
+	return slug
+}
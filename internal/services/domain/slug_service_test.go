@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"strings"
+	"testing"
+)
+
+// takenSlugs is a SlugRepository whose IsTaken answers from a fixed set; other methods are unused.
+type takenSlugs struct {
+	domain.SlugRepository
+	taken map[string]bool
+}
+
+func (r takenSlugs) IsTaken(entityType, slug string, entityID int) (bool, error) {
+	return r.taken[entityType+"/"+slug], nil
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"ascii", "Steam Gift Card", "steam-gift-card"},
+		{"punctuation collapses", "  Netflix -- 1 month!!  ", "netflix-1-month"},
+		{"russian", "Ключ активации Windows", "klyuch-aktivatsii-windows"},
+		{"ukrainian", "Їжак і ґанок", "yizhak-i-ganok"},
+		{"yo and multi-letter", "Ёжик щука", "ezhik-shchuka"},
+		{"silent signs keep words together", "подъезд объект", "podezd-obekt"},
+		{"apostrophes keep words together", "Assassin’s Creed, Director's Cut", "assassins-creed-directors-cut"},
+		{"latin diacritics", "Pokémon Straße Año", "pokemon-strasse-ano"},
+		{"other scripts are separators", "游戏 Game 游戏", "game"},
+		{"only separators", "!!! ???", ""},
+		{"truncated on a word boundary", strings.Repeat("word ", 20), strings.TrimSuffix(strings.Repeat("word-", 16), "-")},
+		{"truncated mid word without a late hyphen", strings.Repeat("a", 100), strings.Repeat("a", maxSlugLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slugify(tt.in); got != tt.want {
+				t.Errorf("slugify(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugGenerate(t *testing.T) {
+	long := strings.Repeat("a", maxSlugLength)
+
+	tests := []struct {
+		name   string
+		source string
+		taken  []string
+		want   string
+	}{
+		{"free", "Steam Gift Card", nil, "steam-gift-card"},
+		{"first collision", "Steam Gift Card", []string{"steam-gift-card"}, "steam-gift-card-2"},
+		{"several collisions", "Steam Gift Card", []string{"steam-gift-card", "steam-gift-card-2", "steam-gift-card-3"}, "steam-gift-card-4"},
+		{"other entity types don't collide", "Games", []string{"category/games"}, "games"},
+		{"empty source falls back to the type", "???", nil, domain.SlugEntityProduct},
+		{"all digits get the type appended", "2077", nil, "2077-" + domain.SlugEntityProduct},
+		{"suffix fits the length limit", long, []string{long}, long[:maxSlugLength-2] + "-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := takenSlugs{taken: map[string]bool{}}
+			for _, slug := range tt.taken {
+				if !strings.Contains(slug, "/") {
+					slug = domain.SlugEntityProduct + "/" + slug
+				}
+				repo.taken[slug] = true
+			}
+
+			got, err := NewSlugService(repo).Generate(domain.SlugEntityProduct, tt.source, 0)
+			if err != nil {
+				t.Fatalf("Generate(%q) returned error: %v", tt.source, err)
+			}
+			if got != tt.want {
+				t.Errorf("Generate(%q) = %q, want %q", tt.source, got, tt.want)
+			}
+			if len(got) > maxSlugLength {
+				t.Errorf("Generate(%q) = %q is longer than %d", tt.source, got, maxSlugLength)
+			}
+		})
+	}
+}
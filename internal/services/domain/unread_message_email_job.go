@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	unreadEmailBatchSize     = 100
+	unreadEmailPreviewLength = 200
+)
+
+// UnreadMessageEmailJob emails participants about messages that have waited unread for longer
+// than delay. Each message is covered by at most one email, and users who are online are
+// skipped since they will see the message in the app. It is run by the scheduler.
+type UnreadMessageEmailJob struct {
+	participantRepo  domain.ParticipantRepository
+	messageRepo      domain.MessageRepository
+	conversationRepo domain.ConversationRepository
+	userRepo         domain.UserRepository
+	presence         PresenceTracker
+	emailService     EmailService
+	delay            time.Duration
+}
+
+func NewUnreadMessageEmailJob(
+	pRepo domain.ParticipantRepository,
+	mRepo domain.MessageRepository,
+	cRepo domain.ConversationRepository,
+	uRepo domain.UserRepository,
+	presence PresenceTracker,
+	emailService EmailService,
+	delay time.Duration,
+) *UnreadMessageEmailJob {
+	return &UnreadMessageEmailJob{
+		participantRepo:  pRepo,
+		messageRepo:      mRepo,
+		conversationRepo: cRepo,
+		userRepo:         uRepo,
+		presence:         presence,
+		emailService:     emailService,
+		delay:            delay,
+	}
+}
+
+func (j *UnreadMessageEmailJob) Run(ctx context.Context) error {
+	notices, err := j.participantRepo.GetUnreadForEmail(time.Now().Add(-j.delay), unreadEmailBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to find unread messages to email: %w", err)
+	}
+
+	failed := 0
+	for _, notice := range notices {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if j.presence.IsOnline(notice.UserID) {
+			continue
+		}
+		if err := j.notify(notice); err != nil {
+			log.Printf("Failed to email user %d about conversation %d: %v", notice.UserID, notice.ConversationID, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to email %d of %d participants", failed, len(notices))
+	}
+	return nil
+}
+
+func (j *UnreadMessageEmailJob) notify(notice *domain.UnreadNotice) error {
+	user, err := j.userRepo.GetByID(notice.UserID)
+	if err != nil {
+		return err
+	}
+	message, err := j.messageRepo.GetByID(notice.LastMessageID)
+	if err != nil {
+		return err
+	}
+	conversation, err := j.conversationRepo.GetByID(notice.ConversationID)
+	if err != nil {
+		return err
+	}
+
+	senderName := ""
+	if message.Sender != nil {
+		senderName = message.Sender.Username
+	}
+	productTitle := ""
+	if conversation.Product != nil {
+		productTitle = conversation.Product.Title
+	}
+
+	err = j.emailService.Notify(user, domain.NotificationNewMessage, "unread_messages", map[string]interface{}{
+		"ConversationID": notice.ConversationID,
+		"Count":          notice.Count,
+		"SenderName":     senderName,
+		"ProductTitle":   productTitle,
+		"Preview":        preview(message.Content),
+	})
+	if err != nil {
+		return err
+	}
+
+	return j.participantRepo.MarkEmailed(notice.ConversationID, notice.UserID, notice.LastMessageID)
+}
+
+func preview(content string) string {
+	if utf8.RuneCountInString(content) <= unreadEmailPreviewLength {
+		return content
+	}
+	runes := []rune(content)
+	return string(runes[:unreadEmailPreviewLength]) + "…"
+}
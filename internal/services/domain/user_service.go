@@ -3,29 +3,32 @@ package domain
 import (
 	"MicroShopik/internal/domain"
 	"errors"
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 	"strings"
 	"time"
 )
 
+// ErrEmailNotVerified is returned when an action needs a confirmed email address.
+var ErrEmailNotVerified = errors.New("please verify your email address first")
+
 type UserService interface {
 	Register(user *domain.User) error
-	Login(email, password string) (string, *domain.User, error)
+	Login(email, password string, client domain.SessionClient) (*domain.AuthTokens, *domain.User, error)
 	GetByID(id int) (*domain.User, error)
 	GetAll() ([]domain.User, error)
 	Create(user *domain.User) error
 	Update(user *domain.User) error
 	Delete(id int) error
 	ValidateUserExists(userID int) error
+	ValidateEmailVerified(userID int) error
 }
 type userService struct {
-	userRepo  domain.UserRepository
-	jwtSecret string
+	userRepo       domain.UserRepository
+	sessionService SessionService
 }
 
-func NewUserService(userRepo domain.UserRepository, jwtSecret string) UserService {
-	return &userService{userRepo: userRepo, jwtSecret: jwtSecret}
+func NewUserService(userRepo domain.UserRepository, sessionService SessionService) UserService {
+	return &userService{userRepo: userRepo, sessionService: sessionService}
 }
 
 func (s *userService) GetByID(id int) (*domain.User, error) {
@@ -41,18 +44,45 @@ func (s *userService) Create(user *domain.User) error {
 }
 
 func (s *userService) Update(user *domain.User) error {
+	emailChanged := false
+	if user.Email != "" {
+		current, err := s.userRepo.GetByID(user.ID)
+		if err != nil {
+			return err
+		}
+		emailChanged = !strings.EqualFold(current.Email, user.Email)
+	}
+
+	passwordChanged := false
 	if user.Password != "" && !strings.HasPrefix(user.Password, "$2") {
 		hashed, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 		if err != nil {
 			return err
 		}
 		user.Password = string(hashed)
+		passwordChanged = true
+	}
+	if err := s.userRepo.Update(user); err != nil {
+		return err
 	}
-	return s.userRepo.Update(user)
+
+	if emailChanged {
+		if err := s.userRepo.ClearEmailVerification(user.ID); err != nil {
+			return err
+		}
+		user.EmailVerifiedAt = nil
+	}
+	if passwordChanged {
+		return s.sessionService.RevokeAll(user.ID, domain.SessionRevokePassword)
+	}
+	return nil
 }
 
 func (s *userService) Delete(id int) error {
-	return s.userRepo.Delete(id)
+	if err := s.userRepo.Delete(id); err != nil {
+		return err
+	}
+	return s.sessionService.RevokeAll(id, domain.SessionRevokeUserDeleted)
 }
 
 func (s *userService) ValidateUserExists(userID int) error {
@@ -63,6 +93,17 @@ func (s *userService) ValidateUserExists(userID int) error {
 	return nil
 }
 
+func (s *userService) ValidateEmailVerified(userID int) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if user.EmailVerifiedAt == nil {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
 func (s *userService) Register(user *domain.User) error {
 	hashed, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -84,40 +125,28 @@ func (s *userService) Register(user *domain.User) error {
 	return nil
 }
 
-func (s *userService) Login(email, password string) (string, *domain.User, error) {
+func (s *userService) Login(email, password string, client domain.SessionClient) (*domain.AuthTokens, *domain.User, error) {
 	findUser, err := s.userRepo.GetByEmail(email)
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
 	if err := bcrypt.CompareHashAndPassword([]byte(findUser.Password), []byte(password)); err != nil {
-		return "", nil, errors.New("incorrect credentials")
+		return nil, nil, errors.New("incorrect credentials")
 	}
 
 	findUser, err = s.userRepo.GetByID(findUser.ID)
 	if err != nil {
-		return "", nil, errors.New("failed to get user with roles")
-	}
-
-	var roleNames []string
-	for _, role := range findUser.Roles {
-		roleNames = append(roleNames, role.Name)
-	}
-
-	claims := domain.JWTClaims{
-		UserID: findUser.ID,
-		Roles:  roleNames,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour))},
+		return nil, nil, errors.New("failed to get user with roles")
 	}
 
 	if err := s.userRepo.LastLoginUpdate(findUser.ID); err != nil {
-		return "", nil, errors.New("failed to update last login time")
+		return nil, nil, errors.New("failed to update last login time")
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(s.jwtSecret))
+
+	tokens, err := s.sessionService.Start(findUser, client)
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
 
-	return tokenString, findUser, nil
+	return tokens, findUser, nil
 }
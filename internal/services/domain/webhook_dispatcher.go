@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// WebhookDispatcher periodically sends the webhook deliveries that are due, including retries.
+type WebhookDispatcher struct {
+	webhookService WebhookService
+	interval       time.Duration
+	ctx            context.Context
+	cancel         context.CancelFunc
+	done           chan struct{}
+}
+
+func NewWebhookDispatcher(webhookService WebhookService, interval time.Duration) *WebhookDispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &WebhookDispatcher{
+		webhookService: webhookService,
+		interval:       interval,
+		ctx:            ctx,
+		cancel:         cancel,
+		done:           make(chan struct{}),
+	}
+}
+
+func (d *WebhookDispatcher) Start() {
+	log.Printf("Starting webhook dispatcher with interval %v", d.interval)
+
+	go func() {
+		defer close(d.done)
+
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				d.drain()
+			case <-d.ctx.Done():
+				log.Println("Webhook dispatcher stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop waits for the batch in progress so its outcomes are recorded.
+func (d *WebhookDispatcher) Stop() {
+	log.Println("Stopping webhook dispatcher...")
+	d.cancel()
+	<-d.done
+}
+
+func (d *WebhookDispatcher) drain() {
+	for d.ctx.Err() == nil {
+		claimed, err := d.webhookService.DeliverDue()
+		if err != nil {
+			log.Printf("Failed to deliver webhooks: %v", err)
+			return
+		}
+		if claimed < webhookBatchSize {
+			return
+		}
+	}
+}
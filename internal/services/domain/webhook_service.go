@@ -0,0 +1,434 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxWebhookEndpoints      = 10
+	webhookBatchSize         = 20
+	webhookBaseBackoff       = time.Minute
+	webhookMaxBackoff        = 6 * time.Hour
+	webhookResponseBodyLimit = 2048
+)
+
+// Webhook request headers. Receivers check the signature as computed by SignWebhook and should
+// reject timestamps that are too old, so a captured request cannot be replayed.
+const (
+	WebhookHeaderEvent     = "X-MicroShopik-Event"
+	WebhookHeaderDelivery  = "X-MicroShopik-Delivery"
+	WebhookHeaderTimestamp = "X-MicroShopik-Timestamp"
+	WebhookHeaderSignature = "X-MicroShopik-Signature"
+)
+
+// webhookEnvelope is the JSON body of every webhook request.
+type webhookEnvelope struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+type WebhookService interface {
+	// CreateEndpoint returns the endpoint together with its signing secret, which is not shown again.
+	CreateEndpoint(sellerID int, rawURL, description string, eventTypes []string) (*domain.WebhookEndpoint, string, error)
+	GetEndpoints(sellerID int) ([]*domain.WebhookEndpoint, error)
+	// UpdateEndpoint keeps the endpoint's active state when isActive is nil.
+	UpdateEndpoint(endpointID, sellerID int, rawURL, description string, eventTypes []string, isActive *bool) (*domain.WebhookEndpoint, error)
+	DeleteEndpoint(endpointID, sellerID int) error
+	RotateSecret(endpointID, sellerID int) (string, error)
+	// Dispatch queues an event for every active endpoint of the user that subscribes to it.
+	// eventID must be stable for the event, so dispatching it twice queues it once.
+	Dispatch(userID int, eventType, eventID string, data interface{}) error
+	SendTest(endpointID, sellerID int) (*domain.WebhookDelivery, error)
+	GetDeliveries(endpointID, sellerID, limit, offset int) ([]*domain.WebhookDelivery, int64, error)
+	Redeliver(endpointID, deliveryID, sellerID int) (*domain.WebhookDelivery, error)
+	// DeliverDue sends a batch of due deliveries and reports how many were picked up.
+	DeliverDue() (int, error)
+}
+
+type webhookService struct {
+	endpointRepo domain.WebhookEndpointRepository
+	deliveryRepo domain.WebhookDeliveryRepository
+	preferences  NotificationPreferenceService
+	client       *http.Client
+	maxAttempts  int
+	allowPrivate bool
+}
+
+// NewWebhookService refuses endpoints on loopback, private and link-local addresses unless
+// allowPrivate is set, which is meant for local development only.
+func NewWebhookService(eRepo domain.WebhookEndpointRepository, dRepo domain.WebhookDeliveryRepository, preferences NotificationPreferenceService, timeout time.Duration, maxAttempts int, allowPrivate bool) WebhookService {
+	return &webhookService{
+		endpointRepo: eRepo,
+		deliveryRepo: dRepo,
+		preferences:  preferences,
+		client:       newWebhookClient(timeout, allowPrivate),
+		maxAttempts:  maxAttempts,
+		allowPrivate: allowPrivate,
+	}
+}
+
+func (s *webhookService) CreateEndpoint(sellerID int, rawURL, description string, eventTypes []string) (*domain.WebhookEndpoint, string, error) {
+	if err := validateWebhookURL(rawURL, s.allowPrivate); err != nil {
+		return nil, "", err
+	}
+	eventTypes, err := normalizeWebhookEventTypes(eventTypes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	count, err := s.endpointRepo.CountBySellerID(sellerID)
+	if err != nil {
+		return nil, "", err
+	}
+	if count >= maxWebhookEndpoints {
+		return nil, "", fmt.Errorf("a seller can have at most %d webhook endpoints", maxWebhookEndpoints)
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	endpoint := &domain.WebhookEndpoint{
+		SellerID:    sellerID,
+		URL:         strings.TrimSpace(rawURL),
+		Description: strings.TrimSpace(description),
+		EventTypes:  eventTypes,
+		Secret:      secret,
+		IsActive:    true,
+	}
+	if err := s.endpointRepo.Create(endpoint); err != nil {
+		return nil, "", err
+	}
+	return endpoint, secret, nil
+}
+
+func (s *webhookService) GetEndpoints(sellerID int) ([]*domain.WebhookEndpoint, error) {
+	return s.endpointRepo.GetBySellerID(sellerID)
+}
+
+func (s *webhookService) UpdateEndpoint(endpointID, sellerID int, rawURL, description string, eventTypes []string, isActive *bool) (*domain.WebhookEndpoint, error) {
+	endpoint, err := s.ownEndpoint(endpointID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWebhookURL(rawURL, s.allowPrivate); err != nil {
+		return nil, err
+	}
+	eventTypes, err = normalizeWebhookEventTypes(eventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.URL = strings.TrimSpace(rawURL)
+	endpoint.Description = strings.TrimSpace(description)
+	endpoint.EventTypes = eventTypes
+	if isActive != nil {
+		endpoint.IsActive = *isActive
+	}
+	if err := s.endpointRepo.Update(endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// DeleteEndpoint keeps the delivery log; deliveries still pending fail on their next attempt.
+func (s *webhookService) DeleteEndpoint(endpointID, sellerID int) error {
+	if _, err := s.ownEndpoint(endpointID, sellerID); err != nil {
+		return err
+	}
+	return s.endpointRepo.Delete(endpointID)
+}
+
+func (s *webhookService) RotateSecret(endpointID, sellerID int) (string, error) {
+	endpoint, err := s.ownEndpoint(endpointID, sellerID)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return "", err
+	}
+	endpoint.Secret = secret
+	if err := s.endpointRepo.Update(endpoint); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+func (s *webhookService) Dispatch(userID int, eventType, eventID string, data interface{}) error {
+	decision, err := s.preferences.Decide(userID, domain.WebhookEventNotificationTypes[eventType], domain.NotificationChannelWebhook)
+	if err != nil {
+		return err
+	}
+	if !decision.Allowed {
+		return nil
+	}
+
+	endpoints, err := s.endpointRepo.GetBySellerID(userID)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, endpoint := range endpoints {
+		if !endpoint.IsActive || !endpoint.Subscribes(eventType) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(webhookEnvelope{ID: eventID, Type: eventType, CreatedAt: time.Now().UTC(), Data: data}); err != nil {
+				return err
+			}
+		}
+
+		delivery := &domain.WebhookDelivery{
+			EndpointID:    endpoint.ID,
+			EventID:       eventID,
+			EventType:     eventType,
+			Payload:       payload,
+			Status:        domain.WebhookDeliveryStatusPending,
+			NextAttemptAt: time.Now(),
+		}
+		if _, err := s.deliveryRepo.Create(delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendTest sends a ping right away and returns the outcome; a failed ping is retried like any delivery.
+func (s *webhookService) SendTest(endpointID, sellerID int) (*domain.WebhookDelivery, error) {
+	endpoint, err := s.ownEndpoint(endpointID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	eventID, err := newWebhookEventID(domain.WebhookEventPing)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(webhookEnvelope{
+		ID:        eventID,
+		Type:      domain.WebhookEventPing,
+		CreatedAt: time.Now().UTC(),
+		Data:      map[string]interface{}{"endpoint_id": endpoint.ID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &domain.WebhookDelivery{
+		EndpointID:    endpoint.ID,
+		EventID:       eventID,
+		EventType:     domain.WebhookEventPing,
+		Payload:       payload,
+		Status:        domain.WebhookDeliveryStatusPending,
+		NextAttemptAt: time.Now().Add(s.client.Timeout + time.Minute),
+		Endpoint:      endpoint,
+	}
+	if _, err := s.deliveryRepo.Create(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, s.attempt(delivery)
+}
+
+func (s *webhookService) GetDeliveries(endpointID, sellerID, limit, offset int) ([]*domain.WebhookDelivery, int64, error) {
+	if _, err := s.ownEndpoint(endpointID, sellerID); err != nil {
+		return nil, 0, err
+	}
+	return s.deliveryRepo.GetByEndpointID(endpointID, limit, offset)
+}
+
+// Redeliver sends the stored payload again as a new delivery, right away. The original stays
+// in the log unchanged, and the event ID is kept so receivers can tell it is a repeat.
+func (s *webhookService) Redeliver(endpointID, deliveryID, sellerID int) (*domain.WebhookDelivery, error) {
+	endpoint, err := s.ownEndpoint(endpointID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	original, err := s.deliveryRepo.GetByID(deliveryID)
+	if err != nil || original.EndpointID != endpoint.ID {
+		return nil, errors.New("webhook delivery not found")
+	}
+
+	redeliveryOf := original.ID
+	if original.RedeliveryOfID != nil {
+		redeliveryOf = *original.RedeliveryOfID
+	}
+
+	delivery := &domain.WebhookDelivery{
+		EndpointID:     endpoint.ID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		RedeliveryOfID: &redeliveryOf,
+		Status:         domain.WebhookDeliveryStatusPending,
+		NextAttemptAt:  time.Now().Add(s.client.Timeout + time.Minute),
+		Endpoint:       endpoint,
+	}
+	if _, err := s.deliveryRepo.Create(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, s.attempt(delivery)
+}
+
+func (s *webhookService) DeliverDue() (int, error) {
+	batch, err := s.deliveryRepo.ClaimDue(webhookBatchSize, s.client.Timeout+time.Minute)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range batch {
+		if err := s.attempt(delivery); err != nil {
+			return len(batch), err
+		}
+	}
+	return len(batch), nil
+}
+
+// attempt sends the delivery once and records the outcome. Only a failure to store the
+// outcome is returned; a failed request is recorded on the delivery and retried later.
+func (s *webhookService) attempt(delivery *domain.WebhookDelivery) error {
+	delivery.Attempts++
+
+	started := time.Now()
+	status, body, sendErr := s.send(delivery)
+	delivery.DurationMs = time.Since(started).Milliseconds()
+	delivery.ResponseStatus = status
+	delivery.ResponseBody = body
+
+	if sendErr == nil {
+		now := time.Now()
+		delivery.Status = domain.WebhookDeliveryStatusSucceeded
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	} else {
+		delivery.LastError = sendErr.Error()
+		if delivery.Endpoint == nil || delivery.Attempts >= s.maxAttempts {
+			delivery.Status = domain.WebhookDeliveryStatusFailed
+		} else {
+			delivery.NextAttemptAt = time.Now().Add(webhookBackoff(delivery.Attempts))
+		}
+	}
+
+	if err := s.deliveryRepo.Update(delivery); err != nil {
+		log.Printf("Failed to record webhook delivery %d: %v", delivery.ID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *webhookService) send(delivery *domain.WebhookDelivery) (int, string, error) {
+	endpoint := delivery.Endpoint
+	if endpoint == nil {
+		return 0, "", errors.New("webhook endpoint was deleted")
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request, err := http.NewRequest(http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "MicroShopik-Webhooks/1.0")
+	request.Header.Set(WebhookHeaderEvent, delivery.EventType)
+	request.Header.Set(WebhookHeaderDelivery, strconv.Itoa(delivery.ID))
+	request.Header.Set(WebhookHeaderTimestamp, timestamp)
+	request.Header.Set(WebhookHeaderSignature, "sha256="+SignWebhook(endpoint.Secret, timestamp, delivery.Payload))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, "", err
+	}
+	defer response.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(response.Body, webhookResponseBodyLimit))
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, string(body), fmt.Errorf("endpoint responded with status %d", response.StatusCode)
+	}
+	return response.StatusCode, string(body), nil
+}
+
+func (s *webhookService) ownEndpoint(endpointID, sellerID int) (*domain.WebhookEndpoint, error) {
+	endpoint, err := s.endpointRepo.GetByID(endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.SellerID != sellerID {
+		return nil, errors.New("webhook endpoint not found")
+	}
+	return endpoint, nil
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the endpoint secret.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff doubles the delay after every failed attempt: 1m, 2m, 4m, ... up to six hours.
+func webhookBackoff(attempts int) time.Duration {
+	delay := webhookBaseBackoff
+	for i := 1; i < attempts && delay < webhookMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > webhookMaxBackoff {
+		delay = webhookMaxBackoff
+	}
+	return delay
+}
+
+func normalizeWebhookEventTypes(eventTypes []string) ([]string, error) {
+	seen := make(map[string]bool, len(eventTypes))
+	var normalized []string
+	for _, eventType := range eventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if _, ok := domain.WebhookEventTypes[eventType]; !ok {
+			return nil, fmt.Errorf("unknown event type %q", eventType)
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			normalized = append(normalized, eventType)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, errors.New("at least one event type is required")
+	}
+	return normalized, nil
+}
+
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+func newWebhookEventID(eventType string) (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return eventType + ":" + hex.EncodeToString(buf), nil
+}
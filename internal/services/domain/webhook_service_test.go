@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"MicroShopik/internal/domain"
+	"crypto/hmac"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignWebhook(t *testing.T) {
+	// Expected values computed independently with `openssl dgst -sha256 -hmac`.
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+		want      string
+	}{
+		{"payload", "whsec_test", "1700000000", `{"event":"order.created"}`, "44ccdd37cc0cde29381624e0495514ce79007393020fddb05c89075cd26cc6bd"},
+		{"empty body", "whsec_test", "1700000000", "", "5967f3c560522fa40cf2876ebc3c3a08551dd6959aaade3b413460591895bdcc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SignWebhook(tt.secret, tt.timestamp, []byte(tt.body)); got != tt.want {
+				t.Errorf("SignWebhook(%q, %q, %q) = %s, want %s", tt.secret, tt.timestamp, tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignWebhookCoversEveryInput(t *testing.T) {
+	base := SignWebhook("whsec_test", "1700000000", []byte(`{"a":1}`))
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+	}{
+		{"other secret", "whsec_other", "1700000000", `{"a":1}`},
+		{"other timestamp", "whsec_test", "1700000001", `{"a":1}`},
+		{"other body", "whsec_test", "1700000000", `{"a":2}`},
+		// The separator keeps digits from moving between the timestamp and the body.
+		{"shifted boundary", "whsec_test", "170000000", `0{"a":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SignWebhook(tt.secret, tt.timestamp, []byte(tt.body)); got == base {
+				t.Errorf("SignWebhook(%q, %q, %q) matches the original signature", tt.secret, tt.timestamp, tt.body)
+			}
+		})
+	}
+}
+
+func TestWebhookSendSignsRequest(t *testing.T) {
+	const secret = "whsec_test"
+	payload := []byte(`{"event":"order.created","data":{"id":7}}`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Verify the request the way a receiver would.
+		body, _ := io.ReadAll(r.Body)
+		timestamp := r.Header.Get(WebhookHeaderTimestamp)
+		want := "sha256=" + SignWebhook(secret, timestamp, body)
+		if !hmac.Equal([]byte(r.Header.Get(WebhookHeaderSignature)), []byte(want)) {
+			t.Errorf("signature header = %q, want %q", r.Header.Get(WebhookHeaderSignature), want)
+		}
+		if string(body) != string(payload) {
+			t.Errorf("body = %s, want %s", body, payload)
+		}
+		if sent, err := strconv.ParseInt(timestamp, 10, 64); err != nil || time.Since(time.Unix(sent, 0)).Abs() > time.Minute {
+			t.Errorf("timestamp header = %q, want the current Unix time", timestamp)
+		}
+		if got := r.Header.Get(WebhookHeaderEvent); got != "order.created" {
+			t.Errorf("event header = %q, want %q", got, "order.created")
+		}
+		if got := r.Header.Get(WebhookHeaderDelivery); got != "42" {
+			t.Errorf("delivery header = %q, want %q", got, "42")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	service := &webhookService{client: newWebhookClient(5*time.Second, true)}
+	delivery := &domain.WebhookDelivery{
+		ID:        42,
+		EventType: "order.created",
+		Payload:   payload,
+		Endpoint:  &domain.WebhookEndpoint{URL: server.URL, Secret: secret},
+	}
+	if status, _, err := service.send(delivery); err != nil || status != http.StatusNoContent {
+		t.Fatalf("send() = %d, %v, want %d", status, err, http.StatusNoContent)
+	}
+}
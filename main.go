@@ -6,7 +6,6 @@ import (
 	"MicroShopik/internal/container"
 	"MicroShopik/internal/database"
 	"MicroShopik/internal/middleware"
-	keepalive "MicroShopik/internal/services/domain"
 	"MicroShopik/scripts"
 	"context"
 	"errors"
@@ -14,6 +13,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
@@ -58,6 +58,12 @@ func main() {
 	setupCORS(e)
 
 	newContainer := container.NewContainer()
+	defer newContainer.RealtimeHub.Close()
+	defer newContainer.EventBus.Close()
+
+	if err := newContainer.SlugService.Backfill(); err != nil {
+		log.Printf("Warning: Failed to backfill slugs: %v", err)
+	}
 
 	setupRoutes(e, newContainer, cfg.JWTSecret)
 
@@ -65,11 +71,18 @@ func main() {
 
 	setupErrorHandler(e)
 
-	if cfg.KeepAliveEnabled {
-		interval := time.Duration(cfg.KeepAliveInterval) * time.Minute
-		ka := keepalive.NewKeepAliveService(cfg.KeepAliveURL, interval)
-		ka.Start()
-		defer ka.Stop()
+	newContainer.OutboxRelay.Start()
+	defer newContainer.OutboxRelay.Stop()
+
+	newContainer.WebhookDispatcher.Start()
+	defer newContainer.WebhookDispatcher.Stop()
+
+	newContainer.Scheduler.Start()
+	defer newContainer.Scheduler.Stop()
+
+	if cfg.JobWorkersInServer {
+		newContainer.JobWorkerPool.Start()
+		defer newContainer.JobWorkerPool.Stop()
 	}
 
 	startServer(e)
@@ -95,6 +108,9 @@ func setupCORS(e *echo.Echo) {
 func setupRoutes(e *echo.Echo, container *container.Container, jwt string) {
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
+	e.GET("/sitemap.xml", container.SitemapController.GetRoot)
+	e.GET("/sitemaps/:name", container.SitemapController.GetPage)
+
 	e.GET("/health", func(c echo.Context) error {
 		return c.JSON(200, map[string]interface{}{
 			"status":    "ok",
@@ -118,22 +134,27 @@ func setupRoutes(e *echo.Echo, container *container.Container, jwt string) {
 
 	setupAdminRoutes(e, container, jwt)
 
-	setupSellerRoutes(e, jwt)
+	setupSellerRoutes(e, container, jwt)
 }
 
 func setupAuthRoutes(e *echo.Echo, container *container.Container) {
 	auth := e.Group("/auth")
 	auth.POST("/register", container.UserController.Register)
 	auth.POST("/login", container.UserController.Login)
+	auth.POST("/refresh", container.SessionController.Refresh)
+	auth.POST("/logout", container.SessionController.Logout)
+	auth.POST("/verify-email", container.EmailVerificationController.Verify, middleware.RateLimit(10, time.Minute))
+	auth.POST("/resend-verification", container.EmailVerificationController.Resend, middleware.RateLimit(5, time.Hour))
 }
 
 func setupCategoryRoutes(e *echo.Echo, container *container.Container, jwt string) {
 	cats := e.Group("/categories")
 	cats.GET("", container.CategoryController.GetAll)
 	cats.GET("/:id", container.CategoryController.GetById)
+	cats.GET("/by-slug/:slug", container.CategoryController.GetBySlug)
 
 	catsAdmin := e.Group("/categories")
-	catsAdmin.Use(middleware.JWTMiddleware(jwt))
+	catsAdmin.Use(middleware.JWTMiddleware(jwt, container.SessionService))
 	catsAdmin.Use(middleware.RequireRole("admin"))
 	catsAdmin.POST("", container.CategoryController.Create)
 	catsAdmin.PUT("/:id", container.CategoryController.Update)
@@ -145,19 +166,30 @@ func setupProductRoutes(e *echo.Echo, container *container.Container, jwt string
 	products.GET("", container.ProductController.Find)
 	products.GET("/count", container.ProductController.Count)
 	products.GET("/:id", container.ProductController.GetById)
+	products.GET("/by-slug/:slug", container.ProductController.GetBySlug)
 	products.GET("/:id/available", container.ProductController.IsAvailable)
+	products.GET("/:id/related", container.ProductController.GetRelated)
 
 	productsAuth := e.Group("/products")
-	productsAuth.Use(middleware.JWTMiddleware(jwt))
+	productsAuth.Use(middleware.JWTMiddleware(jwt, container.SessionService))
 	productsAuth.Use(middleware.RequireAnyRole("seller", "admin"))
 	productsAuth.POST("", container.ProductController.Create)
 	productsAuth.PUT("/:id", container.ProductController.Update)
 	productsAuth.DELETE("/:id", container.ProductController.Delete)
+	productsAuth.POST("/:id/resubmit", container.ProductModerationController.Resubmit)
+	productsAuth.GET("/:id/moderation", container.ProductModerationController.GetHistory)
+	productsAuth.GET("/:id/revisions", container.ProductController.GetRevisions)
+	productsAuth.POST("/:id/revisions/:revisionID/rollback", container.ProductController.Rollback)
+	productsAuth.GET("/:id/conversations", container.ConversationController.GetByProductID)
+
+	inquiries := e.Group("/products")
+	inquiries.Use(middleware.JWTMiddleware(jwt, container.SessionService))
+	inquiries.POST("/:id/inquiry", container.ConversationController.CreateInquiry)
 }
 
 func setupOrderRoutes(e *echo.Echo, container *container.Container, jwt string) {
 	orders := e.Group("/orders")
-	orders.Use(middleware.JWTMiddleware(jwt))
+	orders.Use(middleware.JWTMiddleware(jwt, container.SessionService))
 	orders.POST("", container.OrderController.Create)
 	orders.GET("", container.OrderController.GetMyOrders)
 	orders.GET("/seller", container.OrderController.GetMyOrdersAsSeller)
@@ -170,29 +202,59 @@ func setupOrderRoutes(e *echo.Echo, container *container.Container, jwt string)
 
 func setupConversationRoutes(e *echo.Echo, container *container.Container, jwt string) {
 	conversations := e.Group("/conversations")
-	conversations.Use(middleware.JWTMiddleware(jwt))
+	conversations.Use(middleware.JWTMiddleware(jwt, container.SessionService))
 	conversations.POST("", container.ConversationController.Create)
 	conversations.GET("/:id", container.ConversationController.GetByID)
 	conversations.PUT("/:id", container.ConversationController.Update)
 	conversations.DELETE("/:id", container.ConversationController.Delete)
 	conversations.POST("/:id/participants/:userID", container.ConversationController.AddParticipant)
 	conversations.DELETE("/:id/participants/:userID", container.ConversationController.RemoveParticipant)
+	conversations.POST("/:id/read", container.ConversationController.MarkRead)
 
 	users := e.Group("/users")
-	users.Use(middleware.JWTMiddleware(jwt))
+	users.Use(middleware.JWTMiddleware(jwt, container.SessionService))
 	users.GET("/:userID/conversations", container.ConversationController.GetByUserID)
+	users.GET("/:userID/presence", container.PresenceController.Get)
+
+	me := e.Group("/me")
+	me.Use(middleware.JWTMiddleware(jwt, container.SessionService))
+	me.GET("/unread", container.ConversationController.GetUnread)
+	me.PUT("/presence", container.PresenceController.UpdateSettings)
+	me.PUT("/locale", container.UserController.UpdateLocale)
+	me.GET("/sessions", container.SessionController.GetSessions)
+	me.DELETE("/sessions/:id", container.SessionController.Revoke)
+	me.GET("/conversations/search", container.ConversationController.Search)
+	me.GET("/sanctions", container.MessageModerationController.GetMySanctions)
+	me.GET("/notifications", container.NotificationController.GetNotifications)
+	me.POST("/notifications/read-all", container.NotificationController.MarkAllRead)
+	me.POST("/notifications/:id/read", container.NotificationController.MarkRead)
+	me.GET("/notification-preferences", container.NotificationController.GetPreferences)
+	me.PUT("/notification-preferences", container.NotificationController.UpdatePreferences)
+
+	e.GET("/ws", container.RealtimeController.Connect, middleware.JWTMiddleware(jwt, container.SessionService))
 
 	messages := e.Group("/conversations/:conversationID/messages")
-	messages.Use(middleware.JWTMiddleware(jwt))
+	messages.Use(middleware.JWTMiddleware(jwt, container.SessionService))
 	messages.GET("", container.MessageController.GetByConversationID)
 	messages.POST("", container.MessageController.Create)
 	messages.GET("/system", container.MessageController.GetSystemMessages)
 	messages.POST("/system", container.MessageController.SendSystemMessage)
+	messages.PUT("/:id", container.MessageController.Update)
+	messages.DELETE("/:id", container.MessageController.Delete)
+	messages.GET("/:id/revisions", container.MessageController.GetRevisions)
+	messages.POST("/:id/report", container.MessageModerationController.Report)
+
+	e.GET("/moderation/message-report-reasons", container.MessageModerationController.GetReportReasons)
+
+	attachments := e.Group("/conversations/:conversationID/attachments")
+	attachments.Use(middleware.JWTMiddleware(jwt, container.SessionService))
+	attachments.POST("", container.AttachmentController.Upload)
+	attachments.GET("/:id", container.AttachmentController.Download)
 }
 
 func setupRoleRoutes(e *echo.Echo, container *container.Container, jwt string) {
 	roles := e.Group("/roles")
-	roles.Use(middleware.JWTMiddleware(jwt))
+	roles.Use(middleware.JWTMiddleware(jwt, container.SessionService))
 	roles.Use(middleware.RequireRole("admin"))
 	roles.GET("", container.RoleController.GetAll)
 	roles.GET("/:name", container.RoleController.GetByName)
@@ -201,7 +263,7 @@ func setupRoleRoutes(e *echo.Echo, container *container.Container, jwt string) {
 	roles.DELETE("/:id", container.RoleController.Delete)
 
 	userManagement := e.Group("/users")
-	userManagement.Use(middleware.JWTMiddleware(jwt))
+	userManagement.Use(middleware.JWTMiddleware(jwt, container.SessionService))
 	userManagement.Use(middleware.RequireRole("admin"))
 	userManagement.GET("/:user_id/roles", container.RoleController.GetUserRoles)
 	userManagement.POST("/:user_id/roles/:role_name", container.RoleController.AssignRoleToUser)
@@ -211,7 +273,7 @@ func setupRoleRoutes(e *echo.Echo, container *container.Container, jwt string) {
 
 func setupAdminRoutes(e *echo.Echo, container *container.Container, jwt string) {
 	adminGroup := e.Group("/admin")
-	adminGroup.Use(middleware.JWTMiddleware(jwt))
+	adminGroup.Use(middleware.JWTMiddleware(jwt, container.SessionService))
 	adminGroup.Use(middleware.RequireRole("admin"))
 
 	adminGroup.GET("/dashboard", func(c echo.Context) error {
@@ -241,6 +303,30 @@ func setupAdminRoutes(e *echo.Echo, container *container.Container, jwt string)
 		return c.JSON(200, products)
 	})
 
+	adminGroup.GET("/moderation/products", container.ProductModerationController.GetQueue)
+	adminGroup.GET("/moderation/products/reasons", container.ProductModerationController.GetReasonCodes)
+	adminGroup.GET("/moderation/products/:id/history", container.ProductModerationController.GetHistory)
+	adminGroup.POST("/moderation/products/:id/approve", container.ProductModerationController.Approve)
+	adminGroup.POST("/moderation/products/:id/reject", container.ProductModerationController.Reject)
+
+	adminGroup.GET("/outbox", container.OutboxController.GetEvents)
+	adminGroup.POST("/outbox/:id/retry", container.OutboxController.Retry)
+
+	adminGroup.GET("/jobs", container.JobController.GetJobs)
+	adminGroup.GET("/jobs/:name/runs", container.JobController.GetRuns)
+	adminGroup.POST("/jobs/:name/run", container.JobController.Trigger)
+
+	adminGroup.GET("/queue/jobs", container.QueueController.GetJobs)
+	adminGroup.GET("/queue/jobs/:id", container.QueueController.GetJob)
+	adminGroup.POST("/queue/jobs/:id/retry", container.QueueController.Retry)
+	adminGroup.POST("/queue/jobs/:id/cancel", container.QueueController.Cancel)
+
+	adminGroup.GET("/moderation/messages", container.MessageModerationController.GetQueue)
+	adminGroup.POST("/moderation/messages/:id/dismiss", container.MessageModerationController.Dismiss)
+	adminGroup.POST("/moderation/messages/:id/delete", container.MessageModerationController.Delete)
+	adminGroup.POST("/moderation/messages/:id/warn", container.MessageModerationController.Warn)
+	adminGroup.POST("/moderation/messages/:id/suspend", container.MessageModerationController.Suspend)
+
 	adminGroup.GET("/orders", func(c echo.Context) error {
 		orders, err := container.OrderRepository.GetAll()
 		if err != nil {
@@ -266,9 +352,9 @@ func setupAdminRoutes(e *echo.Echo, container *container.Container, jwt string)
 	})
 }
 
-func setupSellerRoutes(e *echo.Echo, jwt string) {
+func setupSellerRoutes(e *echo.Echo, container *container.Container, jwt string) {
 	sellerGroup := e.Group("/seller")
-	sellerGroup.Use(middleware.JWTMiddleware(jwt))
+	sellerGroup.Use(middleware.JWTMiddleware(jwt, container.SessionService))
 	sellerGroup.Use(middleware.RequireRole("seller"))
 
 	sellerGroup.GET("/products", func(c echo.Context) error {
@@ -278,11 +364,52 @@ func setupSellerRoutes(e *echo.Echo, jwt string) {
 			"user_id": userID,
 		})
 	})
+	sellerGroup.POST("/products/import", container.ProductImportController.Import)
+	sellerGroup.GET("/products/import/:jobID", container.ProductImportController.GetImportJob)
+	sellerGroup.GET("/products/export", container.ProductImportController.Export)
+
+	sellerGroup.GET("/webhooks/event-types", container.WebhookController.GetEventTypes)
+	sellerGroup.GET("/webhooks", container.WebhookController.GetEndpoints)
+	sellerGroup.POST("/webhooks", container.WebhookController.CreateEndpoint)
+	sellerGroup.PUT("/webhooks/:id", container.WebhookController.UpdateEndpoint)
+	sellerGroup.DELETE("/webhooks/:id", container.WebhookController.DeleteEndpoint)
+	sellerGroup.POST("/webhooks/:id/rotate-secret", container.WebhookController.RotateSecret)
+	sellerGroup.POST("/webhooks/:id/test", container.WebhookController.SendTest)
+	sellerGroup.GET("/webhooks/:id/deliveries", container.WebhookController.GetDeliveries)
+	sellerGroup.POST("/webhooks/:id/deliveries/:deliveryID/redeliver", container.WebhookController.Redeliver)
 }
 
+const frontendDist = "frontend/dist"
+
+// serverPages are served by the API even when a browser navigates to them.
+var serverPages = []string{"/swagger/", "/sitemap.xml", "/sitemaps/", "/health", "/ws"}
+
 func setupStaticFiles(e *echo.Echo) {
-	e.Static("/assets", "frontend/dist/assets")
-	e.File("/", "frontend/dist/index.html")
+	e.Static("/assets", frontendDist+"/assets")
+	e.File("/", frontendDist+"/index.html")
+
+	// Client-side routes share paths with API endpoints (/products/:id, /categories/:slug), so
+	// browser navigations are told apart by the Accept header: they get a file from the build or
+	// the app, API calls reach the handlers.
+	e.Pre(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.Method != http.MethodGet || !strings.Contains(req.Header.Get("Accept"), "text/html") {
+				return next(c)
+			}
+			for _, prefix := range serverPages {
+				if strings.HasPrefix(req.URL.Path, prefix) {
+					return next(c)
+				}
+			}
+
+			file := filepath.Join(frontendDist, filepath.Clean("/"+req.URL.Path))
+			if info, err := os.Stat(file); err == nil && !info.IsDir() {
+				return c.File(file)
+			}
+			return c.File(frontendDist + "/index.html")
+		}
+	})
 }
 
 func setupErrorHandler(e *echo.Echo) {
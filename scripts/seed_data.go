@@ -7,6 +7,7 @@ import (
 	"MicroShopik/internal/repositories"
 	"golang.org/x/crypto/bcrypt"
 	"log"
+	"time"
 )
 
 // runSeedData runs the seed data function that can be called from main.go
@@ -23,21 +24,25 @@ func runSeedData(_ *configs.Config) error {
 		return err
 	}
 
+	verifiedAt := time.Now()
 	users := []domain.User{
 		{
-			Username: "admin",
-			Email:    "admin@microshopik.com",
-			Password: string(adminHash),
+			Username:        "admin",
+			Email:           "admin@microshopik.com",
+			Password:        string(adminHash),
+			EmailVerifiedAt: &verifiedAt,
 		},
 		{
-			Username: "seller1",
-			Email:    "seller1@microshopik.com",
-			Password: string(sellerHash),
+			Username:        "seller1",
+			Email:           "seller1@microshopik.com",
+			Password:        string(sellerHash),
+			EmailVerifiedAt: &verifiedAt,
 		},
 		{
-			Username: "buyer1",
-			Email:    "buyer1@microshopik.com",
-			Password: string(buyerHash),
+			Username:        "buyer1",
+			Email:           "buyer1@microshopik.com",
+			Password:        string(buyerHash),
+			EmailVerifiedAt: &verifiedAt,
 		},
 	}
 